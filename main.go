@@ -1,25 +1,59 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"flag"
+	"fmt"
+	"io/ioutil"
 	"log"
 	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	// the leaderboard timezone does not depend on the zoneinfo of the image
+	_ "time/tzdata"
 
 	_ "github.com/lib/pq"
 	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/authz"
 	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/connection"
 	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/jaeger_service"
 	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/service"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/token"
 	"google.golang.org/grpc"
 	"k8s.io/klog"
 )
 
 var port string
 var config string
+var sessionStore string
+var instance string
+var advertiseAddr string
+var leaseTTL time.Duration
+var snapshot string
+var heroUpdatePolicy string
+var tokenSecretFile string
+var tokenTTL time.Duration
+var leaderboardTimezone string
+var policyFile string
 
 func init() {
+	hostname, _ := os.Hostname()
+
 	flag.StringVar(&port, "port", "8001", "listen port")
 	flag.StringVar(&config, "config", "./config/config.json", "connection config file to postgresql")
+	flag.StringVar(&sessionStore, "session-store", service.MemoryStore, "session store backend, 'memory' or 'postgres'")
+	flag.StringVar(&instance, "instance", hostname, "instance name owning the sessions")
+	flag.StringVar(&advertiseAddr, "advertise-addr", "", "address handed to the other replicas to redirect requests (default \"<instance>:<port>\")")
+	flag.DurationVar(&leaseTTL, "lease-ttl", 0, "ttl of the session ownership leases, 0 disables the leases (single replica)")
+	flag.StringVar(&heroUpdatePolicy, "hero-update-policy", string(service.ApplyImmediately), "when the hero changes are applied to the live sessions, 'immediate', 'next-level' or 'never'")
+	flag.StringVar(&tokenSecretFile, "token-secret-file", "", "file of the secret signing the session tokens, shared by the replicas (default is a random secret)")
+	flag.DurationVar(&tokenTTL, "token-ttl", 24*time.Hour, "ttl of the session tokens")
+	flag.StringVar(&leaderboardTimezone, "leaderboard-timezone", "UTC", "timezone of the daily, weekly and season leaderboards, e.g. 'Asia/Shanghai'")
+	flag.StringVar(&policyFile, "policy", "./config/policy.json", "authorization policy file of the roles and the api keys, empty disables the authorization")
+	flag.StringVar(&snapshot, "snapshot", "", "snapshot file of the online sessions, dumped on shutdown and reloaded on startup")
 }
 
 func main() {
@@ -33,7 +67,19 @@ func main() {
 		klog.Fatal(err)
 	}
 
-	servOpts = append(servOpts, jaeger_service.ServerOption(tracer))
+	if policyFile == "" {
+		klog.Warning("authorization is disabled")
+		servOpts = append(servOpts, jaeger_service.ServerOption(tracer))
+	} else {
+		policy, err := authz.LoadPolicy(policyFile)
+		if err != nil {
+			klog.Fatal(err)
+		}
+		servOpts = append(servOpts,
+			grpc.ChainUnaryInterceptor(jaeger_service.UnaryServerInterceptor(tracer), policy.UnaryServerInterceptor()),
+			grpc.StreamInterceptor(policy.StreamServerInterceptor()),
+		)
+	}
 
 	// hold the connection to the pg
 	db, listener, err := connection.Create(config)
@@ -42,7 +88,50 @@ func main() {
 	}
 	defer db.Close()
 
-	svc := service.New(db, listener, tracer)
+	store, err := service.NewSessionStore(sessionStore, db)
+	if err != nil {
+		klog.Fatal(err)
+	}
+
+	policy, err := service.ParseHeroUpdatePolicy(heroUpdatePolicy)
+	if err != nil {
+		klog.Fatal(err)
+	}
+
+	secret, err := loadTokenSecret(tokenSecretFile)
+	if err != nil {
+		klog.Fatal(err)
+	}
+
+	location, err := time.LoadLocation(leaderboardTimezone)
+	if err != nil {
+		klog.Fatal(err)
+	}
+
+	var svcOpts = []service.Option{
+		service.WithHeroUpdatePolicy(policy),
+		service.WithTokens(token.NewIssuer(secret, tokenTTL)),
+		service.WithAnonymizeSecret(secret),
+		service.WithWindows(service.NewWindows(db, location)),
+	}
+	if leaseTTL > 0 {
+		if advertiseAddr == "" {
+			advertiseAddr = instance + ":" + port
+		}
+		svcOpts = append(svcOpts, service.WithLeases(service.NewLeaseManager(db, instance, advertiseAddr, leaseTTL)))
+	}
+
+	if snapshot != "" {
+		svcOpts = append(svcOpts, service.WithSnapshot(snapshot))
+	}
+
+	svc := service.New(db, listener, tracer, store, svcOpts...)
+
+	if snapshot != "" {
+		if err = svc.Restore(context.Background()); err != nil {
+			klog.Warning(err)
+		}
+	}
 
 	server := grpc.NewServer(servOpts...)
 
@@ -53,5 +142,43 @@ func main() {
 		log.Fatalf("net.Listen err: %v", err)
 	}
 
-	server.Serve(lis)
+	stopped := make(chan struct{})
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+		<-sig
+		klog.Info("shutting down")
+
+		// the streaming requests never end by themselves
+		timer := time.AfterFunc(10*time.Second, server.Stop)
+		server.GracefulStop()
+		timer.Stop()
+		close(stopped)
+	}()
+
+	if err = server.Serve(lis); err != nil {
+		klog.Fatal(err)
+	}
+	<-stopped
+
+	if err = svc.Close(); err != nil {
+		klog.Warning(err)
+	}
+}
+
+func loadTokenSecret(filename string) ([]byte, error) {
+	if filename == "" {
+		klog.Warning("no token secret file is given, the session tokens are valid for this instance only")
+		return token.NewSecret()
+	}
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	secret := bytes.TrimSpace(data)
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("token secret file '%s' is empty", filename)
+	}
+	return secret, nil
 }
@@ -7,6 +7,7 @@ import (
 	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	duration "github.com/golang/protobuf/ptypes/duration"
 	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
@@ -25,6 +26,34 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type GuildRole int32
+
+const (
+	GuildRole_MEMBER  GuildRole = 0
+	GuildRole_OFFICER GuildRole = 1
+	GuildRole_OWNER   GuildRole = 2
+)
+
+var GuildRole_name = map[int32]string{
+	0: "MEMBER",
+	1: "OFFICER",
+	2: "OWNER",
+}
+
+var GuildRole_value = map[string]int32{
+	"MEMBER":  0,
+	"OFFICER": 1,
+	"OWNER":   2,
+}
+
+func (x GuildRole) String() string {
+	return proto.EnumName(GuildRole_name, int32(x))
+}
+
+func (GuildRole) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{0}
+}
+
 type Type int32
 
 const (
@@ -53,24 +82,106 @@ func (x Type) String() string {
 }
 
 func (Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_475ae6b24dd70e2f, []int{0}
+	return fileDescriptor_475ae6b24dd70e2f, []int{1}
+}
+
+type Friend_Status int32
+
+const (
+	Friend_PENDING  Friend_Status = 0
+	Friend_ACCEPTED Friend_Status = 1
+	Friend_DECLINED Friend_Status = 2
+)
+
+var Friend_Status_name = map[int32]string{
+	0: "PENDING",
+	1: "ACCEPTED",
+	2: "DECLINED",
+}
+
+var Friend_Status_value = map[string]int32{
+	"PENDING":  0,
+	"ACCEPTED": 1,
+	"DECLINED": 2,
+}
+
+func (x Friend_Status) String() string {
+	return proto.EnumName(Friend_Status_name, int32(x))
+}
+
+func (Friend_Status) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{12, 0}
 }
 
 type AdminRequest_Type int32
 
 const (
 	AdminRequest_CREATE_HERO AdminRequest_Type = 0
+	// a balance patch of all the heros multiplying their attack and
+	// defense power by 1.2
 	AdminRequest_ADJUST_HERO AdminRequest_Type = 1
+	// dumps the online sessions to the snapshot file
+	AdminRequest_SNAPSHOT_SESSIONS AdminRequest_Type = 2
+	// updates the heros, fails for an undefined hero
+	AdminRequest_UPDATE_HERO AdminRequest_Type = 3
+	// deletes the heros by name, see migrate_to
+	AdminRequest_DELETE_HERO AdminRequest_Type = 4
+	// creates the heros, or updates the existing ones
+	AdminRequest_UPSERT_HERO AdminRequest_Type = 5
+	// inserts the bosses at their level, the following levels are moved
+	// down, or appends them after the last level if the level is 0
+	AdminRequest_CREATE_BOSS AdminRequest_Type = 6
+	// updates the bosses by name, the levels are set by REORDER_BOSSES
+	AdminRequest_UPDATE_BOSS AdminRequest_Type = 7
+	// deletes the bosses by name, the following levels are moved up
+	AdminRequest_DELETE_BOSS AdminRequest_Type = 8
+	// sets the levels of the bosses to their order in the request, every
+	// boss must be listed once
+	AdminRequest_REORDER_BOSSES AdminRequest_Type = 9
+	// applies the patch, or previews it with dry_run
+	AdminRequest_BALANCE_PATCH AdminRequest_Type = 10
+	// reverts the applied balance patch of patch_id
+	AdminRequest_REVERT_BALANCE_PATCH AdminRequest_Type = 11
+	// sends the changes of the tables and the actions only, all of
+	// them if empty, instead of all the changes
+	AdminRequest_SUBSCRIBE AdminRequest_Type = 12
+	// rebuilds the session_id from all its events, or the ones up to
+	// until_seq, under the current game rules, without changing it
+	AdminRequest_REPLAY_SESSION AdminRequest_Type = 13
 )
 
 var AdminRequest_Type_name = map[int32]string{
-	0: "CREATE_HERO",
-	1: "ADJUST_HERO",
+	0:  "CREATE_HERO",
+	1:  "ADJUST_HERO",
+	2:  "SNAPSHOT_SESSIONS",
+	3:  "UPDATE_HERO",
+	4:  "DELETE_HERO",
+	5:  "UPSERT_HERO",
+	6:  "CREATE_BOSS",
+	7:  "UPDATE_BOSS",
+	8:  "DELETE_BOSS",
+	9:  "REORDER_BOSSES",
+	10: "BALANCE_PATCH",
+	11: "REVERT_BALANCE_PATCH",
+	12: "SUBSCRIBE",
+	13: "REPLAY_SESSION",
 }
 
 var AdminRequest_Type_value = map[string]int32{
-	"CREATE_HERO": 0,
-	"ADJUST_HERO": 1,
+	"CREATE_HERO":          0,
+	"ADJUST_HERO":          1,
+	"SNAPSHOT_SESSIONS":    2,
+	"UPDATE_HERO":          3,
+	"DELETE_HERO":          4,
+	"UPSERT_HERO":          5,
+	"CREATE_BOSS":          6,
+	"UPDATE_BOSS":          7,
+	"DELETE_BOSS":          8,
+	"REORDER_BOSSES":       9,
+	"BALANCE_PATCH":        10,
+	"REVERT_BALANCE_PATCH": 11,
+	"SUBSCRIBE":            12,
+	"REPLAY_SESSION":       13,
 }
 
 func (x AdminRequest_Type) String() string {
@@ -78,1513 +189,5256 @@ func (x AdminRequest_Type) String() string {
 }
 
 func (AdminRequest_Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_475ae6b24dd70e2f, []int{2, 0}
+	return fileDescriptor_475ae6b24dd70e2f, []int{21, 0}
 }
 
-type ClearSessionRequest struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type BalancePatch_Stat int32
+
+const (
+	BalancePatch_ATTACK_POWER  BalancePatch_Stat = 0
+	BalancePatch_DEFENSE_POWER BalancePatch_Stat = 1
+	BalancePatch_BLOOD         BalancePatch_Stat = 2
+)
+
+var BalancePatch_Stat_name = map[int32]string{
+	0: "ATTACK_POWER",
+	1: "DEFENSE_POWER",
+	2: "BLOOD",
 }
 
-func (m *ClearSessionRequest) Reset()         { *m = ClearSessionRequest{} }
-func (m *ClearSessionRequest) String() string { return proto.CompactTextString(m) }
-func (*ClearSessionRequest) ProtoMessage()    {}
-func (*ClearSessionRequest) Descriptor() ([]byte, []int) {
+var BalancePatch_Stat_value = map[string]int32{
+	"ATTACK_POWER":  0,
+	"DEFENSE_POWER": 1,
+	"BLOOD":         2,
+}
+
+func (x BalancePatch_Stat) String() string {
+	return proto.EnumName(BalancePatch_Stat_name, int32(x))
+}
+
+func (BalancePatch_Stat) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{22, 0}
+}
+
+type BalancePatch_Mode int32
+
+const (
+	// sets the stat to the value
+	BalancePatch_SET BalancePatch_Mode = 0
+	// adds the value to the stat
+	BalancePatch_ADD BalancePatch_Mode = 1
+	// multiplies the stat by the value, rounded to the nearest integer
+	BalancePatch_MULTIPLY BalancePatch_Mode = 2
+)
+
+var BalancePatch_Mode_name = map[int32]string{
+	0: "SET",
+	1: "ADD",
+	2: "MULTIPLY",
+}
+
+var BalancePatch_Mode_value = map[string]int32{
+	"SET":      0,
+	"ADD":      1,
+	"MULTIPLY": 2,
+}
+
+func (x BalancePatch_Mode) String() string {
+	return proto.EnumName(BalancePatch_Mode_name, int32(x))
+}
+
+func (BalancePatch_Mode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{22, 1}
+}
+
+type LeaderboardRequest_Window int32
+
+const (
+	// the online sessions
+	LeaderboardRequest_PLAYING_NOW LeaderboardRequest_Window = 0
+	// the best score of every player
+	LeaderboardRequest_ALL_TIME LeaderboardRequest_Window = 1
+	// the scores earned in the day, the week or the season (quarter),
+	// in the timezone of the server
+	LeaderboardRequest_DAILY  LeaderboardRequest_Window = 2
+	LeaderboardRequest_WEEKLY LeaderboardRequest_Window = 3
+	LeaderboardRequest_SEASON LeaderboardRequest_Window = 4
+)
+
+var LeaderboardRequest_Window_name = map[int32]string{
+	0: "PLAYING_NOW",
+	1: "ALL_TIME",
+	2: "DAILY",
+	3: "WEEKLY",
+	4: "SEASON",
+}
+
+var LeaderboardRequest_Window_value = map[string]int32{
+	"PLAYING_NOW": 0,
+	"ALL_TIME":    1,
+	"DAILY":       2,
+	"WEEKLY":      3,
+	"SEASON":      4,
+}
+
+func (x LeaderboardRequest_Window) String() string {
+	return proto.EnumName(LeaderboardRequest_Window_name, int32(x))
+}
+
+func (LeaderboardRequest_Window) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{31, 0}
+}
+
+type LeaderboardDelta_Type int32
+
+const (
+	// the player enters the watched part
+	LeaderboardDelta_INSERT LeaderboardDelta_Type = 0
+	// the player leaves the watched part
+	LeaderboardDelta_REMOVE LeaderboardDelta_Type = 1
+	// the rank, the score, the level or the display name of the player
+	// changes
+	LeaderboardDelta_MOVE LeaderboardDelta_Type = 2
+)
+
+var LeaderboardDelta_Type_name = map[int32]string{
+	0: "INSERT",
+	1: "REMOVE",
+	2: "MOVE",
+}
+
+var LeaderboardDelta_Type_value = map[string]int32{
+	"INSERT": 0,
+	"REMOVE": 1,
+	"MOVE":   2,
+}
+
+func (x LeaderboardDelta_Type) String() string {
+	return proto.EnumName(LeaderboardDelta_Type_name, int32(x))
+}
+
+func (LeaderboardDelta_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{33, 0}
+}
+
+type Guild struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// the sum of the best scores of the members
+	Score                int32                `protobuf:"varint,2,opt,name=score,proto3" json:"score,omitempty"`
+	Members              []*GuildMember       `protobuf:"bytes,3,rep,name=members,proto3" json:"members,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Guild) Reset()         { *m = Guild{} }
+func (m *Guild) String() string { return proto.CompactTextString(m) }
+func (*Guild) ProtoMessage()    {}
+func (*Guild) Descriptor() ([]byte, []int) {
 	return fileDescriptor_475ae6b24dd70e2f, []int{0}
 }
 
-func (m *ClearSessionRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ClearSessionRequest.Unmarshal(m, b)
+func (m *Guild) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Guild.Unmarshal(m, b)
 }
-func (m *ClearSessionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ClearSessionRequest.Marshal(b, m, deterministic)
+func (m *Guild) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Guild.Marshal(b, m, deterministic)
 }
-func (m *ClearSessionRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ClearSessionRequest.Merge(m, src)
+func (m *Guild) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Guild.Merge(m, src)
 }
-func (m *ClearSessionRequest) XXX_Size() int {
-	return xxx_messageInfo_ClearSessionRequest.Size(m)
+func (m *Guild) XXX_Size() int {
+	return xxx_messageInfo_Guild.Size(m)
 }
-func (m *ClearSessionRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ClearSessionRequest.DiscardUnknown(m)
+func (m *Guild) XXX_DiscardUnknown() {
+	xxx_messageInfo_Guild.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ClearSessionRequest proto.InternalMessageInfo
+var xxx_messageInfo_Guild proto.InternalMessageInfo
 
-func (m *ClearSessionRequest) GetId() string {
+func (m *Guild) GetName() string {
 	if m != nil {
-		return m.Id
+		return m.Name
 	}
 	return ""
 }
 
-type ClearSessionResponse struct {
-	Msg                  string   `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *Guild) GetScore() int32 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
 }
 
-func (m *ClearSessionResponse) Reset()         { *m = ClearSessionResponse{} }
-func (m *ClearSessionResponse) String() string { return proto.CompactTextString(m) }
-func (*ClearSessionResponse) ProtoMessage()    {}
-func (*ClearSessionResponse) Descriptor() ([]byte, []int) {
+func (m *Guild) GetMembers() []*GuildMember {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+func (m *Guild) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+type GuildMember struct {
+	DisplayName          string    `protobuf:"bytes,1,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Role                 GuildRole `protobuf:"varint,2,opt,name=role,proto3,enum=fight.GuildRole" json:"role,omitempty"`
+	BestScore            int32     `protobuf:"varint,3,opt,name=best_score,json=bestScore,proto3" json:"best_score,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *GuildMember) Reset()         { *m = GuildMember{} }
+func (m *GuildMember) String() string { return proto.CompactTextString(m) }
+func (*GuildMember) ProtoMessage()    {}
+func (*GuildMember) Descriptor() ([]byte, []int) {
 	return fileDescriptor_475ae6b24dd70e2f, []int{1}
 }
 
-func (m *ClearSessionResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ClearSessionResponse.Unmarshal(m, b)
+func (m *GuildMember) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GuildMember.Unmarshal(m, b)
 }
-func (m *ClearSessionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ClearSessionResponse.Marshal(b, m, deterministic)
+func (m *GuildMember) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GuildMember.Marshal(b, m, deterministic)
 }
-func (m *ClearSessionResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ClearSessionResponse.Merge(m, src)
+func (m *GuildMember) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GuildMember.Merge(m, src)
 }
-func (m *ClearSessionResponse) XXX_Size() int {
-	return xxx_messageInfo_ClearSessionResponse.Size(m)
+func (m *GuildMember) XXX_Size() int {
+	return xxx_messageInfo_GuildMember.Size(m)
 }
-func (m *ClearSessionResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_ClearSessionResponse.DiscardUnknown(m)
+func (m *GuildMember) XXX_DiscardUnknown() {
+	xxx_messageInfo_GuildMember.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ClearSessionResponse proto.InternalMessageInfo
+var xxx_messageInfo_GuildMember proto.InternalMessageInfo
 
-func (m *ClearSessionResponse) GetMsg() string {
+func (m *GuildMember) GetDisplayName() string {
 	if m != nil {
-		return m.Msg
+		return m.DisplayName
 	}
 	return ""
 }
 
-type AdminRequest struct {
-	Heros                []*Hero           `protobuf:"bytes,1,rep,name=heros,proto3" json:"heros,omitempty"`
-	Type                 AdminRequest_Type `protobuf:"varint,2,opt,name=type,proto3,enum=fight.AdminRequest_Type" json:"type,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+func (m *GuildMember) GetRole() GuildRole {
+	if m != nil {
+		return m.Role
+	}
+	return GuildRole_MEMBER
 }
 
-func (m *AdminRequest) Reset()         { *m = AdminRequest{} }
-func (m *AdminRequest) String() string { return proto.CompactTextString(m) }
-func (*AdminRequest) ProtoMessage()    {}
-func (*AdminRequest) Descriptor() ([]byte, []int) {
+func (m *GuildMember) GetBestScore() int32 {
+	if m != nil {
+		return m.BestScore
+	}
+	return 0
+}
+
+type CreateGuildRequest struct {
+	// the session id of the owner
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// 3 to 20 letters, digits, spaces, '_' or '-', unique regardless of the case
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateGuildRequest) Reset()         { *m = CreateGuildRequest{} }
+func (m *CreateGuildRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateGuildRequest) ProtoMessage()    {}
+func (*CreateGuildRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_475ae6b24dd70e2f, []int{2}
 }
 
-func (m *AdminRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_AdminRequest.Unmarshal(m, b)
+func (m *CreateGuildRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateGuildRequest.Unmarshal(m, b)
 }
-func (m *AdminRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_AdminRequest.Marshal(b, m, deterministic)
+func (m *CreateGuildRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateGuildRequest.Marshal(b, m, deterministic)
 }
-func (m *AdminRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_AdminRequest.Merge(m, src)
+func (m *CreateGuildRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateGuildRequest.Merge(m, src)
 }
-func (m *AdminRequest) XXX_Size() int {
-	return xxx_messageInfo_AdminRequest.Size(m)
+func (m *CreateGuildRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateGuildRequest.Size(m)
 }
-func (m *AdminRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_AdminRequest.DiscardUnknown(m)
+func (m *CreateGuildRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateGuildRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_AdminRequest proto.InternalMessageInfo
+var xxx_messageInfo_CreateGuildRequest proto.InternalMessageInfo
 
-func (m *AdminRequest) GetHeros() []*Hero {
+func (m *CreateGuildRequest) GetId() string {
 	if m != nil {
-		return m.Heros
+		return m.Id
 	}
-	return nil
+	return ""
 }
 
-func (m *AdminRequest) GetType() AdminRequest_Type {
+func (m *CreateGuildRequest) GetName() string {
 	if m != nil {
-		return m.Type
+		return m.Name
 	}
-	return AdminRequest_CREATE_HERO
+	return ""
 }
 
-type AdminResponse struct {
-	Heros                []*Hero  `protobuf:"bytes,1,rep,name=heros,proto3" json:"heros,omitempty"`
+type GetGuildRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *AdminResponse) Reset()         { *m = AdminResponse{} }
-func (m *AdminResponse) String() string { return proto.CompactTextString(m) }
-func (*AdminResponse) ProtoMessage()    {}
-func (*AdminResponse) Descriptor() ([]byte, []int) {
+func (m *GetGuildRequest) Reset()         { *m = GetGuildRequest{} }
+func (m *GetGuildRequest) String() string { return proto.CompactTextString(m) }
+func (*GetGuildRequest) ProtoMessage()    {}
+func (*GetGuildRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_475ae6b24dd70e2f, []int{3}
 }
 
-func (m *AdminResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_AdminResponse.Unmarshal(m, b)
+func (m *GetGuildRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetGuildRequest.Unmarshal(m, b)
 }
-func (m *AdminResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_AdminResponse.Marshal(b, m, deterministic)
+func (m *GetGuildRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetGuildRequest.Marshal(b, m, deterministic)
 }
-func (m *AdminResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_AdminResponse.Merge(m, src)
+func (m *GetGuildRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetGuildRequest.Merge(m, src)
 }
-func (m *AdminResponse) XXX_Size() int {
-	return xxx_messageInfo_AdminResponse.Size(m)
+func (m *GetGuildRequest) XXX_Size() int {
+	return xxx_messageInfo_GetGuildRequest.Size(m)
 }
-func (m *AdminResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_AdminResponse.DiscardUnknown(m)
+func (m *GetGuildRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetGuildRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_AdminResponse proto.InternalMessageInfo
+var xxx_messageInfo_GetGuildRequest proto.InternalMessageInfo
 
-func (m *AdminResponse) GetHeros() []*Hero {
+func (m *GetGuildRequest) GetName() string {
 	if m != nil {
-		return m.Heros
+		return m.Name
 	}
-	return nil
+	return ""
 }
 
-type Top10Request struct {
+type JoinGuildRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Top10Request) Reset()         { *m = Top10Request{} }
-func (m *Top10Request) String() string { return proto.CompactTextString(m) }
-func (*Top10Request) ProtoMessage()    {}
-func (*Top10Request) Descriptor() ([]byte, []int) {
+func (m *JoinGuildRequest) Reset()         { *m = JoinGuildRequest{} }
+func (m *JoinGuildRequest) String() string { return proto.CompactTextString(m) }
+func (*JoinGuildRequest) ProtoMessage()    {}
+func (*JoinGuildRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_475ae6b24dd70e2f, []int{4}
 }
 
-func (m *Top10Request) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Top10Request.Unmarshal(m, b)
+func (m *JoinGuildRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JoinGuildRequest.Unmarshal(m, b)
 }
-func (m *Top10Request) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Top10Request.Marshal(b, m, deterministic)
+func (m *JoinGuildRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JoinGuildRequest.Marshal(b, m, deterministic)
 }
-func (m *Top10Request) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Top10Request.Merge(m, src)
+func (m *JoinGuildRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JoinGuildRequest.Merge(m, src)
 }
-func (m *Top10Request) XXX_Size() int {
-	return xxx_messageInfo_Top10Request.Size(m)
+func (m *JoinGuildRequest) XXX_Size() int {
+	return xxx_messageInfo_JoinGuildRequest.Size(m)
 }
-func (m *Top10Request) XXX_DiscardUnknown() {
-	xxx_messageInfo_Top10Request.DiscardUnknown(m)
+func (m *JoinGuildRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_JoinGuildRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Top10Request proto.InternalMessageInfo
+var xxx_messageInfo_JoinGuildRequest proto.InternalMessageInfo
 
-type Top10Response struct {
-	Players              []*Top10Response_Player `protobuf:"bytes,1,rep,name=players,proto3" json:"players,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
-	XXX_unrecognized     []byte                  `json:"-"`
-	XXX_sizecache        int32                   `json:"-"`
+func (m *JoinGuildRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
 }
 
-func (m *Top10Response) Reset()         { *m = Top10Response{} }
-func (m *Top10Response) String() string { return proto.CompactTextString(m) }
-func (*Top10Response) ProtoMessage()    {}
-func (*Top10Response) Descriptor() ([]byte, []int) {
+func (m *JoinGuildRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type LeaveGuildRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LeaveGuildRequest) Reset()         { *m = LeaveGuildRequest{} }
+func (m *LeaveGuildRequest) String() string { return proto.CompactTextString(m) }
+func (*LeaveGuildRequest) ProtoMessage()    {}
+func (*LeaveGuildRequest) Descriptor() ([]byte, []int) {
 	return fileDescriptor_475ae6b24dd70e2f, []int{5}
 }
 
-func (m *Top10Response) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Top10Response.Unmarshal(m, b)
+func (m *LeaveGuildRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LeaveGuildRequest.Unmarshal(m, b)
 }
-func (m *Top10Response) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Top10Response.Marshal(b, m, deterministic)
+func (m *LeaveGuildRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LeaveGuildRequest.Marshal(b, m, deterministic)
 }
-func (m *Top10Response) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Top10Response.Merge(m, src)
+func (m *LeaveGuildRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LeaveGuildRequest.Merge(m, src)
 }
-func (m *Top10Response) XXX_Size() int {
-	return xxx_messageInfo_Top10Response.Size(m)
+func (m *LeaveGuildRequest) XXX_Size() int {
+	return xxx_messageInfo_LeaveGuildRequest.Size(m)
 }
-func (m *Top10Response) XXX_DiscardUnknown() {
-	xxx_messageInfo_Top10Response.DiscardUnknown(m)
+func (m *LeaveGuildRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LeaveGuildRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Top10Response proto.InternalMessageInfo
+var xxx_messageInfo_LeaveGuildRequest proto.InternalMessageInfo
 
-func (m *Top10Response) GetPlayers() []*Top10Response_Player {
+func (m *LeaveGuildRequest) GetId() string {
 	if m != nil {
-		return m.Players
+		return m.Id
 	}
-	return nil
+	return ""
 }
 
-type Top10Response_Player struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Score                int32    `protobuf:"varint,2,opt,name=score,proto3" json:"score,omitempty"`
-	Level                int32    `protobuf:"varint,3,opt,name=level,proto3" json:"level,omitempty"`
+type LeaveGuildResponse struct {
+	Msg                  string   `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Top10Response_Player) Reset()         { *m = Top10Response_Player{} }
-func (m *Top10Response_Player) String() string { return proto.CompactTextString(m) }
-func (*Top10Response_Player) ProtoMessage()    {}
-func (*Top10Response_Player) Descriptor() ([]byte, []int) {
-	return fileDescriptor_475ae6b24dd70e2f, []int{5, 0}
+func (m *LeaveGuildResponse) Reset()         { *m = LeaveGuildResponse{} }
+func (m *LeaveGuildResponse) String() string { return proto.CompactTextString(m) }
+func (*LeaveGuildResponse) ProtoMessage()    {}
+func (*LeaveGuildResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{6}
 }
 
-func (m *Top10Response_Player) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Top10Response_Player.Unmarshal(m, b)
+func (m *LeaveGuildResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LeaveGuildResponse.Unmarshal(m, b)
 }
-func (m *Top10Response_Player) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Top10Response_Player.Marshal(b, m, deterministic)
+func (m *LeaveGuildResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LeaveGuildResponse.Marshal(b, m, deterministic)
 }
-func (m *Top10Response_Player) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Top10Response_Player.Merge(m, src)
+func (m *LeaveGuildResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LeaveGuildResponse.Merge(m, src)
 }
-func (m *Top10Response_Player) XXX_Size() int {
-	return xxx_messageInfo_Top10Response_Player.Size(m)
+func (m *LeaveGuildResponse) XXX_Size() int {
+	return xxx_messageInfo_LeaveGuildResponse.Size(m)
 }
-func (m *Top10Response_Player) XXX_DiscardUnknown() {
-	xxx_messageInfo_Top10Response_Player.DiscardUnknown(m)
+func (m *LeaveGuildResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LeaveGuildResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Top10Response_Player proto.InternalMessageInfo
+var xxx_messageInfo_LeaveGuildResponse proto.InternalMessageInfo
 
-func (m *Top10Response_Player) GetId() string {
+func (m *LeaveGuildResponse) GetMsg() string {
+	if m != nil {
+		return m.Msg
+	}
+	return ""
+}
+
+type GuildMemberRequest struct {
+	// the session id of the owner or the officer
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// the display name of the member
+	DisplayName string `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// the new role for SetGuildRole
+	Role                 GuildRole `protobuf:"varint,3,opt,name=role,proto3,enum=fight.GuildRole" json:"role,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *GuildMemberRequest) Reset()         { *m = GuildMemberRequest{} }
+func (m *GuildMemberRequest) String() string { return proto.CompactTextString(m) }
+func (*GuildMemberRequest) ProtoMessage()    {}
+func (*GuildMemberRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{7}
+}
+
+func (m *GuildMemberRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GuildMemberRequest.Unmarshal(m, b)
+}
+func (m *GuildMemberRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GuildMemberRequest.Marshal(b, m, deterministic)
+}
+func (m *GuildMemberRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GuildMemberRequest.Merge(m, src)
+}
+func (m *GuildMemberRequest) XXX_Size() int {
+	return xxx_messageInfo_GuildMemberRequest.Size(m)
+}
+func (m *GuildMemberRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GuildMemberRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GuildMemberRequest proto.InternalMessageInfo
+
+func (m *GuildMemberRequest) GetId() string {
 	if m != nil {
 		return m.Id
 	}
 	return ""
 }
 
-func (m *Top10Response_Player) GetScore() int32 {
+func (m *GuildMemberRequest) GetDisplayName() string {
 	if m != nil {
-		return m.Score
+		return m.DisplayName
 	}
-	return 0
+	return ""
 }
 
-func (m *Top10Response_Player) GetLevel() int32 {
+func (m *GuildMemberRequest) GetRole() GuildRole {
 	if m != nil {
-		return m.Level
+		return m.Role
 	}
-	return 0
+	return GuildRole_MEMBER
 }
 
-type GameRequest struct {
-	Type                 Type     `protobuf:"varint,1,opt,name=type,proto3,enum=fight.Type" json:"type,omitempty"`
-	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+type GuildLeaderboardRequest struct {
+	Limit                int32    `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset               int32    `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *GameRequest) Reset()         { *m = GameRequest{} }
-func (m *GameRequest) String() string { return proto.CompactTextString(m) }
-func (*GameRequest) ProtoMessage()    {}
-func (*GameRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_475ae6b24dd70e2f, []int{6}
+func (m *GuildLeaderboardRequest) Reset()         { *m = GuildLeaderboardRequest{} }
+func (m *GuildLeaderboardRequest) String() string { return proto.CompactTextString(m) }
+func (*GuildLeaderboardRequest) ProtoMessage()    {}
+func (*GuildLeaderboardRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{8}
 }
 
-func (m *GameRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GameRequest.Unmarshal(m, b)
+func (m *GuildLeaderboardRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GuildLeaderboardRequest.Unmarshal(m, b)
 }
-func (m *GameRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GameRequest.Marshal(b, m, deterministic)
+func (m *GuildLeaderboardRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GuildLeaderboardRequest.Marshal(b, m, deterministic)
 }
-func (m *GameRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GameRequest.Merge(m, src)
+func (m *GuildLeaderboardRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GuildLeaderboardRequest.Merge(m, src)
 }
-func (m *GameRequest) XXX_Size() int {
-	return xxx_messageInfo_GameRequest.Size(m)
+func (m *GuildLeaderboardRequest) XXX_Size() int {
+	return xxx_messageInfo_GuildLeaderboardRequest.Size(m)
 }
-func (m *GameRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_GameRequest.DiscardUnknown(m)
+func (m *GuildLeaderboardRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GuildLeaderboardRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GameRequest proto.InternalMessageInfo
+var xxx_messageInfo_GuildLeaderboardRequest proto.InternalMessageInfo
 
-func (m *GameRequest) GetType() Type {
+func (m *GuildLeaderboardRequest) GetLimit() int32 {
 	if m != nil {
-		return m.Type
+		return m.Limit
 	}
-	return Type_FIGHT
+	return 0
 }
 
-func (m *GameRequest) GetId() string {
+func (m *GuildLeaderboardRequest) GetOffset() int32 {
 	if m != nil {
-		return m.Id
+		return m.Offset
 	}
-	return ""
+	return 0
 }
 
-type GameResponse struct {
-	Type Type `protobuf:"varint,1,opt,name=type,proto3,enum=fight.Type" json:"type,omitempty"`
-	// Types that are valid to be assigned to Value:
-	//	*GameResponse_Fight
-	//	*GameResponse_Archive
-	//	*GameResponse_Level
-	//	*GameResponse_Quit
-	Value                isGameResponse_Value `protobuf_oneof:"value"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+type GuildLeaderboardResponse struct {
+	Entries              []*GuildLeaderboardResponse_Entry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	Total                int32                             `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                          `json:"-"`
+	XXX_unrecognized     []byte                            `json:"-"`
+	XXX_sizecache        int32                             `json:"-"`
 }
 
-func (m *GameResponse) Reset()         { *m = GameResponse{} }
-func (m *GameResponse) String() string { return proto.CompactTextString(m) }
-func (*GameResponse) ProtoMessage()    {}
-func (*GameResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_475ae6b24dd70e2f, []int{7}
+func (m *GuildLeaderboardResponse) Reset()         { *m = GuildLeaderboardResponse{} }
+func (m *GuildLeaderboardResponse) String() string { return proto.CompactTextString(m) }
+func (*GuildLeaderboardResponse) ProtoMessage()    {}
+func (*GuildLeaderboardResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{9}
 }
 
-func (m *GameResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GameResponse.Unmarshal(m, b)
+func (m *GuildLeaderboardResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GuildLeaderboardResponse.Unmarshal(m, b)
 }
-func (m *GameResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GameResponse.Marshal(b, m, deterministic)
+func (m *GuildLeaderboardResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GuildLeaderboardResponse.Marshal(b, m, deterministic)
 }
-func (m *GameResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GameResponse.Merge(m, src)
+func (m *GuildLeaderboardResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GuildLeaderboardResponse.Merge(m, src)
 }
-func (m *GameResponse) XXX_Size() int {
-	return xxx_messageInfo_GameResponse.Size(m)
+func (m *GuildLeaderboardResponse) XXX_Size() int {
+	return xxx_messageInfo_GuildLeaderboardResponse.Size(m)
 }
-func (m *GameResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_GameResponse.DiscardUnknown(m)
+func (m *GuildLeaderboardResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GuildLeaderboardResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GameResponse proto.InternalMessageInfo
+var xxx_messageInfo_GuildLeaderboardResponse proto.InternalMessageInfo
 
-func (m *GameResponse) GetType() Type {
+func (m *GuildLeaderboardResponse) GetEntries() []*GuildLeaderboardResponse_Entry {
 	if m != nil {
-		return m.Type
+		return m.Entries
 	}
-	return Type_FIGHT
+	return nil
 }
 
-type isGameResponse_Value interface {
-	isGameResponse_Value()
+func (m *GuildLeaderboardResponse) GetTotal() int32 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
 }
 
-type GameResponse_Fight struct {
-	Fight *Fight `protobuf:"bytes,2,opt,name=fight,proto3,oneof"`
+type GuildLeaderboardResponse_Entry struct {
+	Rank                 int32    `protobuf:"varint,1,opt,name=rank,proto3" json:"rank,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Score                int32    `protobuf:"varint,3,opt,name=score,proto3" json:"score,omitempty"`
+	Members              int32    `protobuf:"varint,4,opt,name=members,proto3" json:"members,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-type GameResponse_Archive struct {
-	Archive *Archive `protobuf:"bytes,3,opt,name=archive,proto3,oneof"`
+func (m *GuildLeaderboardResponse_Entry) Reset()         { *m = GuildLeaderboardResponse_Entry{} }
+func (m *GuildLeaderboardResponse_Entry) String() string { return proto.CompactTextString(m) }
+func (*GuildLeaderboardResponse_Entry) ProtoMessage()    {}
+func (*GuildLeaderboardResponse_Entry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{9, 0}
 }
 
-type GameResponse_Level struct {
-	Level *Level `protobuf:"bytes,4,opt,name=level,proto3,oneof"`
+func (m *GuildLeaderboardResponse_Entry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GuildLeaderboardResponse_Entry.Unmarshal(m, b)
 }
-
-type GameResponse_Quit struct {
-	Quit *Quit `protobuf:"bytes,5,opt,name=quit,proto3,oneof"`
+func (m *GuildLeaderboardResponse_Entry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GuildLeaderboardResponse_Entry.Marshal(b, m, deterministic)
 }
-
-func (*GameResponse_Fight) isGameResponse_Value() {}
-
-func (*GameResponse_Archive) isGameResponse_Value() {}
-
-func (*GameResponse_Level) isGameResponse_Value() {}
-
-func (*GameResponse_Quit) isGameResponse_Value() {}
-
-func (m *GameResponse) GetValue() isGameResponse_Value {
-	if m != nil {
-		return m.Value
-	}
-	return nil
+func (m *GuildLeaderboardResponse_Entry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GuildLeaderboardResponse_Entry.Merge(m, src)
 }
-
-func (m *GameResponse) GetFight() *Fight {
-	if x, ok := m.GetValue().(*GameResponse_Fight); ok {
-		return x.Fight
-	}
-	return nil
+func (m *GuildLeaderboardResponse_Entry) XXX_Size() int {
+	return xxx_messageInfo_GuildLeaderboardResponse_Entry.Size(m)
+}
+func (m *GuildLeaderboardResponse_Entry) XXX_DiscardUnknown() {
+	xxx_messageInfo_GuildLeaderboardResponse_Entry.DiscardUnknown(m)
 }
 
-func (m *GameResponse) GetArchive() *Archive {
-	if x, ok := m.GetValue().(*GameResponse_Archive); ok {
-		return x.Archive
+var xxx_messageInfo_GuildLeaderboardResponse_Entry proto.InternalMessageInfo
+
+func (m *GuildLeaderboardResponse_Entry) GetRank() int32 {
+	if m != nil {
+		return m.Rank
 	}
-	return nil
+	return 0
 }
 
-func (m *GameResponse) GetLevel() *Level {
-	if x, ok := m.GetValue().(*GameResponse_Level); ok {
-		return x.Level
+func (m *GuildLeaderboardResponse_Entry) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	return nil
+	return ""
 }
 
-func (m *GameResponse) GetQuit() *Quit {
-	if x, ok := m.GetValue().(*GameResponse_Quit); ok {
-		return x.Quit
+func (m *GuildLeaderboardResponse_Entry) GetScore() int32 {
+	if m != nil {
+		return m.Score
 	}
-	return nil
+	return 0
 }
 
-// XXX_OneofWrappers is for the internal use of the proto package.
-func (*GameResponse) XXX_OneofWrappers() []interface{} {
-	return []interface{}{
-		(*GameResponse_Fight)(nil),
-		(*GameResponse_Archive)(nil),
-		(*GameResponse_Level)(nil),
-		(*GameResponse_Quit)(nil),
+func (m *GuildLeaderboardResponse_Entry) GetMembers() int32 {
+	if m != nil {
+		return m.Members
 	}
+	return 0
 }
 
-type Fight struct {
-	GameOver             bool     `protobuf:"varint,1,opt,name=game_over,json=gameOver,proto3" json:"game_over,omitempty"`
-	NextLevel            bool     `protobuf:"varint,2,opt,name=next_level,json=nextLevel,proto3" json:"next_level,omitempty"`
-	Score                int32    `protobuf:"varint,3,opt,name=score,proto3" json:"score,omitempty"`
-	HeroBlood            int32    `protobuf:"varint,4,opt,name=hero_blood,json=heroBlood,proto3" json:"hero_blood,omitempty"`
-	BossBlood            int32    `protobuf:"varint,5,opt,name=boss_blood,json=bossBlood,proto3" json:"boss_blood,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type Profile struct {
+	// the session id
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// 3 to 20 letters, digits, spaces, '_' or '-', unique regardless of the case
+	DisplayName string `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// the key of the avatar image
+	AvatarKey            string               `protobuf:"bytes,3,opt,name=avatar_key,json=avatarKey,proto3" json:"avatar_key,omitempty"`
+	CreatedAt            *timestamp.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
-func (m *Fight) Reset()         { *m = Fight{} }
-func (m *Fight) String() string { return proto.CompactTextString(m) }
-func (*Fight) ProtoMessage()    {}
-func (*Fight) Descriptor() ([]byte, []int) {
-	return fileDescriptor_475ae6b24dd70e2f, []int{8}
+func (m *Profile) Reset()         { *m = Profile{} }
+func (m *Profile) String() string { return proto.CompactTextString(m) }
+func (*Profile) ProtoMessage()    {}
+func (*Profile) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{10}
 }
 
-func (m *Fight) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Fight.Unmarshal(m, b)
+func (m *Profile) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Profile.Unmarshal(m, b)
 }
-func (m *Fight) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Fight.Marshal(b, m, deterministic)
+func (m *Profile) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Profile.Marshal(b, m, deterministic)
 }
-func (m *Fight) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Fight.Merge(m, src)
+func (m *Profile) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Profile.Merge(m, src)
 }
-func (m *Fight) XXX_Size() int {
-	return xxx_messageInfo_Fight.Size(m)
+func (m *Profile) XXX_Size() int {
+	return xxx_messageInfo_Profile.Size(m)
 }
-func (m *Fight) XXX_DiscardUnknown() {
-	xxx_messageInfo_Fight.DiscardUnknown(m)
+func (m *Profile) XXX_DiscardUnknown() {
+	xxx_messageInfo_Profile.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Fight proto.InternalMessageInfo
-
-func (m *Fight) GetGameOver() bool {
-	if m != nil {
-		return m.GameOver
-	}
-	return false
-}
+var xxx_messageInfo_Profile proto.InternalMessageInfo
 
-func (m *Fight) GetNextLevel() bool {
+func (m *Profile) GetId() string {
 	if m != nil {
-		return m.NextLevel
+		return m.Id
 	}
-	return false
+	return ""
 }
 
-func (m *Fight) GetScore() int32 {
+func (m *Profile) GetDisplayName() string {
 	if m != nil {
-		return m.Score
+		return m.DisplayName
 	}
-	return 0
+	return ""
 }
 
-func (m *Fight) GetHeroBlood() int32 {
+func (m *Profile) GetAvatarKey() string {
 	if m != nil {
-		return m.HeroBlood
+		return m.AvatarKey
 	}
-	return 0
+	return ""
 }
 
-func (m *Fight) GetBossBlood() int32 {
+func (m *Profile) GetCreatedAt() *timestamp.Timestamp {
 	if m != nil {
-		return m.BossBlood
+		return m.CreatedAt
 	}
-	return 0
+	return nil
 }
 
-type Archive struct {
-	Msg                  string   `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
-	SessionId            string   `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+type FriendRequest struct {
+	// the session id of the player
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// the display name of the friend
+	DisplayName          string   `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Archive) Reset()         { *m = Archive{} }
-func (m *Archive) String() string { return proto.CompactTextString(m) }
-func (*Archive) ProtoMessage()    {}
-func (*Archive) Descriptor() ([]byte, []int) {
-	return fileDescriptor_475ae6b24dd70e2f, []int{9}
+func (m *FriendRequest) Reset()         { *m = FriendRequest{} }
+func (m *FriendRequest) String() string { return proto.CompactTextString(m) }
+func (*FriendRequest) ProtoMessage()    {}
+func (*FriendRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{11}
 }
 
-func (m *Archive) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Archive.Unmarshal(m, b)
+func (m *FriendRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FriendRequest.Unmarshal(m, b)
 }
-func (m *Archive) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Archive.Marshal(b, m, deterministic)
+func (m *FriendRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FriendRequest.Marshal(b, m, deterministic)
 }
-func (m *Archive) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Archive.Merge(m, src)
+func (m *FriendRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FriendRequest.Merge(m, src)
 }
-func (m *Archive) XXX_Size() int {
-	return xxx_messageInfo_Archive.Size(m)
+func (m *FriendRequest) XXX_Size() int {
+	return xxx_messageInfo_FriendRequest.Size(m)
 }
-func (m *Archive) XXX_DiscardUnknown() {
-	xxx_messageInfo_Archive.DiscardUnknown(m)
+func (m *FriendRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FriendRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Archive proto.InternalMessageInfo
+var xxx_messageInfo_FriendRequest proto.InternalMessageInfo
 
-func (m *Archive) GetMsg() string {
+func (m *FriendRequest) GetId() string {
 	if m != nil {
-		return m.Msg
+		return m.Id
 	}
 	return ""
 }
 
-func (m *Archive) GetSessionId() string {
+func (m *FriendRequest) GetDisplayName() string {
 	if m != nil {
-		return m.SessionId
+		return m.DisplayName
 	}
 	return ""
 }
 
-type Level struct {
-	Msg                  string   `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
-	Session              *Session `protobuf:"bytes,2,opt,name=session,proto3" json:"session,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type Friend struct {
+	DisplayName string        `protobuf:"bytes,1,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Status      Friend_Status `protobuf:"varint,2,opt,name=status,proto3,enum=fight.Friend_Status" json:"status,omitempty"`
+	// the friend request is sent by the friend
+	Incoming bool `protobuf:"varint,3,opt,name=incoming,proto3" json:"incoming,omitempty"`
+	// the time of the request, or of the acceptance
+	Since                *timestamp.Timestamp `protobuf:"bytes,4,opt,name=since,proto3" json:"since,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
-func (m *Level) Reset()         { *m = Level{} }
-func (m *Level) String() string { return proto.CompactTextString(m) }
-func (*Level) ProtoMessage()    {}
-func (*Level) Descriptor() ([]byte, []int) {
-	return fileDescriptor_475ae6b24dd70e2f, []int{10}
+func (m *Friend) Reset()         { *m = Friend{} }
+func (m *Friend) String() string { return proto.CompactTextString(m) }
+func (*Friend) ProtoMessage()    {}
+func (*Friend) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{12}
 }
 
-func (m *Level) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Level.Unmarshal(m, b)
+func (m *Friend) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Friend.Unmarshal(m, b)
 }
-func (m *Level) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Level.Marshal(b, m, deterministic)
+func (m *Friend) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Friend.Marshal(b, m, deterministic)
 }
-func (m *Level) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Level.Merge(m, src)
+func (m *Friend) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Friend.Merge(m, src)
 }
-func (m *Level) XXX_Size() int {
-	return xxx_messageInfo_Level.Size(m)
+func (m *Friend) XXX_Size() int {
+	return xxx_messageInfo_Friend.Size(m)
 }
-func (m *Level) XXX_DiscardUnknown() {
-	xxx_messageInfo_Level.DiscardUnknown(m)
+func (m *Friend) XXX_DiscardUnknown() {
+	xxx_messageInfo_Friend.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Level proto.InternalMessageInfo
+var xxx_messageInfo_Friend proto.InternalMessageInfo
 
-func (m *Level) GetMsg() string {
+func (m *Friend) GetDisplayName() string {
 	if m != nil {
-		return m.Msg
+		return m.DisplayName
 	}
 	return ""
 }
 
-func (m *Level) GetSession() *Session {
+func (m *Friend) GetStatus() Friend_Status {
 	if m != nil {
-		return m.Session
+		return m.Status
+	}
+	return Friend_PENDING
+}
+
+func (m *Friend) GetIncoming() bool {
+	if m != nil {
+		return m.Incoming
+	}
+	return false
+}
+
+func (m *Friend) GetSince() *timestamp.Timestamp {
+	if m != nil {
+		return m.Since
 	}
 	return nil
 }
 
-type Quit struct {
-	Msg                  string   `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
+type ListFriendsRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Quit) Reset()         { *m = Quit{} }
-func (m *Quit) String() string { return proto.CompactTextString(m) }
-func (*Quit) ProtoMessage()    {}
-func (*Quit) Descriptor() ([]byte, []int) {
-	return fileDescriptor_475ae6b24dd70e2f, []int{11}
+func (m *ListFriendsRequest) Reset()         { *m = ListFriendsRequest{} }
+func (m *ListFriendsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListFriendsRequest) ProtoMessage()    {}
+func (*ListFriendsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{13}
 }
 
-func (m *Quit) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Quit.Unmarshal(m, b)
+func (m *ListFriendsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListFriendsRequest.Unmarshal(m, b)
 }
-func (m *Quit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Quit.Marshal(b, m, deterministic)
+func (m *ListFriendsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListFriendsRequest.Marshal(b, m, deterministic)
 }
-func (m *Quit) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Quit.Merge(m, src)
+func (m *ListFriendsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListFriendsRequest.Merge(m, src)
 }
-func (m *Quit) XXX_Size() int {
-	return xxx_messageInfo_Quit.Size(m)
+func (m *ListFriendsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListFriendsRequest.Size(m)
 }
-func (m *Quit) XXX_DiscardUnknown() {
-	xxx_messageInfo_Quit.DiscardUnknown(m)
+func (m *ListFriendsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListFriendsRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Quit proto.InternalMessageInfo
+var xxx_messageInfo_ListFriendsRequest proto.InternalMessageInfo
 
-func (m *Quit) GetMsg() string {
+func (m *ListFriendsRequest) GetId() string {
 	if m != nil {
-		return m.Msg
+		return m.Id
 	}
 	return ""
 }
 
-type SelectHeroRequest struct {
+type ListFriendsResponse struct {
+	Friends              []*Friend `protobuf:"bytes,1,rep,name=friends,proto3" json:"friends,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ListFriendsResponse) Reset()         { *m = ListFriendsResponse{} }
+func (m *ListFriendsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListFriendsResponse) ProtoMessage()    {}
+func (*ListFriendsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{14}
+}
+
+func (m *ListFriendsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListFriendsResponse.Unmarshal(m, b)
+}
+func (m *ListFriendsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListFriendsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListFriendsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListFriendsResponse.Merge(m, src)
+}
+func (m *ListFriendsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListFriendsResponse.Size(m)
+}
+func (m *ListFriendsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListFriendsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListFriendsResponse proto.InternalMessageInfo
+
+func (m *ListFriendsResponse) GetFriends() []*Friend {
+	if m != nil {
+		return m.Friends
+	}
+	return nil
+}
+
+type FriendsLeaderboardRequest struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	HeroName             string   `protobuf:"bytes,2,opt,name=hero_name,json=heroName,proto3" json:"hero_name,omitempty"`
+	Limit                int32    `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset               int32    `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Neighbours           int32    `protobuf:"varint,4,opt,name=neighbours,proto3" json:"neighbours,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *SelectHeroRequest) Reset()         { *m = SelectHeroRequest{} }
-func (m *SelectHeroRequest) String() string { return proto.CompactTextString(m) }
-func (*SelectHeroRequest) ProtoMessage()    {}
-func (*SelectHeroRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_475ae6b24dd70e2f, []int{12}
+func (m *FriendsLeaderboardRequest) Reset()         { *m = FriendsLeaderboardRequest{} }
+func (m *FriendsLeaderboardRequest) String() string { return proto.CompactTextString(m) }
+func (*FriendsLeaderboardRequest) ProtoMessage()    {}
+func (*FriendsLeaderboardRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{15}
 }
 
-func (m *SelectHeroRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SelectHeroRequest.Unmarshal(m, b)
+func (m *FriendsLeaderboardRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FriendsLeaderboardRequest.Unmarshal(m, b)
 }
-func (m *SelectHeroRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SelectHeroRequest.Marshal(b, m, deterministic)
+func (m *FriendsLeaderboardRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FriendsLeaderboardRequest.Marshal(b, m, deterministic)
 }
-func (m *SelectHeroRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SelectHeroRequest.Merge(m, src)
+func (m *FriendsLeaderboardRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FriendsLeaderboardRequest.Merge(m, src)
 }
-func (m *SelectHeroRequest) XXX_Size() int {
-	return xxx_messageInfo_SelectHeroRequest.Size(m)
+func (m *FriendsLeaderboardRequest) XXX_Size() int {
+	return xxx_messageInfo_FriendsLeaderboardRequest.Size(m)
 }
-func (m *SelectHeroRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_SelectHeroRequest.DiscardUnknown(m)
+func (m *FriendsLeaderboardRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FriendsLeaderboardRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SelectHeroRequest proto.InternalMessageInfo
+var xxx_messageInfo_FriendsLeaderboardRequest proto.InternalMessageInfo
 
-func (m *SelectHeroRequest) GetId() string {
+func (m *FriendsLeaderboardRequest) GetId() string {
 	if m != nil {
 		return m.Id
 	}
 	return ""
 }
 
-func (m *SelectHeroRequest) GetHeroName() string {
+func (m *FriendsLeaderboardRequest) GetLimit() int32 {
 	if m != nil {
-		return m.HeroName
+		return m.Limit
 	}
-	return ""
+	return 0
 }
 
-type LoadSessionRequest struct {
+func (m *FriendsLeaderboardRequest) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *FriendsLeaderboardRequest) GetNeighbours() int32 {
+	if m != nil {
+		return m.Neighbours
+	}
+	return 0
+}
+
+type GetProfileRequest struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *LoadSessionRequest) Reset()         { *m = LoadSessionRequest{} }
-func (m *LoadSessionRequest) String() string { return proto.CompactTextString(m) }
-func (*LoadSessionRequest) ProtoMessage()    {}
-func (*LoadSessionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_475ae6b24dd70e2f, []int{13}
+func (m *GetProfileRequest) Reset()         { *m = GetProfileRequest{} }
+func (m *GetProfileRequest) String() string { return proto.CompactTextString(m) }
+func (*GetProfileRequest) ProtoMessage()    {}
+func (*GetProfileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{16}
 }
 
-func (m *LoadSessionRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_LoadSessionRequest.Unmarshal(m, b)
+func (m *GetProfileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetProfileRequest.Unmarshal(m, b)
 }
-func (m *LoadSessionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_LoadSessionRequest.Marshal(b, m, deterministic)
+func (m *GetProfileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetProfileRequest.Marshal(b, m, deterministic)
 }
-func (m *LoadSessionRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_LoadSessionRequest.Merge(m, src)
+func (m *GetProfileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetProfileRequest.Merge(m, src)
 }
-func (m *LoadSessionRequest) XXX_Size() int {
-	return xxx_messageInfo_LoadSessionRequest.Size(m)
+func (m *GetProfileRequest) XXX_Size() int {
+	return xxx_messageInfo_GetProfileRequest.Size(m)
 }
-func (m *LoadSessionRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_LoadSessionRequest.DiscardUnknown(m)
+func (m *GetProfileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetProfileRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_LoadSessionRequest proto.InternalMessageInfo
+var xxx_messageInfo_GetProfileRequest proto.InternalMessageInfo
 
-func (m *LoadSessionRequest) GetId() string {
+func (m *GetProfileRequest) GetId() string {
 	if m != nil {
 		return m.Id
 	}
 	return ""
 }
 
-type SessionView struct {
-	Hero                 *Hero    `protobuf:"bytes,1,opt,name=hero,proto3" json:"hero,omitempty"`
-	Boss                 *Boss    `protobuf:"bytes,2,opt,name=boss,proto3" json:"boss,omitempty"`
-	Session              *Session `protobuf:"bytes,3,opt,name=session,proto3" json:"session,omitempty"`
+type DeleteProfileRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *SessionView) Reset()         { *m = SessionView{} }
-func (m *SessionView) String() string { return proto.CompactTextString(m) }
-func (*SessionView) ProtoMessage()    {}
-func (*SessionView) Descriptor() ([]byte, []int) {
-	return fileDescriptor_475ae6b24dd70e2f, []int{14}
+func (m *DeleteProfileRequest) Reset()         { *m = DeleteProfileRequest{} }
+func (m *DeleteProfileRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteProfileRequest) ProtoMessage()    {}
+func (*DeleteProfileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{17}
 }
 
-func (m *SessionView) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SessionView.Unmarshal(m, b)
-}
-func (m *SessionView) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SessionView.Marshal(b, m, deterministic)
+func (m *DeleteProfileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteProfileRequest.Unmarshal(m, b)
 }
-func (m *SessionView) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SessionView.Merge(m, src)
+func (m *DeleteProfileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteProfileRequest.Marshal(b, m, deterministic)
 }
-func (m *SessionView) XXX_Size() int {
-	return xxx_messageInfo_SessionView.Size(m)
+func (m *DeleteProfileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteProfileRequest.Merge(m, src)
 }
-func (m *SessionView) XXX_DiscardUnknown() {
-	xxx_messageInfo_SessionView.DiscardUnknown(m)
+func (m *DeleteProfileRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteProfileRequest.Size(m)
 }
-
-var xxx_messageInfo_SessionView proto.InternalMessageInfo
-
-func (m *SessionView) GetHero() *Hero {
-	if m != nil {
-		return m.Hero
-	}
-	return nil
+func (m *DeleteProfileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteProfileRequest.DiscardUnknown(m)
 }
 
-func (m *SessionView) GetBoss() *Boss {
-	if m != nil {
-		return m.Boss
-	}
-	return nil
-}
+var xxx_messageInfo_DeleteProfileRequest proto.InternalMessageInfo
 
-func (m *SessionView) GetSession() *Session {
+func (m *DeleteProfileRequest) GetId() string {
 	if m != nil {
-		return m.Session
+		return m.Id
 	}
-	return nil
+	return ""
 }
 
-type ListHerosRequest struct {
+type DeleteProfileResponse struct {
+	Msg                  string   `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ListHerosRequest) Reset()         { *m = ListHerosRequest{} }
-func (m *ListHerosRequest) String() string { return proto.CompactTextString(m) }
-func (*ListHerosRequest) ProtoMessage()    {}
-func (*ListHerosRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_475ae6b24dd70e2f, []int{15}
+func (m *DeleteProfileResponse) Reset()         { *m = DeleteProfileResponse{} }
+func (m *DeleteProfileResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteProfileResponse) ProtoMessage()    {}
+func (*DeleteProfileResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{18}
 }
 
-func (m *ListHerosRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ListHerosRequest.Unmarshal(m, b)
+func (m *DeleteProfileResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteProfileResponse.Unmarshal(m, b)
 }
-func (m *ListHerosRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ListHerosRequest.Marshal(b, m, deterministic)
+func (m *DeleteProfileResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteProfileResponse.Marshal(b, m, deterministic)
 }
-func (m *ListHerosRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ListHerosRequest.Merge(m, src)
+func (m *DeleteProfileResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteProfileResponse.Merge(m, src)
 }
-func (m *ListHerosRequest) XXX_Size() int {
-	return xxx_messageInfo_ListHerosRequest.Size(m)
+func (m *DeleteProfileResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteProfileResponse.Size(m)
 }
-func (m *ListHerosRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ListHerosRequest.DiscardUnknown(m)
+func (m *DeleteProfileResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteProfileResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ListHerosRequest proto.InternalMessageInfo
+var xxx_messageInfo_DeleteProfileResponse proto.InternalMessageInfo
 
-type Hero struct {
-	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Details              string   `protobuf:"bytes,2,opt,name=details,proto3" json:"details,omitempty"`
-	AttackPower          int32    `protobuf:"varint,3,opt,name=attack_power,json=attackPower,proto3" json:"attack_power,omitempty"`
-	DefensePower         int32    `protobuf:"varint,4,opt,name=defense_power,json=defensePower,proto3" json:"defense_power,omitempty"`
-	Blood                int32    `protobuf:"varint,5,opt,name=blood,proto3" json:"blood,omitempty"`
+func (m *DeleteProfileResponse) GetMsg() string {
+	if m != nil {
+		return m.Msg
+	}
+	return ""
+}
+
+type ClearSessionRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Hero) Reset()         { *m = Hero{} }
-func (m *Hero) String() string { return proto.CompactTextString(m) }
-func (*Hero) ProtoMessage()    {}
-func (*Hero) Descriptor() ([]byte, []int) {
-	return fileDescriptor_475ae6b24dd70e2f, []int{16}
+func (m *ClearSessionRequest) Reset()         { *m = ClearSessionRequest{} }
+func (m *ClearSessionRequest) String() string { return proto.CompactTextString(m) }
+func (*ClearSessionRequest) ProtoMessage()    {}
+func (*ClearSessionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{19}
 }
 
-func (m *Hero) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Hero.Unmarshal(m, b)
+func (m *ClearSessionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClearSessionRequest.Unmarshal(m, b)
 }
-func (m *Hero) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Hero.Marshal(b, m, deterministic)
+func (m *ClearSessionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClearSessionRequest.Marshal(b, m, deterministic)
 }
-func (m *Hero) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Hero.Merge(m, src)
+func (m *ClearSessionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClearSessionRequest.Merge(m, src)
 }
-func (m *Hero) XXX_Size() int {
-	return xxx_messageInfo_Hero.Size(m)
+func (m *ClearSessionRequest) XXX_Size() int {
+	return xxx_messageInfo_ClearSessionRequest.Size(m)
 }
-func (m *Hero) XXX_DiscardUnknown() {
-	xxx_messageInfo_Hero.DiscardUnknown(m)
+func (m *ClearSessionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClearSessionRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Hero proto.InternalMessageInfo
-
-func (m *Hero) GetName() string {
-	if m != nil {
-		return m.Name
-	}
-	return ""
-}
+var xxx_messageInfo_ClearSessionRequest proto.InternalMessageInfo
 
-func (m *Hero) GetDetails() string {
+func (m *ClearSessionRequest) GetId() string {
 	if m != nil {
-		return m.Details
+		return m.Id
 	}
 	return ""
 }
 
-func (m *Hero) GetAttackPower() int32 {
-	if m != nil {
-		return m.AttackPower
-	}
-	return 0
+type ClearSessionResponse struct {
+	Msg                  string   `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Hero) GetDefensePower() int32 {
-	if m != nil {
-		return m.DefensePower
-	}
-	return 0
+func (m *ClearSessionResponse) Reset()         { *m = ClearSessionResponse{} }
+func (m *ClearSessionResponse) String() string { return proto.CompactTextString(m) }
+func (*ClearSessionResponse) ProtoMessage()    {}
+func (*ClearSessionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{20}
 }
 
-func (m *Hero) GetBlood() int32 {
+func (m *ClearSessionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClearSessionResponse.Unmarshal(m, b)
+}
+func (m *ClearSessionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClearSessionResponse.Marshal(b, m, deterministic)
+}
+func (m *ClearSessionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClearSessionResponse.Merge(m, src)
+}
+func (m *ClearSessionResponse) XXX_Size() int {
+	return xxx_messageInfo_ClearSessionResponse.Size(m)
+}
+func (m *ClearSessionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClearSessionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClearSessionResponse proto.InternalMessageInfo
+
+func (m *ClearSessionResponse) GetMsg() string {
 	if m != nil {
-		return m.Blood
+		return m.Msg
 	}
-	return 0
+	return ""
 }
 
-type Boss struct {
-	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Details              string   `protobuf:"bytes,2,opt,name=details,proto3" json:"details,omitempty"`
-	AttackPower          int32    `protobuf:"varint,3,opt,name=attack_power,json=attackPower,proto3" json:"attack_power,omitempty"`
-	DefensePower         int32    `protobuf:"varint,4,opt,name=defense_power,json=defensePower,proto3" json:"defense_power,omitempty"`
-	Blood                int32    `protobuf:"varint,5,opt,name=blood,proto3" json:"blood,omitempty"`
-	Level                int32    `protobuf:"varint,6,opt,name=level,proto3" json:"level,omitempty"`
+type AdminRequest struct {
+	Heros []*Hero           `protobuf:"bytes,1,rep,name=heros,proto3" json:"heros,omitempty"`
+	Type  AdminRequest_Type `protobuf:"varint,2,opt,name=type,proto3,enum=fight.AdminRequest_Type" json:"type,omitempty"`
+	// the hero the sessions of a deleted hero are moved to, the delete fails
+	// while sessions play the hero if it is empty
+	MigrateTo string  `protobuf:"bytes,3,opt,name=migrate_to,json=migrateTo,proto3" json:"migrate_to,omitempty"`
+	Bosses    []*Boss `protobuf:"bytes,4,rep,name=bosses,proto3" json:"bosses,omitempty"`
+	// echoed in the result of the request
+	RequestId string `protobuf:"bytes,5,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// applies all the heros or bosses of the request in one transaction,
+	// none is applied if one fails
+	Atomic  bool          `protobuf:"varint,6,opt,name=atomic,proto3" json:"atomic,omitempty"`
+	Patch   *BalancePatch `protobuf:"bytes,7,opt,name=patch,proto3" json:"patch,omitempty"`
+	PatchId int64         `protobuf:"varint,8,opt,name=patch_id,json=patchId,proto3" json:"patch_id,omitempty"`
+	// the tables, hero, boss or session, and the actions, INSERT, UPDATE or
+	// DELETE, of SUBSCRIBE
+	Tables  []string `protobuf:"bytes,9,rep,name=tables,proto3" json:"tables,omitempty"`
+	Actions []string `protobuf:"bytes,10,rep,name=actions,proto3" json:"actions,omitempty"`
+	// the session and the last event of REPLAY_SESSION, all the events if
+	// until_seq is 0
+	SessionId            string   `protobuf:"bytes,11,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	UntilSeq             int64    `protobuf:"varint,12,opt,name=until_seq,json=untilSeq,proto3" json:"until_seq,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Boss) Reset()         { *m = Boss{} }
-func (m *Boss) String() string { return proto.CompactTextString(m) }
-func (*Boss) ProtoMessage()    {}
-func (*Boss) Descriptor() ([]byte, []int) {
-	return fileDescriptor_475ae6b24dd70e2f, []int{17}
+func (m *AdminRequest) Reset()         { *m = AdminRequest{} }
+func (m *AdminRequest) String() string { return proto.CompactTextString(m) }
+func (*AdminRequest) ProtoMessage()    {}
+func (*AdminRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{21}
 }
 
-func (m *Boss) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Boss.Unmarshal(m, b)
+func (m *AdminRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdminRequest.Unmarshal(m, b)
 }
-func (m *Boss) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Boss.Marshal(b, m, deterministic)
+func (m *AdminRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdminRequest.Marshal(b, m, deterministic)
 }
-func (m *Boss) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Boss.Merge(m, src)
+func (m *AdminRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdminRequest.Merge(m, src)
 }
-func (m *Boss) XXX_Size() int {
-	return xxx_messageInfo_Boss.Size(m)
+func (m *AdminRequest) XXX_Size() int {
+	return xxx_messageInfo_AdminRequest.Size(m)
 }
-func (m *Boss) XXX_DiscardUnknown() {
-	xxx_messageInfo_Boss.DiscardUnknown(m)
+func (m *AdminRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdminRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Boss proto.InternalMessageInfo
+var xxx_messageInfo_AdminRequest proto.InternalMessageInfo
 
-func (m *Boss) GetName() string {
+func (m *AdminRequest) GetHeros() []*Hero {
 	if m != nil {
-		return m.Name
+		return m.Heros
+	}
+	return nil
+}
+
+func (m *AdminRequest) GetType() AdminRequest_Type {
+	if m != nil {
+		return m.Type
+	}
+	return AdminRequest_CREATE_HERO
+}
+
+func (m *AdminRequest) GetMigrateTo() string {
+	if m != nil {
+		return m.MigrateTo
 	}
 	return ""
 }
 
-func (m *Boss) GetDetails() string {
+func (m *AdminRequest) GetBosses() []*Boss {
 	if m != nil {
-		return m.Details
+		return m.Bosses
+	}
+	return nil
+}
+
+func (m *AdminRequest) GetRequestId() string {
+	if m != nil {
+		return m.RequestId
 	}
 	return ""
 }
 
-func (m *Boss) GetAttackPower() int32 {
+func (m *AdminRequest) GetAtomic() bool {
 	if m != nil {
-		return m.AttackPower
+		return m.Atomic
 	}
-	return 0
+	return false
 }
 
-func (m *Boss) GetDefensePower() int32 {
+func (m *AdminRequest) GetPatch() *BalancePatch {
 	if m != nil {
-		return m.DefensePower
+		return m.Patch
 	}
-	return 0
+	return nil
 }
 
-func (m *Boss) GetBlood() int32 {
+func (m *AdminRequest) GetPatchId() int64 {
 	if m != nil {
-		return m.Blood
+		return m.PatchId
 	}
 	return 0
 }
 
-func (m *Boss) GetLevel() int32 {
+func (m *AdminRequest) GetTables() []string {
 	if m != nil {
-		return m.Level
+		return m.Tables
+	}
+	return nil
+}
+
+func (m *AdminRequest) GetActions() []string {
+	if m != nil {
+		return m.Actions
+	}
+	return nil
+}
+
+func (m *AdminRequest) GetSessionId() string {
+	if m != nil {
+		return m.SessionId
+	}
+	return ""
+}
+
+func (m *AdminRequest) GetUntilSeq() int64 {
+	if m != nil {
+		return m.UntilSeq
 	}
 	return 0
 }
 
-type Session struct {
-	UID                  string               `protobuf:"bytes,1,opt,name=UID,proto3" json:"UID,omitempty"`
-	HeroName             string               `protobuf:"bytes,2,opt,name=hero_name,json=heroName,proto3" json:"hero_name,omitempty"`
-	LiveHeroBlood        int32                `protobuf:"varint,3,opt,name=live_hero_blood,json=liveHeroBlood,proto3" json:"live_hero_blood,omitempty"`
-	LiveBossBlood        int32                `protobuf:"varint,4,opt,name=live_boss_blood,json=liveBossBlood,proto3" json:"live_boss_blood,omitempty"`
-	CurrentLevel         int32                `protobuf:"varint,5,opt,name=current_level,json=currentLevel,proto3" json:"current_level,omitempty"`
-	Score                int32                `protobuf:"varint,6,opt,name=score,proto3" json:"score,omitempty"`
-	ArchiveDate          *timestamp.Timestamp `protobuf:"bytes,7,opt,name=archive_date,json=archiveDate,proto3" json:"archive_date,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+// changes the stats of the heros
+type BalancePatch struct {
+	// the names of the patched heros, all the heros if empty
+	Heros   []string               `protobuf:"bytes,1,rep,name=heros,proto3" json:"heros,omitempty"`
+	Changes []*BalancePatch_Change `protobuf:"bytes,2,rep,name=changes,proto3" json:"changes,omitempty"`
+	// previews the diff without applying the patch
+	DryRun               bool     `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Session) Reset()         { *m = Session{} }
-func (m *Session) String() string { return proto.CompactTextString(m) }
-func (*Session) ProtoMessage()    {}
-func (*Session) Descriptor() ([]byte, []int) {
-	return fileDescriptor_475ae6b24dd70e2f, []int{18}
+func (m *BalancePatch) Reset()         { *m = BalancePatch{} }
+func (m *BalancePatch) String() string { return proto.CompactTextString(m) }
+func (*BalancePatch) ProtoMessage()    {}
+func (*BalancePatch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{22}
 }
 
-func (m *Session) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Session.Unmarshal(m, b)
+func (m *BalancePatch) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BalancePatch.Unmarshal(m, b)
 }
-func (m *Session) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Session.Marshal(b, m, deterministic)
+func (m *BalancePatch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BalancePatch.Marshal(b, m, deterministic)
 }
-func (m *Session) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Session.Merge(m, src)
+func (m *BalancePatch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BalancePatch.Merge(m, src)
 }
-func (m *Session) XXX_Size() int {
-	return xxx_messageInfo_Session.Size(m)
+func (m *BalancePatch) XXX_Size() int {
+	return xxx_messageInfo_BalancePatch.Size(m)
 }
-func (m *Session) XXX_DiscardUnknown() {
-	xxx_messageInfo_Session.DiscardUnknown(m)
+func (m *BalancePatch) XXX_DiscardUnknown() {
+	xxx_messageInfo_BalancePatch.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Session proto.InternalMessageInfo
+var xxx_messageInfo_BalancePatch proto.InternalMessageInfo
 
-func (m *Session) GetUID() string {
+func (m *BalancePatch) GetHeros() []string {
 	if m != nil {
-		return m.UID
+		return m.Heros
 	}
-	return ""
+	return nil
 }
 
-func (m *Session) GetHeroName() string {
+func (m *BalancePatch) GetChanges() []*BalancePatch_Change {
 	if m != nil {
-		return m.HeroName
+		return m.Changes
 	}
-	return ""
+	return nil
 }
 
-func (m *Session) GetLiveHeroBlood() int32 {
+func (m *BalancePatch) GetDryRun() bool {
 	if m != nil {
-		return m.LiveHeroBlood
+		return m.DryRun
 	}
-	return 0
+	return false
 }
 
-func (m *Session) GetLiveBossBlood() int32 {
-	if m != nil {
-		return m.LiveBossBlood
-	}
-	return 0
+type BalancePatch_Change struct {
+	Stat                 BalancePatch_Stat `protobuf:"varint,1,opt,name=stat,proto3,enum=fight.BalancePatch_Stat" json:"stat,omitempty"`
+	Mode                 BalancePatch_Mode `protobuf:"varint,2,opt,name=mode,proto3,enum=fight.BalancePatch_Mode" json:"mode,omitempty"`
+	Value                float64           `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
-func (m *Session) GetCurrentLevel() int32 {
+func (m *BalancePatch_Change) Reset()         { *m = BalancePatch_Change{} }
+func (m *BalancePatch_Change) String() string { return proto.CompactTextString(m) }
+func (*BalancePatch_Change) ProtoMessage()    {}
+func (*BalancePatch_Change) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{22, 0}
+}
+
+func (m *BalancePatch_Change) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BalancePatch_Change.Unmarshal(m, b)
+}
+func (m *BalancePatch_Change) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BalancePatch_Change.Marshal(b, m, deterministic)
+}
+func (m *BalancePatch_Change) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BalancePatch_Change.Merge(m, src)
+}
+func (m *BalancePatch_Change) XXX_Size() int {
+	return xxx_messageInfo_BalancePatch_Change.Size(m)
+}
+func (m *BalancePatch_Change) XXX_DiscardUnknown() {
+	xxx_messageInfo_BalancePatch_Change.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BalancePatch_Change proto.InternalMessageInfo
+
+func (m *BalancePatch_Change) GetStat() BalancePatch_Stat {
 	if m != nil {
-		return m.CurrentLevel
+		return m.Stat
 	}
-	return 0
+	return BalancePatch_ATTACK_POWER
 }
 
-func (m *Session) GetScore() int32 {
+func (m *BalancePatch_Change) GetMode() BalancePatch_Mode {
 	if m != nil {
-		return m.Score
+		return m.Mode
 	}
-	return 0
+	return BalancePatch_SET
 }
 
-func (m *Session) GetArchiveDate() *timestamp.Timestamp {
+func (m *BalancePatch_Change) GetValue() float64 {
 	if m != nil {
-		return m.ArchiveDate
+		return m.Value
 	}
-	return nil
+	return 0
 }
 
-func init() {
-	proto.RegisterEnum("fight.Type", Type_name, Type_value)
-	proto.RegisterEnum("fight.AdminRequest_Type", AdminRequest_Type_name, AdminRequest_Type_value)
-	proto.RegisterType((*ClearSessionRequest)(nil), "fight.ClearSessionRequest")
-	proto.RegisterType((*ClearSessionResponse)(nil), "fight.ClearSessionResponse")
-	proto.RegisterType((*AdminRequest)(nil), "fight.AdminRequest")
-	proto.RegisterType((*AdminResponse)(nil), "fight.AdminResponse")
-	proto.RegisterType((*Top10Request)(nil), "fight.Top10Request")
-	proto.RegisterType((*Top10Response)(nil), "fight.Top10Response")
-	proto.RegisterType((*Top10Response_Player)(nil), "fight.Top10Response.Player")
-	proto.RegisterType((*GameRequest)(nil), "fight.GameRequest")
-	proto.RegisterType((*GameResponse)(nil), "fight.GameResponse")
-	proto.RegisterType((*Fight)(nil), "fight.Fight")
-	proto.RegisterType((*Archive)(nil), "fight.Archive")
-	proto.RegisterType((*Level)(nil), "fight.Level")
-	proto.RegisterType((*Quit)(nil), "fight.Quit")
-	proto.RegisterType((*SelectHeroRequest)(nil), "fight.SelectHeroRequest")
-	proto.RegisterType((*LoadSessionRequest)(nil), "fight.LoadSessionRequest")
-	proto.RegisterType((*SessionView)(nil), "fight.SessionView")
-	proto.RegisterType((*ListHerosRequest)(nil), "fight.ListHerosRequest")
-	proto.RegisterType((*Hero)(nil), "fight.Hero")
-	proto.RegisterType((*Boss)(nil), "fight.Boss")
-	proto.RegisterType((*Session)(nil), "fight.Session")
+// the stats of a hero before and after a balance patch
+type HeroDiff struct {
+	Before               *Hero    `protobuf:"bytes,1,opt,name=before,proto3" json:"before,omitempty"`
+	After                *Hero    `protobuf:"bytes,2,opt,name=after,proto3" json:"after,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func init() { proto.RegisterFile("pd/fight/fight.proto", fileDescriptor_475ae6b24dd70e2f) }
+func (m *HeroDiff) Reset()         { *m = HeroDiff{} }
+func (m *HeroDiff) String() string { return proto.CompactTextString(m) }
+func (*HeroDiff) ProtoMessage()    {}
+func (*HeroDiff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{23}
+}
 
-var fileDescriptor_475ae6b24dd70e2f = []byte{
-	// 993 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x56, 0xcf, 0x6f, 0xe2, 0x56,
-	0x10, 0x8e, 0xc1, 0x8e, 0x61, 0xec, 0x64, 0xe9, 0x0b, 0x52, 0x5d, 0xa2, 0xd5, 0x26, 0xee, 0xb6,
-	0x42, 0xab, 0x8a, 0xa4, 0xa4, 0xed, 0x61, 0xfb, 0x43, 0x0d, 0x81, 0x0d, 0x54, 0xa8, 0xbb, 0xeb,
-	0x90, 0x1c, 0x7a, 0x41, 0x0e, 0x9e, 0x10, 0xab, 0x06, 0x7b, 0xfd, 0x0c, 0xdb, 0x5c, 0x7b, 0xab,
-	0xd4, 0x53, 0x2f, 0x3d, 0xf7, 0xd6, 0xff, 0xa7, 0xff, 0x50, 0xf5, 0x7e, 0x81, 0x21, 0x28, 0xb9,
-	0xee, 0x05, 0xf1, 0xbe, 0xf9, 0xde, 0xbc, 0x99, 0x79, 0xf3, 0xbd, 0x31, 0x54, 0x93, 0xe0, 0xe8,
-	0x26, 0x1c, 0xdf, 0x66, 0xe2, 0xb7, 0x91, 0xa4, 0x71, 0x16, 0x13, 0x83, 0x2f, 0x6a, 0xcf, 0xc6,
-	0x71, 0x3c, 0x8e, 0xf0, 0x88, 0x83, 0xd7, 0xb3, 0x9b, 0xa3, 0x2c, 0x9c, 0x20, 0xcd, 0xfc, 0x49,
-	0x22, 0x78, 0xee, 0x67, 0xb0, 0x77, 0x16, 0xa1, 0x9f, 0x5e, 0x20, 0xa5, 0x61, 0x3c, 0xf5, 0xf0,
-	0xdd, 0x0c, 0x69, 0x46, 0x76, 0xa1, 0x10, 0x06, 0x8e, 0x76, 0xa0, 0xd5, 0xcb, 0x5e, 0x21, 0x0c,
-	0xdc, 0x3a, 0x54, 0x57, 0x69, 0x34, 0x89, 0xa7, 0x14, 0x49, 0x05, 0x8a, 0x13, 0x3a, 0x96, 0x44,
-	0xf6, 0xd7, 0xfd, 0x43, 0x03, 0xfb, 0x34, 0x98, 0x84, 0x0b, 0x57, 0x87, 0x60, 0xdc, 0x62, 0x1a,
-	0x53, 0x47, 0x3b, 0x28, 0xd6, 0xad, 0xa6, 0xd5, 0x10, 0x61, 0x76, 0x31, 0x8d, 0x3d, 0x61, 0x21,
-	0x5f, 0x80, 0x9e, 0xdd, 0x25, 0xe8, 0x14, 0x0e, 0xb4, 0xfa, 0x6e, 0xd3, 0x91, 0x8c, 0xbc, 0x97,
-	0xc6, 0xe0, 0x2e, 0x41, 0x8f, 0xb3, 0xdc, 0x3a, 0xe8, 0x6c, 0x45, 0x9e, 0x80, 0x75, 0xe6, 0x75,
-	0x4e, 0x07, 0x9d, 0x61, 0xb7, 0xe3, 0xbd, 0xae, 0x6c, 0x31, 0xe0, 0xb4, 0xfd, 0xd3, 0xe5, 0xc5,
-	0x40, 0x00, 0x9a, 0xdb, 0x84, 0x1d, 0xe9, 0x44, 0x86, 0xfb, 0x78, 0x2c, 0xee, 0x2e, 0xd8, 0x83,
-	0x38, 0xf9, 0xf2, 0x58, 0x1e, 0xec, 0xfe, 0xa9, 0xc1, 0x8e, 0x04, 0xa4, 0x93, 0xaf, 0xc1, 0x4c,
-	0x22, 0xff, 0x0e, 0x53, 0xe5, 0x66, 0x5f, 0xba, 0x59, 0xa1, 0x35, 0xde, 0x70, 0x8e, 0xa7, 0xb8,
-	0xb5, 0x36, 0x6c, 0x0b, 0x68, 0xbd, 0xb8, 0xa4, 0x0a, 0x06, 0x1d, 0xc5, 0xa9, 0xc8, 0xdf, 0xf0,
-	0xc4, 0x82, 0xa1, 0x11, 0xce, 0x31, 0x72, 0x8a, 0x02, 0xe5, 0x0b, 0xf7, 0x07, 0xb0, 0xce, 0xfd,
-	0x09, 0xaa, 0xe2, 0x3e, 0x93, 0x95, 0xd3, 0x78, 0xe5, 0x54, 0x3e, 0xcb, 0x62, 0xc9, 0xb3, 0x0a,
-	0x8b, 0x8b, 0xfc, 0x4f, 0x03, 0x5b, 0x38, 0x90, 0xd9, 0x3c, 0xea, 0xe1, 0x39, 0x88, 0x5e, 0xe2,
-	0x4e, 0xac, 0xa6, 0x2d, 0x19, 0xaf, 0xd8, 0x6f, 0x77, 0xcb, 0x13, 0x46, 0xf2, 0x02, 0x4c, 0x3f,
-	0x1d, 0xdd, 0x86, 0x73, 0xe4, 0xf1, 0x5a, 0xcd, 0x5d, 0x75, 0x8b, 0x02, 0xed, 0x6e, 0x79, 0x8a,
-	0xc0, 0x3c, 0x8a, 0xcc, 0xf4, 0x15, 0x8f, 0x7d, 0x86, 0x31, 0x8f, 0xdc, 0x48, 0x0e, 0x41, 0x7f,
-	0x37, 0x0b, 0x33, 0xc7, 0xe0, 0x24, 0x15, 0xd8, 0xdb, 0x59, 0xc8, 0x4e, 0xe5, 0xa6, 0x96, 0x09,
-	0xc6, 0xdc, 0x8f, 0x66, 0xe8, 0xfe, 0xad, 0x81, 0xc1, 0x03, 0x22, 0xfb, 0x50, 0x1e, 0xfb, 0x13,
-	0x1c, 0xc6, 0x73, 0x4c, 0x79, 0x4e, 0x25, 0xaf, 0xc4, 0x80, 0xd7, 0x73, 0x4c, 0xc9, 0x53, 0x80,
-	0x29, 0xfe, 0x96, 0x0d, 0xc5, 0xe9, 0x05, 0x6e, 0x2d, 0x33, 0x84, 0x1f, 0xbd, 0xbc, 0x87, 0x62,
-	0xfe, 0x1e, 0x9e, 0x02, 0xb0, 0xce, 0x18, 0x5e, 0x47, 0x71, 0x1c, 0xf0, 0x90, 0x0d, 0xaf, 0xcc,
-	0x90, 0x16, 0x03, 0x98, 0xf9, 0x3a, 0xa6, 0x54, 0x9a, 0x0d, 0x61, 0x66, 0x08, 0x37, 0xbb, 0x2f,
-	0xc1, 0x94, 0x15, 0xb8, 0xaf, 0x15, 0xb6, 0x97, 0x0a, 0x41, 0x0d, 0x17, 0x97, 0x54, 0x96, 0x48,
-	0x2f, 0x70, 0xcf, 0xc0, 0x10, 0x81, 0xdd, 0xdf, 0x59, 0x07, 0x53, 0xf2, 0xe4, 0xb5, 0xa8, 0x72,
-	0x2b, 0x81, 0x2a, 0xb3, 0xeb, 0x80, 0xce, 0x6a, 0xb6, 0x41, 0xa9, 0x3f, 0xc2, 0x47, 0x17, 0x18,
-	0xe1, 0x28, 0xe3, 0xed, 0xbf, 0x59, 0xf8, 0xac, 0x9e, 0x3c, 0xfb, 0xa9, 0x3f, 0x41, 0x19, 0x61,
-	0x89, 0x01, 0x3f, 0xfb, 0x13, 0x74, 0x9f, 0x03, 0xe9, 0xc7, 0x7e, 0xf0, 0xc8, 0xdb, 0x71, 0x07,
-	0x96, 0x64, 0x5c, 0x85, 0xf8, 0x9e, 0x35, 0x1c, 0x73, 0xc0, 0x09, 0x6b, 0x12, 0xe4, 0x06, 0x46,
-	0x60, 0xf5, 0x93, 0x89, 0x29, 0x42, 0x2b, 0xa6, 0xd4, 0xe3, 0x86, 0x7c, 0xf2, 0xc5, 0x87, 0x93,
-	0x27, 0x50, 0xe9, 0x87, 0x94, 0x27, 0x48, 0x95, 0xa0, 0xff, 0xd2, 0x40, 0x67, 0x00, 0x21, 0xa0,
-	0xf3, 0xac, 0x44, 0xa4, 0xfc, 0x3f, 0x71, 0xc0, 0x0c, 0x30, 0xf3, 0xc3, 0x88, 0xca, 0x64, 0xd5,
-	0x92, 0x1c, 0x82, 0xed, 0x67, 0x99, 0x3f, 0xfa, 0x75, 0x98, 0xc4, 0xef, 0x31, 0x95, 0x3d, 0x62,
-	0x09, 0xec, 0x0d, 0x83, 0xc8, 0xa7, 0xb0, 0x13, 0xe0, 0x0d, 0x4e, 0x29, 0x4a, 0x8e, 0x68, 0x16,
-	0x5b, 0x82, 0x82, 0x54, 0x05, 0x23, 0xdf, 0x2a, 0x62, 0xe1, 0xfe, 0xab, 0x81, 0xce, 0x32, 0xfc,
-	0x90, 0x82, 0x5a, 0xbe, 0x40, 0xdb, 0xf9, 0x17, 0xe8, 0xf7, 0x02, 0x98, 0xb2, 0xd0, 0xac, 0xa9,
-	0x2e, 0x7b, 0x6d, 0xd5, 0x54, 0x97, 0xbd, 0xf6, 0x83, 0xfd, 0x42, 0x3e, 0x87, 0x27, 0x51, 0x38,
-	0xc7, 0x61, 0x4e, 0x4f, 0x22, 0xe2, 0x1d, 0x06, 0x77, 0x17, 0x9a, 0x52, 0xbc, 0x9c, 0xb0, 0xf4,
-	0x25, 0xaf, 0xa5, 0xc4, 0xc5, 0x72, 0x1b, 0xcd, 0xd2, 0x14, 0xa7, 0x4a, 0xd2, 0x22, 0x7c, 0x5b,
-	0x82, 0x6b, 0xaa, 0xde, 0xce, 0xab, 0xfa, 0x7b, 0xb0, 0xe5, 0x73, 0x34, 0x0c, 0xfc, 0x0c, 0x1d,
-	0x93, 0x37, 0x52, 0xad, 0x21, 0xe6, 0x65, 0x43, 0xcd, 0xcb, 0xc6, 0x40, 0xcd, 0x4b, 0xcf, 0x92,
-	0xfc, 0xb6, 0x9f, 0xe1, 0x8b, 0x13, 0x39, 0x83, 0xca, 0x60, 0xbc, 0xea, 0x9d, 0x77, 0x07, 0x95,
-	0x2d, 0x62, 0x81, 0x79, 0xea, 0x9d, 0x75, 0x7b, 0x57, 0x9d, 0x8a, 0xc6, 0xf0, 0x7e, 0xe7, 0xaa,
-	0xd3, 0xaf, 0x14, 0x48, 0x09, 0xf4, 0xb7, 0x97, 0xbd, 0x41, 0xa5, 0xd8, 0xfc, 0xa7, 0x08, 0x25,
-	0xfe, 0x4a, 0x5d, 0xcc, 0x47, 0xe4, 0x04, 0xca, 0x8b, 0xd6, 0x24, 0x1f, 0xab, 0x27, 0x70, 0xad,
-	0x59, 0x6b, 0x79, 0x79, 0x1c, 0x6b, 0xe4, 0x3b, 0xb0, 0x72, 0x82, 0x23, 0x9f, 0xa8, 0x6d, 0xf7,
-	0x44, 0x58, 0x23, 0xab, 0x92, 0xe0, 0xca, 0x7b, 0x09, 0xb0, 0x14, 0x3c, 0x71, 0x16, 0x8c, 0xb5,
-	0x37, 0x60, 0xe3, 0xde, 0x23, 0xd0, 0xd9, 0xd8, 0x20, 0xca, 0x96, 0x1b, 0x42, 0xb5, 0xbd, 0x15,
-	0x4c, 0xce, 0x95, 0x73, 0xb0, 0xf3, 0x5f, 0x0c, 0xa4, 0x26, 0x49, 0x1b, 0xbe, 0x36, 0x6a, 0xfb,
-	0x1b, 0x6d, 0xd2, 0xd1, 0x57, 0x60, 0xf0, 0xc1, 0x4a, 0xf6, 0x56, 0xc7, 0xac, 0xd8, 0x5a, 0xdd,
-	0x34, 0x7b, 0x8f, 0x35, 0xf2, 0x0d, 0x18, 0x7c, 0xf4, 0x2f, 0x76, 0xe5, 0xbf, 0x26, 0x16, 0xbb,
-	0x56, 0xbe, 0x0e, 0xea, 0xda, 0xb1, 0xd6, 0x2a, 0xff, 0x62, 0x36, 0xbe, 0xe5, 0xc6, 0xeb, 0x6d,
-	0xde, 0x04, 0x27, 0xff, 0x07, 0x00, 0x00, 0xff, 0xff, 0x06, 0x72, 0xd5, 0x5b, 0x61, 0x09, 0x00,
-	0x00,
+func (m *HeroDiff) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HeroDiff.Unmarshal(m, b)
+}
+func (m *HeroDiff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HeroDiff.Marshal(b, m, deterministic)
+}
+func (m *HeroDiff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HeroDiff.Merge(m, src)
+}
+func (m *HeroDiff) XXX_Size() int {
+	return xxx_messageInfo_HeroDiff.Size(m)
+}
+func (m *HeroDiff) XXX_DiscardUnknown() {
+	xxx_messageInfo_HeroDiff.DiscardUnknown(m)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+var xxx_messageInfo_HeroDiff proto.InternalMessageInfo
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *HeroDiff) GetBefore() *Hero {
+	if m != nil {
+		return m.Before
+	}
+	return nil
+}
 
-// FightSvcClient is the client API for FightSvc service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type FightSvcClient interface {
-	// here stream is used to trans a big mount of data.
-	ListHeros(ctx context.Context, in *ListHerosRequest, opts ...grpc.CallOption) (FightSvc_ListHerosClient, error)
-	LoadSession(ctx context.Context, in *LoadSessionRequest, opts ...grpc.CallOption) (*SessionView, error)
-	SelectHero(ctx context.Context, in *SelectHeroRequest, opts ...grpc.CallOption) (*SessionView, error)
-	Game(ctx context.Context, in *GameRequest, opts ...grpc.CallOption) (*GameResponse, error)
-	ClearSession(ctx context.Context, in *ClearSessionRequest, opts ...grpc.CallOption) (*ClearSessionResponse, error)
-	// here stream is used to keep the long-alive connection and get real top10
-	Top10(ctx context.Context, in *Top10Request, opts ...grpc.CallOption) (FightSvc_Top10Client, error)
-	Admin(ctx context.Context, opts ...grpc.CallOption) (FightSvc_AdminClient, error)
+func (m *HeroDiff) GetAfter() *Hero {
+	if m != nil {
+		return m.After
+	}
+	return nil
 }
 
-type fightSvcClient struct {
-	cc *grpc.ClientConn
+// the result of an admin request, sent once it is done
+type AdminResult struct {
+	RequestId string `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// the grpc status code, OK on success
+	Code  int32  `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	// the number of the heros or bosses applied
+	Applied int32 `protobuf:"varint,4,opt,name=applied,proto3" json:"applied,omitempty"`
+	// the changes of a balance patch, previewed or applied, or reverted
+	Diff []*HeroDiff `protobuf:"bytes,5,rep,name=diff,proto3" json:"diff,omitempty"`
+	// the id of the applied balance patch
+	PatchId int64 `protobuf:"varint,6,opt,name=patch_id,json=patchId,proto3" json:"patch_id,omitempty"`
+	// the session rebuilt by REPLAY_SESSION
+	Session              *SessionView `protobuf:"bytes,7,opt,name=session,proto3" json:"session,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
 }
 
-func NewFightSvcClient(cc *grpc.ClientConn) FightSvcClient {
-	return &fightSvcClient{cc}
+func (m *AdminResult) Reset()         { *m = AdminResult{} }
+func (m *AdminResult) String() string { return proto.CompactTextString(m) }
+func (*AdminResult) ProtoMessage()    {}
+func (*AdminResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{24}
 }
 
-func (c *fightSvcClient) ListHeros(ctx context.Context, in *ListHerosRequest, opts ...grpc.CallOption) (FightSvc_ListHerosClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_FightSvc_serviceDesc.Streams[0], "/fight.FightSvc/ListHeros", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &fightSvcListHerosClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
+func (m *AdminResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdminResult.Unmarshal(m, b)
+}
+func (m *AdminResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdminResult.Marshal(b, m, deterministic)
+}
+func (m *AdminResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdminResult.Merge(m, src)
+}
+func (m *AdminResult) XXX_Size() int {
+	return xxx_messageInfo_AdminResult.Size(m)
+}
+func (m *AdminResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdminResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AdminResult proto.InternalMessageInfo
+
+func (m *AdminResult) GetRequestId() string {
+	if m != nil {
+		return m.RequestId
+	}
+	return ""
+}
+
+func (m *AdminResult) GetCode() int32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *AdminResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *AdminResult) GetApplied() int32 {
+	if m != nil {
+		return m.Applied
+	}
+	return 0
+}
+
+func (m *AdminResult) GetDiff() []*HeroDiff {
+	if m != nil {
+		return m.Diff
+	}
+	return nil
+}
+
+func (m *AdminResult) GetPatchId() int64 {
+	if m != nil {
+		return m.PatchId
+	}
+	return 0
+}
+
+func (m *AdminResult) GetSession() *SessionView {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+// a change of a row of the hero table, old is not set for an INSERT and
+// new for a DELETE
+type HeroChange struct {
+	Action               string   `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	Old                  *Hero    `protobuf:"bytes,2,opt,name=old,proto3" json:"old,omitempty"`
+	New                  *Hero    `protobuf:"bytes,3,opt,name=new,proto3" json:"new,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HeroChange) Reset()         { *m = HeroChange{} }
+func (m *HeroChange) String() string { return proto.CompactTextString(m) }
+func (*HeroChange) ProtoMessage()    {}
+func (*HeroChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{25}
+}
+
+func (m *HeroChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HeroChange.Unmarshal(m, b)
+}
+func (m *HeroChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HeroChange.Marshal(b, m, deterministic)
+}
+func (m *HeroChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HeroChange.Merge(m, src)
+}
+func (m *HeroChange) XXX_Size() int {
+	return xxx_messageInfo_HeroChange.Size(m)
+}
+func (m *HeroChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_HeroChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HeroChange proto.InternalMessageInfo
+
+func (m *HeroChange) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *HeroChange) GetOld() *Hero {
+	if m != nil {
+		return m.Old
+	}
+	return nil
+}
+
+func (m *HeroChange) GetNew() *Hero {
+	if m != nil {
+		return m.New
+	}
+	return nil
+}
+
+type BossChange struct {
+	Action               string   `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	Old                  *Boss    `protobuf:"bytes,2,opt,name=old,proto3" json:"old,omitempty"`
+	New                  *Boss    `protobuf:"bytes,3,opt,name=new,proto3" json:"new,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BossChange) Reset()         { *m = BossChange{} }
+func (m *BossChange) String() string { return proto.CompactTextString(m) }
+func (*BossChange) ProtoMessage()    {}
+func (*BossChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{26}
+}
+
+func (m *BossChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BossChange.Unmarshal(m, b)
+}
+func (m *BossChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BossChange.Marshal(b, m, deterministic)
+}
+func (m *BossChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BossChange.Merge(m, src)
+}
+func (m *BossChange) XXX_Size() int {
+	return xxx_messageInfo_BossChange.Size(m)
+}
+func (m *BossChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_BossChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BossChange proto.InternalMessageInfo
+
+func (m *BossChange) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *BossChange) GetOld() *Boss {
+	if m != nil {
+		return m.Old
+	}
+	return nil
+}
+
+func (m *BossChange) GetNew() *Boss {
+	if m != nil {
+		return m.New
+	}
+	return nil
+}
+
+// a change of a row of the session table, the archived sessions
+type SessionChange struct {
+	Action               string   `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	Old                  *Session `protobuf:"bytes,2,opt,name=old,proto3" json:"old,omitempty"`
+	New                  *Session `protobuf:"bytes,3,opt,name=new,proto3" json:"new,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SessionChange) Reset()         { *m = SessionChange{} }
+func (m *SessionChange) String() string { return proto.CompactTextString(m) }
+func (*SessionChange) ProtoMessage()    {}
+func (*SessionChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{27}
+}
+
+func (m *SessionChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionChange.Unmarshal(m, b)
+}
+func (m *SessionChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionChange.Marshal(b, m, deterministic)
+}
+func (m *SessionChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionChange.Merge(m, src)
+}
+func (m *SessionChange) XXX_Size() int {
+	return xxx_messageInfo_SessionChange.Size(m)
+}
+func (m *SessionChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionChange proto.InternalMessageInfo
+
+func (m *SessionChange) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *SessionChange) GetOld() *Session {
+	if m != nil {
+		return m.Old
+	}
+	return nil
+}
+
+func (m *SessionChange) GetNew() *Session {
+	if m != nil {
+		return m.New
+	}
+	return nil
+}
+
+// carries either a change of a table or the result of a request
+type AdminResponse struct {
+	// the new row of a hero change, or the old one of a delete, see hero
+	Heros []*Hero `protobuf:"bytes,1,rep,name=heros,proto3" json:"heros,omitempty"`
+	// the new row of a boss change, or the old one of a delete, see boss
+	Bosses []*Boss      `protobuf:"bytes,2,rep,name=bosses,proto3" json:"bosses,omitempty"`
+	Result *AdminResult `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
+	// Types that are valid to be assigned to Change:
+	//	*AdminResponse_Hero
+	//	*AdminResponse_Boss
+	//	*AdminResponse_Session
+	Change isAdminResponse_Change `protobuf_oneof:"change"`
+	// the events listener reconnected, the changes in the meantime are
+	// lost: reload the heros and the bosses
+	Resync               bool     `protobuf:"varint,7,opt,name=resync,proto3" json:"resync,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AdminResponse) Reset()         { *m = AdminResponse{} }
+func (m *AdminResponse) String() string { return proto.CompactTextString(m) }
+func (*AdminResponse) ProtoMessage()    {}
+func (*AdminResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{28}
+}
+
+func (m *AdminResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AdminResponse.Unmarshal(m, b)
+}
+func (m *AdminResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AdminResponse.Marshal(b, m, deterministic)
+}
+func (m *AdminResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AdminResponse.Merge(m, src)
+}
+func (m *AdminResponse) XXX_Size() int {
+	return xxx_messageInfo_AdminResponse.Size(m)
+}
+func (m *AdminResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AdminResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AdminResponse proto.InternalMessageInfo
+
+func (m *AdminResponse) GetHeros() []*Hero {
+	if m != nil {
+		return m.Heros
+	}
+	return nil
+}
+
+func (m *AdminResponse) GetBosses() []*Boss {
+	if m != nil {
+		return m.Bosses
+	}
+	return nil
+}
+
+func (m *AdminResponse) GetResult() *AdminResult {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+type isAdminResponse_Change interface {
+	isAdminResponse_Change()
+}
+
+type AdminResponse_Hero struct {
+	Hero *HeroChange `protobuf:"bytes,4,opt,name=hero,proto3,oneof"`
+}
+
+type AdminResponse_Boss struct {
+	Boss *BossChange `protobuf:"bytes,5,opt,name=boss,proto3,oneof"`
+}
+
+type AdminResponse_Session struct {
+	Session *SessionChange `protobuf:"bytes,6,opt,name=session,proto3,oneof"`
+}
+
+func (*AdminResponse_Hero) isAdminResponse_Change() {}
+
+func (*AdminResponse_Boss) isAdminResponse_Change() {}
+
+func (*AdminResponse_Session) isAdminResponse_Change() {}
+
+func (m *AdminResponse) GetChange() isAdminResponse_Change {
+	if m != nil {
+		return m.Change
+	}
+	return nil
+}
+
+func (m *AdminResponse) GetHero() *HeroChange {
+	if x, ok := m.GetChange().(*AdminResponse_Hero); ok {
+		return x.Hero
+	}
+	return nil
+}
+
+func (m *AdminResponse) GetBoss() *BossChange {
+	if x, ok := m.GetChange().(*AdminResponse_Boss); ok {
+		return x.Boss
+	}
+	return nil
+}
+
+func (m *AdminResponse) GetSession() *SessionChange {
+	if x, ok := m.GetChange().(*AdminResponse_Session); ok {
+		return x.Session
+	}
+	return nil
+}
+
+func (m *AdminResponse) GetResync() bool {
+	if m != nil {
+		return m.Resync
+	}
+	return false
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*AdminResponse) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*AdminResponse_Hero)(nil),
+		(*AdminResponse_Boss)(nil),
+		(*AdminResponse_Session)(nil),
+	}
+}
+
+type Top10Request struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Top10Request) Reset()         { *m = Top10Request{} }
+func (m *Top10Request) String() string { return proto.CompactTextString(m) }
+func (*Top10Request) ProtoMessage()    {}
+func (*Top10Request) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{29}
+}
+
+func (m *Top10Request) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Top10Request.Unmarshal(m, b)
+}
+func (m *Top10Request) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Top10Request.Marshal(b, m, deterministic)
+}
+func (m *Top10Request) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Top10Request.Merge(m, src)
+}
+func (m *Top10Request) XXX_Size() int {
+	return xxx_messageInfo_Top10Request.Size(m)
+}
+func (m *Top10Request) XXX_DiscardUnknown() {
+	xxx_messageInfo_Top10Request.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Top10Request proto.InternalMessageInfo
+
+type Top10Response struct {
+	Players              []*Top10Response_Player `protobuf:"bytes,1,rep,name=players,proto3" json:"players,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *Top10Response) Reset()         { *m = Top10Response{} }
+func (m *Top10Response) String() string { return proto.CompactTextString(m) }
+func (*Top10Response) ProtoMessage()    {}
+func (*Top10Response) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{30}
+}
+
+func (m *Top10Response) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Top10Response.Unmarshal(m, b)
+}
+func (m *Top10Response) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Top10Response.Marshal(b, m, deterministic)
+}
+func (m *Top10Response) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Top10Response.Merge(m, src)
+}
+func (m *Top10Response) XXX_Size() int {
+	return xxx_messageInfo_Top10Response.Size(m)
+}
+func (m *Top10Response) XXX_DiscardUnknown() {
+	xxx_messageInfo_Top10Response.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Top10Response proto.InternalMessageInfo
+
+func (m *Top10Response) GetPlayers() []*Top10Response_Player {
+	if m != nil {
+		return m.Players
+	}
+	return nil
+}
+
+type Top10Response_Player struct {
+	// not set anymore, see display_name
+	Id    string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Score int32  `protobuf:"varint,2,opt,name=score,proto3" json:"score,omitempty"`
+	Level int32  `protobuf:"varint,3,opt,name=level,proto3" json:"level,omitempty"`
+	// the display name of the profile, or an anonymized label
+	DisplayName          string   `protobuf:"bytes,4,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Top10Response_Player) Reset()         { *m = Top10Response_Player{} }
+func (m *Top10Response_Player) String() string { return proto.CompactTextString(m) }
+func (*Top10Response_Player) ProtoMessage()    {}
+func (*Top10Response_Player) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{30, 0}
+}
+
+func (m *Top10Response_Player) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Top10Response_Player.Unmarshal(m, b)
+}
+func (m *Top10Response_Player) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Top10Response_Player.Marshal(b, m, deterministic)
+}
+func (m *Top10Response_Player) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Top10Response_Player.Merge(m, src)
+}
+func (m *Top10Response_Player) XXX_Size() int {
+	return xxx_messageInfo_Top10Response_Player.Size(m)
+}
+func (m *Top10Response_Player) XXX_DiscardUnknown() {
+	xxx_messageInfo_Top10Response_Player.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Top10Response_Player proto.InternalMessageInfo
+
+func (m *Top10Response_Player) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Top10Response_Player) GetScore() int32 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+func (m *Top10Response_Player) GetLevel() int32 {
+	if m != nil {
+		return m.Level
+	}
+	return 0
+}
+
+func (m *Top10Response_Player) GetDisplayName() string {
+	if m != nil {
+		return m.DisplayName
+	}
+	return ""
+}
+
+type LeaderboardRequest struct {
+	// number of the entries of the page, 10 by default, 100 at most
+	Limit  int32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset int32 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// if set, the rank of the player and its neighbours are returned as well
+	PlayerId string `protobuf:"bytes,3,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	// number of the neighbours above and below the player, 2 by default
+	Neighbours int32                     `protobuf:"varint,4,opt,name=neighbours,proto3" json:"neighbours,omitempty"`
+	Window     LeaderboardRequest_Window `protobuf:"varint,5,opt,name=window,proto3,enum=fight.LeaderboardRequest_Window" json:"window,omitempty"`
+	// the period of the DAILY, WEEKLY or SEASON window, e.g. '2021-03-11',
+	// '2021-W10' or '2021-Q1', the current one by default
+	Period               string   `protobuf:"bytes,6,opt,name=period,proto3" json:"period,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LeaderboardRequest) Reset()         { *m = LeaderboardRequest{} }
+func (m *LeaderboardRequest) String() string { return proto.CompactTextString(m) }
+func (*LeaderboardRequest) ProtoMessage()    {}
+func (*LeaderboardRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{31}
+}
+
+func (m *LeaderboardRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LeaderboardRequest.Unmarshal(m, b)
+}
+func (m *LeaderboardRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LeaderboardRequest.Marshal(b, m, deterministic)
+}
+func (m *LeaderboardRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LeaderboardRequest.Merge(m, src)
+}
+func (m *LeaderboardRequest) XXX_Size() int {
+	return xxx_messageInfo_LeaderboardRequest.Size(m)
+}
+func (m *LeaderboardRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LeaderboardRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LeaderboardRequest proto.InternalMessageInfo
+
+func (m *LeaderboardRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *LeaderboardRequest) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *LeaderboardRequest) GetPlayerId() string {
+	if m != nil {
+		return m.PlayerId
+	}
+	return ""
+}
+
+func (m *LeaderboardRequest) GetNeighbours() int32 {
+	if m != nil {
+		return m.Neighbours
+	}
+	return 0
+}
+
+func (m *LeaderboardRequest) GetWindow() LeaderboardRequest_Window {
+	if m != nil {
+		return m.Window
+	}
+	return LeaderboardRequest_PLAYING_NOW
+}
+
+func (m *LeaderboardRequest) GetPeriod() string {
+	if m != nil {
+		return m.Period
+	}
+	return ""
+}
+
+type WatchLeaderboardRequest struct {
+	// the first request sets the watched part of the ranking, 10 entries by
+	// default, 100 at most
+	Limit  int32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset int32 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// asks for a new snapshot, e.g. after a gap in the sequence numbers
+	Resync               bool     `protobuf:"varint,3,opt,name=resync,proto3" json:"resync,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchLeaderboardRequest) Reset()         { *m = WatchLeaderboardRequest{} }
+func (m *WatchLeaderboardRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchLeaderboardRequest) ProtoMessage()    {}
+func (*WatchLeaderboardRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{32}
+}
+
+func (m *WatchLeaderboardRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchLeaderboardRequest.Unmarshal(m, b)
+}
+func (m *WatchLeaderboardRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchLeaderboardRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchLeaderboardRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchLeaderboardRequest.Merge(m, src)
+}
+func (m *WatchLeaderboardRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchLeaderboardRequest.Size(m)
+}
+func (m *WatchLeaderboardRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchLeaderboardRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchLeaderboardRequest proto.InternalMessageInfo
+
+func (m *WatchLeaderboardRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *WatchLeaderboardRequest) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *WatchLeaderboardRequest) GetResync() bool {
+	if m != nil {
+		return m.Resync
+	}
+	return false
+}
+
+type LeaderboardDelta struct {
+	Type LeaderboardDelta_Type `protobuf:"varint,1,opt,name=type,proto3,enum=fight.LeaderboardDelta_Type" json:"type,omitempty"`
+	// the new entry of INSERT and MOVE, the player key and the display name
+	// only for REMOVE
+	Entry *LeaderboardEntry `protobuf:"bytes,2,opt,name=entry,proto3" json:"entry,omitempty"`
+	// the previous rank for REMOVE and MOVE
+	FromRank             int32    `protobuf:"varint,3,opt,name=from_rank,json=fromRank,proto3" json:"from_rank,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LeaderboardDelta) Reset()         { *m = LeaderboardDelta{} }
+func (m *LeaderboardDelta) String() string { return proto.CompactTextString(m) }
+func (*LeaderboardDelta) ProtoMessage()    {}
+func (*LeaderboardDelta) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{33}
+}
+
+func (m *LeaderboardDelta) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LeaderboardDelta.Unmarshal(m, b)
+}
+func (m *LeaderboardDelta) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LeaderboardDelta.Marshal(b, m, deterministic)
+}
+func (m *LeaderboardDelta) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LeaderboardDelta.Merge(m, src)
+}
+func (m *LeaderboardDelta) XXX_Size() int {
+	return xxx_messageInfo_LeaderboardDelta.Size(m)
+}
+func (m *LeaderboardDelta) XXX_DiscardUnknown() {
+	xxx_messageInfo_LeaderboardDelta.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LeaderboardDelta proto.InternalMessageInfo
+
+func (m *LeaderboardDelta) GetType() LeaderboardDelta_Type {
+	if m != nil {
+		return m.Type
+	}
+	return LeaderboardDelta_INSERT
+}
+
+func (m *LeaderboardDelta) GetEntry() *LeaderboardEntry {
+	if m != nil {
+		return m.Entry
+	}
+	return nil
+}
+
+func (m *LeaderboardDelta) GetFromRank() int32 {
+	if m != nil {
+		return m.FromRank
+	}
+	return 0
+}
+
+type LeaderboardUpdate struct {
+	// incremented by every update of the stream
+	Seq uint64 `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	// the update replaces the entries instead of changing them
+	Snapshot bool                `protobuf:"varint,2,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	Entries  []*LeaderboardEntry `protobuf:"bytes,3,rep,name=entries,proto3" json:"entries,omitempty"`
+	// applied by the display name of the entries, the removals come first
+	Deltas               []*LeaderboardDelta `protobuf:"bytes,4,rep,name=deltas,proto3" json:"deltas,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *LeaderboardUpdate) Reset()         { *m = LeaderboardUpdate{} }
+func (m *LeaderboardUpdate) String() string { return proto.CompactTextString(m) }
+func (*LeaderboardUpdate) ProtoMessage()    {}
+func (*LeaderboardUpdate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{34}
+}
+
+func (m *LeaderboardUpdate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LeaderboardUpdate.Unmarshal(m, b)
+}
+func (m *LeaderboardUpdate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LeaderboardUpdate.Marshal(b, m, deterministic)
+}
+func (m *LeaderboardUpdate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LeaderboardUpdate.Merge(m, src)
+}
+func (m *LeaderboardUpdate) XXX_Size() int {
+	return xxx_messageInfo_LeaderboardUpdate.Size(m)
+}
+func (m *LeaderboardUpdate) XXX_DiscardUnknown() {
+	xxx_messageInfo_LeaderboardUpdate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LeaderboardUpdate proto.InternalMessageInfo
+
+func (m *LeaderboardUpdate) GetSeq() uint64 {
+	if m != nil {
+		return m.Seq
+	}
+	return 0
+}
+
+func (m *LeaderboardUpdate) GetSnapshot() bool {
+	if m != nil {
+		return m.Snapshot
+	}
+	return false
+}
+
+func (m *LeaderboardUpdate) GetEntries() []*LeaderboardEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+func (m *LeaderboardUpdate) GetDeltas() []*LeaderboardDelta {
+	if m != nil {
+		return m.Deltas
+	}
+	return nil
+}
+
+type HeroLeaderboardRequest struct {
+	HeroName             string   `protobuf:"bytes,1,opt,name=hero_name,json=heroName,proto3" json:"hero_name,omitempty"`
+	Limit                int32    `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset               int32    `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	PlayerId             string   `protobuf:"bytes,4,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Neighbours           int32    `protobuf:"varint,5,opt,name=neighbours,proto3" json:"neighbours,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HeroLeaderboardRequest) Reset()         { *m = HeroLeaderboardRequest{} }
+func (m *HeroLeaderboardRequest) String() string { return proto.CompactTextString(m) }
+func (*HeroLeaderboardRequest) ProtoMessage()    {}
+func (*HeroLeaderboardRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{35}
+}
+
+func (m *HeroLeaderboardRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HeroLeaderboardRequest.Unmarshal(m, b)
+}
+func (m *HeroLeaderboardRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HeroLeaderboardRequest.Marshal(b, m, deterministic)
+}
+func (m *HeroLeaderboardRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HeroLeaderboardRequest.Merge(m, src)
+}
+func (m *HeroLeaderboardRequest) XXX_Size() int {
+	return xxx_messageInfo_HeroLeaderboardRequest.Size(m)
+}
+func (m *HeroLeaderboardRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_HeroLeaderboardRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HeroLeaderboardRequest proto.InternalMessageInfo
+
+func (m *HeroLeaderboardRequest) GetHeroName() string {
+	if m != nil {
+		return m.HeroName
+	}
+	return ""
+}
+
+func (m *HeroLeaderboardRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *HeroLeaderboardRequest) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *HeroLeaderboardRequest) GetPlayerId() string {
+	if m != nil {
+		return m.PlayerId
+	}
+	return ""
+}
+
+func (m *HeroLeaderboardRequest) GetNeighbours() int32 {
+	if m != nil {
+		return m.Neighbours
+	}
+	return 0
+}
+
+type LevelLeaderboardRequest struct {
+	Level                int32    `protobuf:"varint,1,opt,name=level,proto3" json:"level,omitempty"`
+	Limit                int32    `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset               int32    `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	PlayerId             string   `protobuf:"bytes,4,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Neighbours           int32    `protobuf:"varint,5,opt,name=neighbours,proto3" json:"neighbours,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LevelLeaderboardRequest) Reset()         { *m = LevelLeaderboardRequest{} }
+func (m *LevelLeaderboardRequest) String() string { return proto.CompactTextString(m) }
+func (*LevelLeaderboardRequest) ProtoMessage()    {}
+func (*LevelLeaderboardRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{36}
+}
+
+func (m *LevelLeaderboardRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LevelLeaderboardRequest.Unmarshal(m, b)
+}
+func (m *LevelLeaderboardRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LevelLeaderboardRequest.Marshal(b, m, deterministic)
+}
+func (m *LevelLeaderboardRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LevelLeaderboardRequest.Merge(m, src)
+}
+func (m *LevelLeaderboardRequest) XXX_Size() int {
+	return xxx_messageInfo_LevelLeaderboardRequest.Size(m)
+}
+func (m *LevelLeaderboardRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LevelLeaderboardRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LevelLeaderboardRequest proto.InternalMessageInfo
+
+func (m *LevelLeaderboardRequest) GetLevel() int32 {
+	if m != nil {
+		return m.Level
+	}
+	return 0
+}
+
+func (m *LevelLeaderboardRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *LevelLeaderboardRequest) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *LevelLeaderboardRequest) GetPlayerId() string {
+	if m != nil {
+		return m.PlayerId
+	}
+	return ""
+}
+
+func (m *LevelLeaderboardRequest) GetNeighbours() int32 {
+	if m != nil {
+		return m.Neighbours
+	}
+	return 0
+}
+
+type LeaderboardEntry struct {
+	// 1-based
+	Rank int32 `protobuf:"varint,1,opt,name=rank,proto3" json:"rank,omitempty"`
+	// set for the requested player only
+	Id    string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Score int32  `protobuf:"varint,3,opt,name=score,proto3" json:"score,omitempty"`
+	Level int32  `protobuf:"varint,4,opt,name=level,proto3" json:"level,omitempty"`
+	// set by the per-hero and the per-level leaderboards
+	HeroName string `protobuf:"bytes,5,opt,name=hero_name,json=heroName,proto3" json:"hero_name,omitempty"`
+	// set by the per-level leaderboards
+	ClearTime *duration.Duration `protobuf:"bytes,6,opt,name=clear_time,json=clearTime,proto3" json:"clear_time,omitempty"`
+	// the display name of the profile, or an anonymized label
+	DisplayName string `protobuf:"bytes,7,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// an opaque id of the player, stable across the renames, which matches
+	// the entries of the leaderboard deltas
+	PlayerKey            string   `protobuf:"bytes,8,opt,name=player_key,json=playerKey,proto3" json:"player_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LeaderboardEntry) Reset()         { *m = LeaderboardEntry{} }
+func (m *LeaderboardEntry) String() string { return proto.CompactTextString(m) }
+func (*LeaderboardEntry) ProtoMessage()    {}
+func (*LeaderboardEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{37}
+}
+
+func (m *LeaderboardEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LeaderboardEntry.Unmarshal(m, b)
+}
+func (m *LeaderboardEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LeaderboardEntry.Marshal(b, m, deterministic)
+}
+func (m *LeaderboardEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LeaderboardEntry.Merge(m, src)
+}
+func (m *LeaderboardEntry) XXX_Size() int {
+	return xxx_messageInfo_LeaderboardEntry.Size(m)
+}
+func (m *LeaderboardEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_LeaderboardEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LeaderboardEntry proto.InternalMessageInfo
+
+func (m *LeaderboardEntry) GetRank() int32 {
+	if m != nil {
+		return m.Rank
+	}
+	return 0
+}
+
+func (m *LeaderboardEntry) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *LeaderboardEntry) GetScore() int32 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+func (m *LeaderboardEntry) GetLevel() int32 {
+	if m != nil {
+		return m.Level
+	}
+	return 0
+}
+
+func (m *LeaderboardEntry) GetHeroName() string {
+	if m != nil {
+		return m.HeroName
+	}
+	return ""
+}
+
+func (m *LeaderboardEntry) GetClearTime() *duration.Duration {
+	if m != nil {
+		return m.ClearTime
+	}
+	return nil
+}
+
+func (m *LeaderboardEntry) GetDisplayName() string {
+	if m != nil {
+		return m.DisplayName
+	}
+	return ""
+}
+
+func (m *LeaderboardEntry) GetPlayerKey() string {
+	if m != nil {
+		return m.PlayerKey
+	}
+	return ""
+}
+
+type LeaderboardResponse struct {
+	Entries []*LeaderboardEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	// number of the ranked players
+	Total int32 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	// not set if the player is not ranked
+	Player     *LeaderboardEntry   `protobuf:"bytes,3,opt,name=player,proto3" json:"player,omitempty"`
+	Neighbours []*LeaderboardEntry `protobuf:"bytes,4,rep,name=neighbours,proto3" json:"neighbours,omitempty"`
+	// the period of the DAILY, WEEKLY or SEASON window
+	Period               string   `protobuf:"bytes,5,opt,name=period,proto3" json:"period,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LeaderboardResponse) Reset()         { *m = LeaderboardResponse{} }
+func (m *LeaderboardResponse) String() string { return proto.CompactTextString(m) }
+func (*LeaderboardResponse) ProtoMessage()    {}
+func (*LeaderboardResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{38}
+}
+
+func (m *LeaderboardResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LeaderboardResponse.Unmarshal(m, b)
+}
+func (m *LeaderboardResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LeaderboardResponse.Marshal(b, m, deterministic)
+}
+func (m *LeaderboardResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LeaderboardResponse.Merge(m, src)
+}
+func (m *LeaderboardResponse) XXX_Size() int {
+	return xxx_messageInfo_LeaderboardResponse.Size(m)
+}
+func (m *LeaderboardResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LeaderboardResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LeaderboardResponse proto.InternalMessageInfo
+
+func (m *LeaderboardResponse) GetEntries() []*LeaderboardEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+func (m *LeaderboardResponse) GetTotal() int32 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *LeaderboardResponse) GetPlayer() *LeaderboardEntry {
+	if m != nil {
+		return m.Player
+	}
+	return nil
+}
+
+func (m *LeaderboardResponse) GetNeighbours() []*LeaderboardEntry {
+	if m != nil {
+		return m.Neighbours
+	}
+	return nil
+}
+
+func (m *LeaderboardResponse) GetPeriod() string {
+	if m != nil {
+		return m.Period
+	}
+	return ""
+}
+
+type GameRequest struct {
+	Type                 Type     `protobuf:"varint,1,opt,name=type,proto3,enum=fight.Type" json:"type,omitempty"`
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GameRequest) Reset()         { *m = GameRequest{} }
+func (m *GameRequest) String() string { return proto.CompactTextString(m) }
+func (*GameRequest) ProtoMessage()    {}
+func (*GameRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{39}
+}
+
+func (m *GameRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GameRequest.Unmarshal(m, b)
+}
+func (m *GameRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GameRequest.Marshal(b, m, deterministic)
+}
+func (m *GameRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GameRequest.Merge(m, src)
+}
+func (m *GameRequest) XXX_Size() int {
+	return xxx_messageInfo_GameRequest.Size(m)
+}
+func (m *GameRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GameRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GameRequest proto.InternalMessageInfo
+
+func (m *GameRequest) GetType() Type {
+	if m != nil {
+		return m.Type
+	}
+	return Type_FIGHT
+}
+
+func (m *GameRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type GameResponse struct {
+	Type Type `protobuf:"varint,1,opt,name=type,proto3,enum=fight.Type" json:"type,omitempty"`
+	// Types that are valid to be assigned to Value:
+	//	*GameResponse_Fight
+	//	*GameResponse_Archive
+	//	*GameResponse_Level
+	//	*GameResponse_Quit
+	Value                isGameResponse_Value `protobuf_oneof:"value"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *GameResponse) Reset()         { *m = GameResponse{} }
+func (m *GameResponse) String() string { return proto.CompactTextString(m) }
+func (*GameResponse) ProtoMessage()    {}
+func (*GameResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{40}
+}
+
+func (m *GameResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GameResponse.Unmarshal(m, b)
+}
+func (m *GameResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GameResponse.Marshal(b, m, deterministic)
+}
+func (m *GameResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GameResponse.Merge(m, src)
+}
+func (m *GameResponse) XXX_Size() int {
+	return xxx_messageInfo_GameResponse.Size(m)
+}
+func (m *GameResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GameResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GameResponse proto.InternalMessageInfo
+
+func (m *GameResponse) GetType() Type {
+	if m != nil {
+		return m.Type
+	}
+	return Type_FIGHT
+}
+
+type isGameResponse_Value interface {
+	isGameResponse_Value()
+}
+
+type GameResponse_Fight struct {
+	Fight *Fight `protobuf:"bytes,2,opt,name=fight,proto3,oneof"`
+}
+
+type GameResponse_Archive struct {
+	Archive *Archive `protobuf:"bytes,3,opt,name=archive,proto3,oneof"`
+}
+
+type GameResponse_Level struct {
+	Level *Level `protobuf:"bytes,4,opt,name=level,proto3,oneof"`
+}
+
+type GameResponse_Quit struct {
+	Quit *Quit `protobuf:"bytes,5,opt,name=quit,proto3,oneof"`
+}
+
+func (*GameResponse_Fight) isGameResponse_Value() {}
+
+func (*GameResponse_Archive) isGameResponse_Value() {}
+
+func (*GameResponse_Level) isGameResponse_Value() {}
+
+func (*GameResponse_Quit) isGameResponse_Value() {}
+
+func (m *GameResponse) GetValue() isGameResponse_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *GameResponse) GetFight() *Fight {
+	if x, ok := m.GetValue().(*GameResponse_Fight); ok {
+		return x.Fight
+	}
+	return nil
+}
+
+func (m *GameResponse) GetArchive() *Archive {
+	if x, ok := m.GetValue().(*GameResponse_Archive); ok {
+		return x.Archive
+	}
+	return nil
+}
+
+func (m *GameResponse) GetLevel() *Level {
+	if x, ok := m.GetValue().(*GameResponse_Level); ok {
+		return x.Level
+	}
+	return nil
+}
+
+func (m *GameResponse) GetQuit() *Quit {
+	if x, ok := m.GetValue().(*GameResponse_Quit); ok {
+		return x.Quit
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*GameResponse) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*GameResponse_Fight)(nil),
+		(*GameResponse_Archive)(nil),
+		(*GameResponse_Level)(nil),
+		(*GameResponse_Quit)(nil),
+	}
+}
+
+type Fight struct {
+	GameOver             bool     `protobuf:"varint,1,opt,name=game_over,json=gameOver,proto3" json:"game_over,omitempty"`
+	NextLevel            bool     `protobuf:"varint,2,opt,name=next_level,json=nextLevel,proto3" json:"next_level,omitempty"`
+	Score                int32    `protobuf:"varint,3,opt,name=score,proto3" json:"score,omitempty"`
+	HeroBlood            int32    `protobuf:"varint,4,opt,name=hero_blood,json=heroBlood,proto3" json:"hero_blood,omitempty"`
+	BossBlood            int32    `protobuf:"varint,5,opt,name=boss_blood,json=bossBlood,proto3" json:"boss_blood,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Fight) Reset()         { *m = Fight{} }
+func (m *Fight) String() string { return proto.CompactTextString(m) }
+func (*Fight) ProtoMessage()    {}
+func (*Fight) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{41}
+}
+
+func (m *Fight) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Fight.Unmarshal(m, b)
+}
+func (m *Fight) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Fight.Marshal(b, m, deterministic)
+}
+func (m *Fight) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Fight.Merge(m, src)
+}
+func (m *Fight) XXX_Size() int {
+	return xxx_messageInfo_Fight.Size(m)
+}
+func (m *Fight) XXX_DiscardUnknown() {
+	xxx_messageInfo_Fight.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Fight proto.InternalMessageInfo
+
+func (m *Fight) GetGameOver() bool {
+	if m != nil {
+		return m.GameOver
+	}
+	return false
+}
+
+func (m *Fight) GetNextLevel() bool {
+	if m != nil {
+		return m.NextLevel
+	}
+	return false
+}
+
+func (m *Fight) GetScore() int32 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+func (m *Fight) GetHeroBlood() int32 {
+	if m != nil {
+		return m.HeroBlood
+	}
+	return 0
+}
+
+func (m *Fight) GetBossBlood() int32 {
+	if m != nil {
+		return m.BossBlood
+	}
+	return 0
+}
+
+type Archive struct {
+	Msg                  string   `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
+	SessionId            string   `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Archive) Reset()         { *m = Archive{} }
+func (m *Archive) String() string { return proto.CompactTextString(m) }
+func (*Archive) ProtoMessage()    {}
+func (*Archive) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{42}
+}
+
+func (m *Archive) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Archive.Unmarshal(m, b)
+}
+func (m *Archive) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Archive.Marshal(b, m, deterministic)
+}
+func (m *Archive) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Archive.Merge(m, src)
+}
+func (m *Archive) XXX_Size() int {
+	return xxx_messageInfo_Archive.Size(m)
+}
+func (m *Archive) XXX_DiscardUnknown() {
+	xxx_messageInfo_Archive.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Archive proto.InternalMessageInfo
+
+func (m *Archive) GetMsg() string {
+	if m != nil {
+		return m.Msg
+	}
+	return ""
+}
+
+func (m *Archive) GetSessionId() string {
+	if m != nil {
+		return m.SessionId
+	}
+	return ""
+}
+
+type Level struct {
+	Msg                  string   `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
+	Session              *Session `protobuf:"bytes,2,opt,name=session,proto3" json:"session,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Level) Reset()         { *m = Level{} }
+func (m *Level) String() string { return proto.CompactTextString(m) }
+func (*Level) ProtoMessage()    {}
+func (*Level) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{43}
+}
+
+func (m *Level) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Level.Unmarshal(m, b)
+}
+func (m *Level) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Level.Marshal(b, m, deterministic)
+}
+func (m *Level) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Level.Merge(m, src)
+}
+func (m *Level) XXX_Size() int {
+	return xxx_messageInfo_Level.Size(m)
+}
+func (m *Level) XXX_DiscardUnknown() {
+	xxx_messageInfo_Level.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Level proto.InternalMessageInfo
+
+func (m *Level) GetMsg() string {
+	if m != nil {
+		return m.Msg
+	}
+	return ""
+}
+
+func (m *Level) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+type Quit struct {
+	Msg                  string   `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Quit) Reset()         { *m = Quit{} }
+func (m *Quit) String() string { return proto.CompactTextString(m) }
+func (*Quit) ProtoMessage()    {}
+func (*Quit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{44}
+}
+
+func (m *Quit) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Quit.Unmarshal(m, b)
+}
+func (m *Quit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Quit.Marshal(b, m, deterministic)
+}
+func (m *Quit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Quit.Merge(m, src)
+}
+func (m *Quit) XXX_Size() int {
+	return xxx_messageInfo_Quit.Size(m)
+}
+func (m *Quit) XXX_DiscardUnknown() {
+	xxx_messageInfo_Quit.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Quit proto.InternalMessageInfo
+
+func (m *Quit) GetMsg() string {
+	if m != nil {
+		return m.Msg
+	}
+	return ""
+}
+
+type SelectHeroRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	HeroName             string   `protobuf:"bytes,2,opt,name=hero_name,json=heroName,proto3" json:"hero_name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SelectHeroRequest) Reset()         { *m = SelectHeroRequest{} }
+func (m *SelectHeroRequest) String() string { return proto.CompactTextString(m) }
+func (*SelectHeroRequest) ProtoMessage()    {}
+func (*SelectHeroRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{45}
+}
+
+func (m *SelectHeroRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SelectHeroRequest.Unmarshal(m, b)
+}
+func (m *SelectHeroRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SelectHeroRequest.Marshal(b, m, deterministic)
+}
+func (m *SelectHeroRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SelectHeroRequest.Merge(m, src)
+}
+func (m *SelectHeroRequest) XXX_Size() int {
+	return xxx_messageInfo_SelectHeroRequest.Size(m)
+}
+func (m *SelectHeroRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SelectHeroRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SelectHeroRequest proto.InternalMessageInfo
+
+func (m *SelectHeroRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *SelectHeroRequest) GetHeroName() string {
+	if m != nil {
+		return m.HeroName
+	}
+	return ""
+}
+
+type LoadSessionRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LoadSessionRequest) Reset()         { *m = LoadSessionRequest{} }
+func (m *LoadSessionRequest) String() string { return proto.CompactTextString(m) }
+func (*LoadSessionRequest) ProtoMessage()    {}
+func (*LoadSessionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{46}
+}
+
+func (m *LoadSessionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LoadSessionRequest.Unmarshal(m, b)
+}
+func (m *LoadSessionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LoadSessionRequest.Marshal(b, m, deterministic)
+}
+func (m *LoadSessionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LoadSessionRequest.Merge(m, src)
+}
+func (m *LoadSessionRequest) XXX_Size() int {
+	return xxx_messageInfo_LoadSessionRequest.Size(m)
+}
+func (m *LoadSessionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LoadSessionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LoadSessionRequest proto.InternalMessageInfo
+
+func (m *LoadSessionRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type SessionView struct {
+	Hero    *Hero    `protobuf:"bytes,1,opt,name=hero,proto3" json:"hero,omitempty"`
+	Boss    *Boss    `protobuf:"bytes,2,opt,name=boss,proto3" json:"boss,omitempty"`
+	Session *Session `protobuf:"bytes,3,opt,name=session,proto3" json:"session,omitempty"`
+	// returned by LoadSession only, the other requests of the session must
+	// present it in the 'x-session-token' metadata.
+	Token                string   `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SessionView) Reset()         { *m = SessionView{} }
+func (m *SessionView) String() string { return proto.CompactTextString(m) }
+func (*SessionView) ProtoMessage()    {}
+func (*SessionView) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{47}
+}
+
+func (m *SessionView) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionView.Unmarshal(m, b)
+}
+func (m *SessionView) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionView.Marshal(b, m, deterministic)
+}
+func (m *SessionView) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionView.Merge(m, src)
+}
+func (m *SessionView) XXX_Size() int {
+	return xxx_messageInfo_SessionView.Size(m)
+}
+func (m *SessionView) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionView.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionView proto.InternalMessageInfo
+
+func (m *SessionView) GetHero() *Hero {
+	if m != nil {
+		return m.Hero
+	}
+	return nil
+}
+
+func (m *SessionView) GetBoss() *Boss {
+	if m != nil {
+		return m.Boss
+	}
+	return nil
+}
+
+func (m *SessionView) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *SessionView) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type ListHerosRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListHerosRequest) Reset()         { *m = ListHerosRequest{} }
+func (m *ListHerosRequest) String() string { return proto.CompactTextString(m) }
+func (*ListHerosRequest) ProtoMessage()    {}
+func (*ListHerosRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{48}
+}
+
+func (m *ListHerosRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListHerosRequest.Unmarshal(m, b)
+}
+func (m *ListHerosRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListHerosRequest.Marshal(b, m, deterministic)
+}
+func (m *ListHerosRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListHerosRequest.Merge(m, src)
+}
+func (m *ListHerosRequest) XXX_Size() int {
+	return xxx_messageInfo_ListHerosRequest.Size(m)
+}
+func (m *ListHerosRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListHerosRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListHerosRequest proto.InternalMessageInfo
+
+type Hero struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Details              string   `protobuf:"bytes,2,opt,name=details,proto3" json:"details,omitempty"`
+	AttackPower          int32    `protobuf:"varint,3,opt,name=attack_power,json=attackPower,proto3" json:"attack_power,omitempty"`
+	DefensePower         int32    `protobuf:"varint,4,opt,name=defense_power,json=defensePower,proto3" json:"defense_power,omitempty"`
+	Blood                int32    `protobuf:"varint,5,opt,name=blood,proto3" json:"blood,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Hero) Reset()         { *m = Hero{} }
+func (m *Hero) String() string { return proto.CompactTextString(m) }
+func (*Hero) ProtoMessage()    {}
+func (*Hero) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{49}
+}
+
+func (m *Hero) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Hero.Unmarshal(m, b)
+}
+func (m *Hero) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Hero.Marshal(b, m, deterministic)
+}
+func (m *Hero) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Hero.Merge(m, src)
+}
+func (m *Hero) XXX_Size() int {
+	return xxx_messageInfo_Hero.Size(m)
+}
+func (m *Hero) XXX_DiscardUnknown() {
+	xxx_messageInfo_Hero.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Hero proto.InternalMessageInfo
+
+func (m *Hero) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Hero) GetDetails() string {
+	if m != nil {
+		return m.Details
+	}
+	return ""
+}
+
+func (m *Hero) GetAttackPower() int32 {
+	if m != nil {
+		return m.AttackPower
+	}
+	return 0
+}
+
+func (m *Hero) GetDefensePower() int32 {
+	if m != nil {
+		return m.DefensePower
+	}
+	return 0
+}
+
+func (m *Hero) GetBlood() int32 {
+	if m != nil {
+		return m.Blood
+	}
+	return 0
+}
+
+type Boss struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Details              string   `protobuf:"bytes,2,opt,name=details,proto3" json:"details,omitempty"`
+	AttackPower          int32    `protobuf:"varint,3,opt,name=attack_power,json=attackPower,proto3" json:"attack_power,omitempty"`
+	DefensePower         int32    `protobuf:"varint,4,opt,name=defense_power,json=defensePower,proto3" json:"defense_power,omitempty"`
+	Blood                int32    `protobuf:"varint,5,opt,name=blood,proto3" json:"blood,omitempty"`
+	Level                int32    `protobuf:"varint,6,opt,name=level,proto3" json:"level,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Boss) Reset()         { *m = Boss{} }
+func (m *Boss) String() string { return proto.CompactTextString(m) }
+func (*Boss) ProtoMessage()    {}
+func (*Boss) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{50}
+}
+
+func (m *Boss) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Boss.Unmarshal(m, b)
+}
+func (m *Boss) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Boss.Marshal(b, m, deterministic)
+}
+func (m *Boss) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Boss.Merge(m, src)
+}
+func (m *Boss) XXX_Size() int {
+	return xxx_messageInfo_Boss.Size(m)
+}
+func (m *Boss) XXX_DiscardUnknown() {
+	xxx_messageInfo_Boss.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Boss proto.InternalMessageInfo
+
+func (m *Boss) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Boss) GetDetails() string {
+	if m != nil {
+		return m.Details
+	}
+	return ""
+}
+
+func (m *Boss) GetAttackPower() int32 {
+	if m != nil {
+		return m.AttackPower
+	}
+	return 0
+}
+
+func (m *Boss) GetDefensePower() int32 {
+	if m != nil {
+		return m.DefensePower
+	}
+	return 0
+}
+
+func (m *Boss) GetBlood() int32 {
+	if m != nil {
+		return m.Blood
+	}
+	return 0
+}
+
+func (m *Boss) GetLevel() int32 {
+	if m != nil {
+		return m.Level
+	}
+	return 0
+}
+
+type Session struct {
+	UID           string               `protobuf:"bytes,1,opt,name=UID,proto3" json:"UID,omitempty"`
+	HeroName      string               `protobuf:"bytes,2,opt,name=hero_name,json=heroName,proto3" json:"hero_name,omitempty"`
+	LiveHeroBlood int32                `protobuf:"varint,3,opt,name=live_hero_blood,json=liveHeroBlood,proto3" json:"live_hero_blood,omitempty"`
+	LiveBossBlood int32                `protobuf:"varint,4,opt,name=live_boss_blood,json=liveBossBlood,proto3" json:"live_boss_blood,omitempty"`
+	CurrentLevel  int32                `protobuf:"varint,5,opt,name=current_level,json=currentLevel,proto3" json:"current_level,omitempty"`
+	Score         int32                `protobuf:"varint,6,opt,name=score,proto3" json:"score,omitempty"`
+	ArchiveDate   *timestamp.Timestamp `protobuf:"bytes,7,opt,name=archive_date,json=archiveDate,proto3" json:"archive_date,omitempty"`
+	// the time the session started fighting the boss of the current level
+	LevelStartedAt *timestamp.Timestamp `protobuf:"bytes,8,opt,name=level_started_at,json=levelStartedAt,proto3" json:"level_started_at,omitempty"`
+	// the time the score was reached, it breaks the ties of the ranking
+	ScoreUpdatedAt       *timestamp.Timestamp `protobuf:"bytes,9,opt,name=score_updated_at,json=scoreUpdatedAt,proto3" json:"score_updated_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Session) Reset()         { *m = Session{} }
+func (m *Session) String() string { return proto.CompactTextString(m) }
+func (*Session) ProtoMessage()    {}
+func (*Session) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{51}
+}
+
+func (m *Session) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Session.Unmarshal(m, b)
+}
+func (m *Session) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Session.Marshal(b, m, deterministic)
+}
+func (m *Session) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Session.Merge(m, src)
+}
+func (m *Session) XXX_Size() int {
+	return xxx_messageInfo_Session.Size(m)
+}
+func (m *Session) XXX_DiscardUnknown() {
+	xxx_messageInfo_Session.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Session proto.InternalMessageInfo
+
+func (m *Session) GetUID() string {
+	if m != nil {
+		return m.UID
+	}
+	return ""
+}
+
+func (m *Session) GetHeroName() string {
+	if m != nil {
+		return m.HeroName
+	}
+	return ""
+}
+
+func (m *Session) GetLiveHeroBlood() int32 {
+	if m != nil {
+		return m.LiveHeroBlood
+	}
+	return 0
+}
+
+func (m *Session) GetLiveBossBlood() int32 {
+	if m != nil {
+		return m.LiveBossBlood
+	}
+	return 0
+}
+
+func (m *Session) GetCurrentLevel() int32 {
+	if m != nil {
+		return m.CurrentLevel
+	}
+	return 0
+}
+
+func (m *Session) GetScore() int32 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+func (m *Session) GetArchiveDate() *timestamp.Timestamp {
+	if m != nil {
+		return m.ArchiveDate
+	}
+	return nil
+}
+
+func (m *Session) GetLevelStartedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.LevelStartedAt
+	}
+	return nil
+}
+
+func (m *Session) GetScoreUpdatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.ScoreUpdatedAt
+	}
+	return nil
+}
+
+type ListAuditLogRequest struct {
+	// the filters, an empty one matches all the entries
+	Actor     string               `protobuf:"bytes,1,opt,name=actor,proto3" json:"actor,omitempty"`
+	Operation string               `protobuf:"bytes,2,opt,name=operation,proto3" json:"operation,omitempty"`
+	TraceId   string               `protobuf:"bytes,3,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	Since     *timestamp.Timestamp `protobuf:"bytes,4,opt,name=since,proto3" json:"since,omitempty"`
+	Until     *timestamp.Timestamp `protobuf:"bytes,5,opt,name=until,proto3" json:"until,omitempty"`
+	// 50 by default, 500 at most
+	Limit                int32    `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset               int32    `protobuf:"varint,7,opt,name=offset,proto3" json:"offset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListAuditLogRequest) Reset()         { *m = ListAuditLogRequest{} }
+func (m *ListAuditLogRequest) String() string { return proto.CompactTextString(m) }
+func (*ListAuditLogRequest) ProtoMessage()    {}
+func (*ListAuditLogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{52}
+}
+
+func (m *ListAuditLogRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAuditLogRequest.Unmarshal(m, b)
+}
+func (m *ListAuditLogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAuditLogRequest.Marshal(b, m, deterministic)
+}
+func (m *ListAuditLogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAuditLogRequest.Merge(m, src)
+}
+func (m *ListAuditLogRequest) XXX_Size() int {
+	return xxx_messageInfo_ListAuditLogRequest.Size(m)
+}
+func (m *ListAuditLogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAuditLogRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAuditLogRequest proto.InternalMessageInfo
+
+func (m *ListAuditLogRequest) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *ListAuditLogRequest) GetOperation() string {
+	if m != nil {
+		return m.Operation
+	}
+	return ""
+}
+
+func (m *ListAuditLogRequest) GetTraceId() string {
+	if m != nil {
+		return m.TraceId
+	}
+	return ""
+}
+
+func (m *ListAuditLogRequest) GetSince() *timestamp.Timestamp {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+func (m *ListAuditLogRequest) GetUntil() *timestamp.Timestamp {
+	if m != nil {
+		return m.Until
+	}
+	return nil
+}
+
+func (m *ListAuditLogRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *ListAuditLogRequest) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type AuditEntry struct {
+	Id        int64                `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt *timestamp.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// the name of the api key, or the peer address of a request without one
+	Actor string `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	Role  string `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`
+	// the admin request type, e.g. BALANCE_PATCH, or ClearSession
+	Operation string `protobuf:"bytes,5,opt,name=operation,proto3" json:"operation,omitempty"`
+	// json of the request, and of the rows before and after it
+	Arguments string `protobuf:"bytes,6,opt,name=arguments,proto3" json:"arguments,omitempty"`
+	Before    string `protobuf:"bytes,7,opt,name=before,proto3" json:"before,omitempty"`
+	After     string `protobuf:"bytes,8,opt,name=after,proto3" json:"after,omitempty"`
+	// the grpc status code and the error of the request
+	Code                 int32    `protobuf:"varint,9,opt,name=code,proto3" json:"code,omitempty"`
+	Error                string   `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`
+	TraceId              string   `protobuf:"bytes,11,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditEntry) Reset()         { *m = AuditEntry{} }
+func (m *AuditEntry) String() string { return proto.CompactTextString(m) }
+func (*AuditEntry) ProtoMessage()    {}
+func (*AuditEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{53}
+}
+
+func (m *AuditEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditEntry.Unmarshal(m, b)
+}
+func (m *AuditEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditEntry.Marshal(b, m, deterministic)
+}
+func (m *AuditEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditEntry.Merge(m, src)
+}
+func (m *AuditEntry) XXX_Size() int {
+	return xxx_messageInfo_AuditEntry.Size(m)
+}
+func (m *AuditEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditEntry proto.InternalMessageInfo
+
+func (m *AuditEntry) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *AuditEntry) GetCreatedAt() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
+func (m *AuditEntry) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetOperation() string {
+	if m != nil {
+		return m.Operation
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetArguments() string {
+	if m != nil {
+		return m.Arguments
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetBefore() string {
+	if m != nil {
+		return m.Before
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetAfter() string {
+	if m != nil {
+		return m.After
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetCode() int32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *AuditEntry) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetTraceId() string {
+	if m != nil {
+		return m.TraceId
+	}
+	return ""
+}
+
+type ListAuditLogResponse struct {
+	Entries              []*AuditEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	Total                int32         `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ListAuditLogResponse) Reset()         { *m = ListAuditLogResponse{} }
+func (m *ListAuditLogResponse) String() string { return proto.CompactTextString(m) }
+func (*ListAuditLogResponse) ProtoMessage()    {}
+func (*ListAuditLogResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{54}
+}
+
+func (m *ListAuditLogResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAuditLogResponse.Unmarshal(m, b)
+}
+func (m *ListAuditLogResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAuditLogResponse.Marshal(b, m, deterministic)
+}
+func (m *ListAuditLogResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAuditLogResponse.Merge(m, src)
+}
+func (m *ListAuditLogResponse) XXX_Size() int {
+	return xxx_messageInfo_ListAuditLogResponse.Size(m)
+}
+func (m *ListAuditLogResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAuditLogResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAuditLogResponse proto.InternalMessageInfo
+
+func (m *ListAuditLogResponse) GetEntries() []*AuditEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+func (m *ListAuditLogResponse) GetTotal() int32 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+// SessionOwner is attached to the status of the requests refused because
+// the session is owned by another replica.
+type SessionOwner struct {
+	Instance             string   `protobuf:"bytes,1,opt,name=instance,proto3" json:"instance,omitempty"`
+	Address              string   `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SessionOwner) Reset()         { *m = SessionOwner{} }
+func (m *SessionOwner) String() string { return proto.CompactTextString(m) }
+func (*SessionOwner) ProtoMessage()    {}
+func (*SessionOwner) Descriptor() ([]byte, []int) {
+	return fileDescriptor_475ae6b24dd70e2f, []int{55}
+}
+
+func (m *SessionOwner) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionOwner.Unmarshal(m, b)
+}
+func (m *SessionOwner) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionOwner.Marshal(b, m, deterministic)
+}
+func (m *SessionOwner) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionOwner.Merge(m, src)
+}
+func (m *SessionOwner) XXX_Size() int {
+	return xxx_messageInfo_SessionOwner.Size(m)
+}
+func (m *SessionOwner) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionOwner.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionOwner proto.InternalMessageInfo
+
+func (m *SessionOwner) GetInstance() string {
+	if m != nil {
+		return m.Instance
+	}
+	return ""
+}
+
+func (m *SessionOwner) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterEnum("fight.GuildRole", GuildRole_name, GuildRole_value)
+	proto.RegisterEnum("fight.Type", Type_name, Type_value)
+	proto.RegisterEnum("fight.Friend_Status", Friend_Status_name, Friend_Status_value)
+	proto.RegisterEnum("fight.AdminRequest_Type", AdminRequest_Type_name, AdminRequest_Type_value)
+	proto.RegisterEnum("fight.BalancePatch_Stat", BalancePatch_Stat_name, BalancePatch_Stat_value)
+	proto.RegisterEnum("fight.BalancePatch_Mode", BalancePatch_Mode_name, BalancePatch_Mode_value)
+	proto.RegisterEnum("fight.LeaderboardRequest_Window", LeaderboardRequest_Window_name, LeaderboardRequest_Window_value)
+	proto.RegisterEnum("fight.LeaderboardDelta_Type", LeaderboardDelta_Type_name, LeaderboardDelta_Type_value)
+	proto.RegisterType((*Guild)(nil), "fight.Guild")
+	proto.RegisterType((*GuildMember)(nil), "fight.GuildMember")
+	proto.RegisterType((*CreateGuildRequest)(nil), "fight.CreateGuildRequest")
+	proto.RegisterType((*GetGuildRequest)(nil), "fight.GetGuildRequest")
+	proto.RegisterType((*JoinGuildRequest)(nil), "fight.JoinGuildRequest")
+	proto.RegisterType((*LeaveGuildRequest)(nil), "fight.LeaveGuildRequest")
+	proto.RegisterType((*LeaveGuildResponse)(nil), "fight.LeaveGuildResponse")
+	proto.RegisterType((*GuildMemberRequest)(nil), "fight.GuildMemberRequest")
+	proto.RegisterType((*GuildLeaderboardRequest)(nil), "fight.GuildLeaderboardRequest")
+	proto.RegisterType((*GuildLeaderboardResponse)(nil), "fight.GuildLeaderboardResponse")
+	proto.RegisterType((*GuildLeaderboardResponse_Entry)(nil), "fight.GuildLeaderboardResponse.Entry")
+	proto.RegisterType((*Profile)(nil), "fight.Profile")
+	proto.RegisterType((*FriendRequest)(nil), "fight.FriendRequest")
+	proto.RegisterType((*Friend)(nil), "fight.Friend")
+	proto.RegisterType((*ListFriendsRequest)(nil), "fight.ListFriendsRequest")
+	proto.RegisterType((*ListFriendsResponse)(nil), "fight.ListFriendsResponse")
+	proto.RegisterType((*FriendsLeaderboardRequest)(nil), "fight.FriendsLeaderboardRequest")
+	proto.RegisterType((*GetProfileRequest)(nil), "fight.GetProfileRequest")
+	proto.RegisterType((*DeleteProfileRequest)(nil), "fight.DeleteProfileRequest")
+	proto.RegisterType((*DeleteProfileResponse)(nil), "fight.DeleteProfileResponse")
+	proto.RegisterType((*ClearSessionRequest)(nil), "fight.ClearSessionRequest")
+	proto.RegisterType((*ClearSessionResponse)(nil), "fight.ClearSessionResponse")
+	proto.RegisterType((*AdminRequest)(nil), "fight.AdminRequest")
+	proto.RegisterType((*BalancePatch)(nil), "fight.BalancePatch")
+	proto.RegisterType((*BalancePatch_Change)(nil), "fight.BalancePatch.Change")
+	proto.RegisterType((*HeroDiff)(nil), "fight.HeroDiff")
+	proto.RegisterType((*AdminResult)(nil), "fight.AdminResult")
+	proto.RegisterType((*HeroChange)(nil), "fight.HeroChange")
+	proto.RegisterType((*BossChange)(nil), "fight.BossChange")
+	proto.RegisterType((*SessionChange)(nil), "fight.SessionChange")
+	proto.RegisterType((*AdminResponse)(nil), "fight.AdminResponse")
+	proto.RegisterType((*Top10Request)(nil), "fight.Top10Request")
+	proto.RegisterType((*Top10Response)(nil), "fight.Top10Response")
+	proto.RegisterType((*Top10Response_Player)(nil), "fight.Top10Response.Player")
+	proto.RegisterType((*LeaderboardRequest)(nil), "fight.LeaderboardRequest")
+	proto.RegisterType((*WatchLeaderboardRequest)(nil), "fight.WatchLeaderboardRequest")
+	proto.RegisterType((*LeaderboardDelta)(nil), "fight.LeaderboardDelta")
+	proto.RegisterType((*LeaderboardUpdate)(nil), "fight.LeaderboardUpdate")
+	proto.RegisterType((*HeroLeaderboardRequest)(nil), "fight.HeroLeaderboardRequest")
+	proto.RegisterType((*LevelLeaderboardRequest)(nil), "fight.LevelLeaderboardRequest")
+	proto.RegisterType((*LeaderboardEntry)(nil), "fight.LeaderboardEntry")
+	proto.RegisterType((*LeaderboardResponse)(nil), "fight.LeaderboardResponse")
+	proto.RegisterType((*GameRequest)(nil), "fight.GameRequest")
+	proto.RegisterType((*GameResponse)(nil), "fight.GameResponse")
+	proto.RegisterType((*Fight)(nil), "fight.Fight")
+	proto.RegisterType((*Archive)(nil), "fight.Archive")
+	proto.RegisterType((*Level)(nil), "fight.Level")
+	proto.RegisterType((*Quit)(nil), "fight.Quit")
+	proto.RegisterType((*SelectHeroRequest)(nil), "fight.SelectHeroRequest")
+	proto.RegisterType((*LoadSessionRequest)(nil), "fight.LoadSessionRequest")
+	proto.RegisterType((*SessionView)(nil), "fight.SessionView")
+	proto.RegisterType((*ListHerosRequest)(nil), "fight.ListHerosRequest")
+	proto.RegisterType((*Hero)(nil), "fight.Hero")
+	proto.RegisterType((*Boss)(nil), "fight.Boss")
+	proto.RegisterType((*Session)(nil), "fight.Session")
+	proto.RegisterType((*ListAuditLogRequest)(nil), "fight.ListAuditLogRequest")
+	proto.RegisterType((*AuditEntry)(nil), "fight.AuditEntry")
+	proto.RegisterType((*ListAuditLogResponse)(nil), "fight.ListAuditLogResponse")
+	proto.RegisterType((*SessionOwner)(nil), "fight.SessionOwner")
+}
+
+func init() { proto.RegisterFile("pd/fight/fight.proto", fileDescriptor_475ae6b24dd70e2f) }
+
+var fileDescriptor_475ae6b24dd70e2f = []byte{
+	// 3242 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x3a, 0x4d, 0x6f, 0x1b, 0xc9,
+	0xb1, 0x1e, 0x92, 0xc3, 0x8f, 0x22, 0x69, 0x8f, 0xdb, 0xda, 0x35, 0x4d, 0xdb, 0xbb, 0xda, 0xf1,
+	0x7e, 0x68, 0xfd, 0xfc, 0x24, 0xd9, 0xde, 0xe7, 0xdd, 0xb7, 0xef, 0x65, 0x13, 0x8a, 0x1c, 0x4b,
+	0xb4, 0x29, 0x52, 0x3b, 0xa4, 0x6c, 0x78, 0x2f, 0xc4, 0x88, 0xd3, 0x94, 0x06, 0x22, 0x67, 0xe8,
+	0x99, 0xa1, 0x14, 0xe5, 0x1f, 0xe4, 0x92, 0x00, 0x39, 0x24, 0xc0, 0x02, 0x01, 0x72, 0x09, 0x90,
+	0x9c, 0x92, 0x73, 0xfe, 0x44, 0x80, 0xe4, 0x96, 0x43, 0x90, 0x1f, 0x11, 0xe4, 0x92, 0x4b, 0x50,
+	0xdd, 0x3d, 0x9c, 0x19, 0x72, 0x28, 0x59, 0xbb, 0x8b, 0x20, 0x17, 0x82, 0xf5, 0xd1, 0xd5, 0xd5,
+	0xd5, 0x55, 0xd5, 0xd5, 0xd5, 0x03, 0x2b, 0x13, 0x73, 0x63, 0x68, 0x1d, 0x1e, 0xf9, 0xfc, 0x77,
+	0x7d, 0xe2, 0x3a, 0xbe, 0x43, 0x64, 0x06, 0x54, 0xdf, 0x39, 0x74, 0x9c, 0xc3, 0x11, 0xdd, 0x60,
+	0xc8, 0x83, 0xe9, 0x70, 0xc3, 0x9c, 0xba, 0x86, 0x6f, 0x39, 0x36, 0x67, 0xab, 0xbe, 0x3b, 0x4f,
+	0xf7, 0xad, 0x31, 0xf5, 0x7c, 0x63, 0x3c, 0xe1, 0x0c, 0xea, 0xd7, 0x12, 0xc8, 0xdb, 0x53, 0x6b,
+	0x64, 0x12, 0x02, 0x19, 0xdb, 0x18, 0xd3, 0x8a, 0xb4, 0x2a, 0xad, 0x15, 0x74, 0xf6, 0x9f, 0xac,
+	0x80, 0xec, 0x0d, 0x1c, 0x97, 0x56, 0x52, 0xab, 0xd2, 0x9a, 0xac, 0x73, 0x80, 0x3c, 0x80, 0xdc,
+	0x98, 0x8e, 0x0f, 0xa8, 0xeb, 0x55, 0xd2, 0xab, 0xe9, 0xb5, 0xe2, 0x23, 0xb2, 0xce, 0x55, 0x63,
+	0x82, 0x76, 0x19, 0x49, 0x0f, 0x58, 0xc8, 0xff, 0x02, 0x0c, 0x5c, 0x6a, 0xf8, 0xd4, 0xec, 0x1b,
+	0x7e, 0x25, 0xb3, 0x2a, 0xad, 0x15, 0x1f, 0x55, 0xd7, 0xb9, 0x5e, 0xeb, 0x81, 0x5e, 0xeb, 0xbd,
+	0x40, 0x2f, 0xbd, 0x20, 0xb8, 0x6b, 0xbe, 0x3a, 0x85, 0x62, 0x44, 0x24, 0x79, 0x0f, 0x4a, 0xa6,
+	0xe5, 0x4d, 0x46, 0xc6, 0x59, 0x3f, 0xa2, 0x69, 0x51, 0xe0, 0xda, 0xa8, 0xf0, 0xfb, 0x90, 0x71,
+	0x9d, 0x11, 0xd7, 0xf7, 0xea, 0x23, 0x25, 0xaa, 0x97, 0xee, 0x8c, 0xa8, 0xce, 0xa8, 0xe4, 0x2e,
+	0xc0, 0x01, 0xf5, 0xfc, 0x3e, 0x5f, 0x5b, 0x9a, 0xad, 0xad, 0x80, 0x98, 0x2e, 0x22, 0xd4, 0xcf,
+	0x80, 0xd4, 0x99, 0x0e, 0x7c, 0x1c, 0x7d, 0x3d, 0xa5, 0x9e, 0x4f, 0xae, 0x42, 0xca, 0x32, 0xc5,
+	0x9c, 0x29, 0x2b, 0xb4, 0x57, 0x2a, 0xb4, 0x97, 0xfa, 0x01, 0x5c, 0xdb, 0xa6, 0x7e, 0x6c, 0x58,
+	0x82, 0x59, 0xd5, 0x27, 0xa0, 0x3c, 0x73, 0x2c, 0xfb, 0xd2, 0xe2, 0xef, 0xc1, 0xf5, 0x16, 0x35,
+	0x4e, 0xce, 0xd5, 0x4b, 0xfd, 0x10, 0x48, 0x94, 0xc9, 0x9b, 0x38, 0xb6, 0x47, 0x89, 0x02, 0xe9,
+	0xb1, 0x77, 0x28, 0xd8, 0xf0, 0xaf, 0x3a, 0x06, 0x12, 0xdd, 0xaf, 0x25, 0x6a, 0xcc, 0xdb, 0x3c,
+	0xb5, 0xdc, 0xe6, 0xe9, 0xf3, 0x6c, 0xae, 0x6e, 0xc3, 0x4d, 0x86, 0x6a, 0x51, 0xc3, 0xa4, 0xee,
+	0x81, 0x63, 0xb8, 0xb3, 0x15, 0xac, 0x80, 0x3c, 0xb2, 0xc6, 0x96, 0xcf, 0xa6, 0x95, 0x75, 0x0e,
+	0x90, 0xb7, 0x21, 0xeb, 0x0c, 0x87, 0x1e, 0xf5, 0x85, 0xf3, 0x09, 0x48, 0xfd, 0x93, 0x04, 0x95,
+	0x45, 0x49, 0x62, 0x99, 0xdf, 0x87, 0x1c, 0xb5, 0x7d, 0xd7, 0xa2, 0x5e, 0x45, 0x62, 0xae, 0xf9,
+	0x41, 0x54, 0x9d, 0x84, 0x11, 0xeb, 0x9a, 0xed, 0xbb, 0x67, 0x7a, 0x30, 0x0a, 0x75, 0xf1, 0x1d,
+	0xdf, 0x18, 0x05, 0x1e, 0xcf, 0x80, 0x6a, 0x1f, 0x64, 0xc6, 0x87, 0xbb, 0xe2, 0x1a, 0xf6, 0xb1,
+	0xd0, 0x94, 0xfd, 0x4f, 0xda, 0xa9, 0x30, 0x70, 0xd2, 0xd1, 0xc0, 0xa9, 0x84, 0x81, 0x93, 0x61,
+	0xf8, 0x00, 0x54, 0x7f, 0x2e, 0x41, 0x6e, 0xcf, 0x75, 0x86, 0xd6, 0x88, 0x7e, 0x93, 0x2d, 0xb8,
+	0x0b, 0x60, 0x9c, 0x18, 0xbe, 0xe1, 0xf6, 0x8f, 0xe9, 0x19, 0x9b, 0xb3, 0xa0, 0x17, 0x38, 0xe6,
+	0x39, 0x3d, 0xfb, 0x36, 0x21, 0xb8, 0x05, 0xe5, 0xa7, 0xae, 0x45, 0x6d, 0xf3, 0x9b, 0x3b, 0x88,
+	0xfa, 0x17, 0x09, 0xb2, 0x5c, 0xc8, 0x9b, 0x84, 0xf0, 0x03, 0xc8, 0x7a, 0xbe, 0xe1, 0x4f, 0x3d,
+	0x11, 0xc4, 0x2b, 0x62, 0x07, 0xb9, 0x84, 0xf5, 0x2e, 0xa3, 0xe9, 0x82, 0x87, 0x54, 0x21, 0x6f,
+	0xd9, 0x03, 0x67, 0x6c, 0xd9, 0x87, 0x6c, 0xdd, 0x79, 0x7d, 0x06, 0x93, 0x4d, 0x90, 0x3d, 0xcb,
+	0x1e, 0xd0, 0x37, 0x58, 0x31, 0x67, 0x54, 0x1f, 0x42, 0x96, 0xcb, 0x27, 0x45, 0xc8, 0xed, 0x69,
+	0xed, 0x46, 0xb3, 0xbd, 0xad, 0x5c, 0x21, 0x25, 0xc8, 0xd7, 0xea, 0x75, 0x6d, 0xaf, 0xa7, 0x35,
+	0x14, 0x09, 0xa1, 0x86, 0x56, 0x6f, 0x35, 0xdb, 0x5a, 0x43, 0x49, 0xa9, 0xef, 0x03, 0x69, 0x59,
+	0x9e, 0xcf, 0xb5, 0xf3, 0x96, 0x05, 0xe5, 0x17, 0x70, 0x23, 0xc6, 0x25, 0xdc, 0xf5, 0x23, 0xc8,
+	0x0d, 0x39, 0x4a, 0xb8, 0x6b, 0x39, 0xb6, 0x58, 0x3d, 0xa0, 0xaa, 0x67, 0x70, 0x4b, 0x8c, 0x4d,
+	0x88, 0x9f, 0xf9, 0x2d, 0x99, 0xc5, 0x53, 0x2a, 0x39, 0x9e, 0xd2, 0xd1, 0x78, 0x22, 0xef, 0x00,
+	0xd8, 0xd4, 0x3a, 0x3c, 0x3a, 0x70, 0xa6, 0x33, 0xbf, 0x8c, 0x60, 0x30, 0xe9, 0x6c, 0x53, 0x5f,
+	0x38, 0xe7, 0xf2, 0xa4, 0xb3, 0xd2, 0xa0, 0x23, 0xea, 0xd3, 0x0b, 0xf8, 0x3e, 0x86, 0xb7, 0xe6,
+	0xf8, 0x96, 0xe6, 0xa7, 0x0f, 0xe0, 0x46, 0x7d, 0x44, 0x0d, 0xb7, 0x4b, 0x3d, 0xcf, 0x72, 0xec,
+	0x65, 0x12, 0xd7, 0x60, 0x25, 0xce, 0xb6, 0x54, 0xe0, 0xd7, 0x32, 0x94, 0x6a, 0xe6, 0xd8, 0x9a,
+	0x89, 0x7a, 0x0f, 0xe4, 0x23, 0xea, 0x3a, 0x81, 0xed, 0x8b, 0xc2, 0xf6, 0x3b, 0xd4, 0x75, 0x74,
+	0x4e, 0x21, 0x0f, 0x20, 0xe3, 0x9f, 0x4d, 0x82, 0xf3, 0xa4, 0x22, 0x38, 0xa2, 0x52, 0xd6, 0x7b,
+	0x67, 0x13, 0xaa, 0x33, 0x2e, 0x0c, 0xc3, 0xb1, 0x75, 0xe8, 0x1a, 0x3e, 0xed, 0xfb, 0x4e, 0x10,
+	0x86, 0x02, 0xd3, 0x73, 0xc8, 0x3d, 0xc8, 0x1e, 0x38, 0x9e, 0x47, 0xd1, 0xca, 0xd1, 0x09, 0xb7,
+	0x1c, 0xcf, 0xd3, 0x05, 0x09, 0x65, 0xb8, 0x5c, 0x72, 0xdf, 0x32, 0x2b, 0x32, 0x97, 0x21, 0x30,
+	0x4d, 0x13, 0x77, 0xd1, 0xf0, 0x9d, 0xb1, 0x35, 0xa8, 0x64, 0x99, 0xb7, 0x0b, 0x88, 0x7c, 0x0c,
+	0xf2, 0xc4, 0xf0, 0x07, 0x47, 0x95, 0x1c, 0xf3, 0xf5, 0x1b, 0x81, 0x68, 0x63, 0x64, 0xd8, 0x03,
+	0xba, 0x87, 0x24, 0x9d, 0x73, 0x90, 0x5b, 0x90, 0x67, 0x7f, 0x50, 0x7e, 0x7e, 0x55, 0x5a, 0x4b,
+	0xeb, 0x39, 0x06, 0x73, 0xe9, 0xbe, 0x71, 0x30, 0xa2, 0x5e, 0xa5, 0xb0, 0x9a, 0x5e, 0x2b, 0xe8,
+	0x02, 0xc2, 0xc4, 0x65, 0x0c, 0xb0, 0xac, 0xf0, 0x2a, 0xc0, 0x08, 0x01, 0x88, 0xea, 0x7a, 0xdc,
+	0xf2, 0x28, 0xae, 0xc8, 0xd5, 0x15, 0x98, 0xa6, 0x49, 0x6e, 0x43, 0x61, 0x6a, 0xfb, 0xd6, 0xa8,
+	0xef, 0xd1, 0xd7, 0x95, 0x12, 0x9b, 0x2c, 0xcf, 0x10, 0x5d, 0xfa, 0x5a, 0xfd, 0x71, 0x0a, 0x32,
+	0x68, 0x3d, 0x72, 0x0d, 0x8a, 0x75, 0x5d, 0xab, 0xf5, 0xb4, 0xfe, 0x8e, 0xa6, 0x77, 0x94, 0x2b,
+	0x88, 0xa8, 0x35, 0x9e, 0xed, 0x77, 0x7b, 0x1c, 0x21, 0x91, 0xb7, 0xe0, 0x7a, 0xb7, 0x5d, 0xdb,
+	0xeb, 0xee, 0x74, 0x7a, 0xfd, 0xae, 0xd6, 0xed, 0x36, 0x3b, 0xed, 0xae, 0x92, 0x42, 0xbe, 0xfd,
+	0xbd, 0xc6, 0x6c, 0x60, 0x1a, 0x11, 0x0d, 0xad, 0xa5, 0x05, 0x88, 0x0c, 0xe7, 0xe8, 0x6a, 0xba,
+	0x90, 0x24, 0x47, 0xe6, 0xda, 0xea, 0x74, 0xbb, 0x4a, 0x36, 0x22, 0x83, 0x21, 0x72, 0x11, 0x19,
+	0x0c, 0x91, 0x27, 0x04, 0xae, 0xea, 0x5a, 0x47, 0x6f, 0x68, 0x3a, 0xc3, 0x68, 0x5d, 0xa5, 0x40,
+	0xae, 0x43, 0x79, 0xab, 0xd6, 0xaa, 0xb5, 0xeb, 0x5a, 0x7f, 0xaf, 0xd6, 0xab, 0xef, 0x28, 0x40,
+	0x2a, 0xb0, 0xa2, 0x6b, 0x2f, 0x70, 0xaa, 0x38, 0xa5, 0x48, 0xca, 0x50, 0xe8, 0xee, 0x6f, 0x75,
+	0xeb, 0x7a, 0x73, 0x4b, 0x53, 0x4a, 0x5c, 0xde, 0x5e, 0xab, 0xf6, 0x2a, 0x58, 0x8a, 0x52, 0x56,
+	0xff, 0x98, 0x82, 0x52, 0x74, 0xb3, 0x30, 0x88, 0x43, 0xe7, 0x2c, 0x04, 0xfe, 0xf8, 0x09, 0xe4,
+	0x06, 0x47, 0x86, 0x7d, 0x48, 0x31, 0x3b, 0xa6, 0x59, 0x52, 0x5b, 0xdc, 0xe8, 0xf5, 0x3a, 0x63,
+	0xd1, 0x03, 0x56, 0x72, 0x13, 0x72, 0xa6, 0x7b, 0xd6, 0x77, 0xa7, 0xb6, 0xc8, 0x91, 0x59, 0xd3,
+	0x3d, 0xd3, 0xa7, 0x76, 0xf5, 0x47, 0x90, 0xe5, 0xbc, 0xe8, 0xe8, 0x98, 0x51, 0x2b, 0x52, 0xcc,
+	0xd1, 0x63, 0x52, 0x31, 0x33, 0xea, 0x8c, 0x0b, 0xb9, 0xc7, 0x8e, 0x39, 0x1f, 0x16, 0x31, 0xee,
+	0x5d, 0xc7, 0xa4, 0x3a, 0xe3, 0xc2, 0xa5, 0x9c, 0x18, 0xa3, 0x29, 0x3f, 0x0c, 0x25, 0x9d, 0x03,
+	0xea, 0x13, 0xc8, 0xa0, 0x44, 0xa2, 0x40, 0xa9, 0xd6, 0xeb, 0xd5, 0xea, 0xcf, 0xfb, 0x7b, 0x9d,
+	0x97, 0x9a, 0xae, 0x5c, 0x41, 0xdb, 0x36, 0xb4, 0xa7, 0x5a, 0xbb, 0xab, 0x09, 0x94, 0x44, 0x0a,
+	0x20, 0x6f, 0xb5, 0x3a, 0x1d, 0x4c, 0xb8, 0x1f, 0x42, 0x06, 0x65, 0x93, 0x1c, 0xa4, 0xbb, 0x5a,
+	0x4f, 0xb9, 0x82, 0x7f, 0x6a, 0x0d, 0x91, 0x98, 0x77, 0xf7, 0x5b, 0xbd, 0xe6, 0x5e, 0xeb, 0x95,
+	0x92, 0x52, 0x75, 0xc8, 0x63, 0x24, 0x37, 0xac, 0xe1, 0x90, 0x45, 0x1e, 0x1d, 0xe2, 0x79, 0x2c,
+	0xad, 0x4a, 0x91, 0xc8, 0x63, 0xa1, 0x2e, 0x48, 0x98, 0x0e, 0x8c, 0xa1, 0x4f, 0xdd, 0x4a, 0x6a,
+	0x91, 0x87, 0x53, 0xd4, 0xbf, 0x4a, 0x50, 0x14, 0xc1, 0xef, 0x4d, 0x47, 0xfe, 0x5c, 0xb0, 0x4a,
+	0xf3, 0xc1, 0x4a, 0x20, 0x33, 0x08, 0xcc, 0x24, 0xeb, 0x99, 0x81, 0x30, 0x06, 0x75, 0x5d, 0xc7,
+	0x15, 0xe9, 0x81, 0x03, 0x2c, 0xc0, 0x26, 0x93, 0x91, 0x45, 0xcd, 0xa0, 0x32, 0x10, 0x20, 0xb9,
+	0x07, 0x19, 0xd3, 0x1a, 0x0e, 0x2b, 0x32, 0xdb, 0xee, 0x6b, 0x11, 0xa5, 0x70, 0x65, 0x3a, 0x23,
+	0xc6, 0x42, 0x3a, 0x1b, 0x0f, 0xe9, 0x07, 0x90, 0x13, 0xe1, 0x28, 0x52, 0x43, 0x50, 0xac, 0x8b,
+	0x84, 0xf9, 0xc2, 0xa2, 0xa7, 0x7a, 0xc0, 0xa2, 0x1e, 0x00, 0xa0, 0x68, 0xe1, 0x14, 0x98, 0x6c,
+	0x58, 0x9c, 0x8b, 0xa5, 0x09, 0x88, 0xdc, 0x85, 0xb4, 0x33, 0x32, 0x93, 0xec, 0x84, 0x78, 0x24,
+	0xdb, 0xf4, 0xb4, 0x92, 0x4e, 0x20, 0xdb, 0xf4, 0x14, 0xe7, 0xc0, 0x8c, 0xf7, 0x4d, 0xe6, 0xc0,
+	0x71, 0xe7, 0xcc, 0xc1, 0xc9, 0x38, 0xc7, 0x31, 0x94, 0xc5, 0xfa, 0x2e, 0x98, 0x66, 0x35, 0x3a,
+	0xcd, 0xd5, 0xb8, 0x69, 0xf8, 0x4c, 0xab, 0xd1, 0x99, 0x16, 0x38, 0x70, 0xb2, 0xdf, 0xa6, 0xa0,
+	0x1c, 0x78, 0x05, 0x3f, 0x7c, 0xde, 0xe0, 0x64, 0x09, 0x0f, 0x83, 0xd4, 0xf2, 0xc3, 0xe0, 0x3e,
+	0x64, 0x5d, 0xe6, 0x69, 0x95, 0x74, 0x6c, 0xef, 0x22, 0x3e, 0xa8, 0x0b, 0x0e, 0xf2, 0x11, 0x64,
+	0x50, 0xb2, 0x28, 0x76, 0xae, 0x47, 0xa6, 0xe4, 0x26, 0xd8, 0xb9, 0xa2, 0x33, 0x06, 0x64, 0x44,
+	0xf1, 0x15, 0x39, 0xc6, 0x18, 0x6e, 0x09, 0x32, 0x22, 0x03, 0xd9, 0x0c, 0x5d, 0x27, 0xcb, 0x78,
+	0x57, 0xe2, 0xab, 0x9f, 0xb1, 0x07, 0x6c, 0x68, 0x65, 0x97, 0x7a, 0x67, 0xf6, 0x80, 0xf9, 0x5a,
+	0x5e, 0x17, 0xd0, 0x56, 0x1e, 0xb2, 0x3c, 0x17, 0xa9, 0x57, 0xa1, 0xd4, 0x73, 0x26, 0x0f, 0x37,
+	0xc5, 0xe9, 0xa9, 0xfe, 0x4e, 0x82, 0xb2, 0x40, 0x08, 0xdb, 0xfd, 0x0f, 0xe4, 0xb0, 0x16, 0xa4,
+	0x6e, 0x60, 0xbd, 0xdb, 0x62, 0xd6, 0x18, 0xdb, 0xfa, 0x1e, 0xe3, 0xd1, 0x03, 0xde, 0xea, 0x21,
+	0x64, 0x39, 0x2a, 0xa9, 0x1c, 0x4a, 0xb8, 0xc4, 0x62, 0x91, 0x44, 0x4f, 0xe8, 0x28, 0xa8, 0xd0,
+	0x19, 0xb0, 0x50, 0x9f, 0x66, 0x16, 0xab, 0xd9, 0x5f, 0xa6, 0x80, 0x24, 0x14, 0x61, 0x97, 0xba,
+	0xc4, 0xe0, 0xb9, 0xc8, 0x15, 0xc7, 0x88, 0xe5, 0x99, 0x20, 0xcf, 0x11, 0x4d, 0xf3, 0xa2, 0x8a,
+	0x8c, 0x7c, 0x06, 0xd9, 0x53, 0xcb, 0x36, 0x9d, 0x53, 0xb6, 0x85, 0x57, 0x1f, 0xad, 0x0a, 0x03,
+	0x2d, 0x6a, 0xb5, 0xfe, 0x92, 0xf1, 0xe9, 0x82, 0x1f, 0xd5, 0x99, 0x50, 0xd7, 0x72, 0x78, 0x96,
+	0x28, 0xe8, 0x02, 0x52, 0x9f, 0x41, 0x96, 0x73, 0xe2, 0xe1, 0x87, 0x27, 0x53, 0xb3, 0xbd, 0xdd,
+	0x6f, 0x77, 0x5e, 0x8a, 0xda, 0xb7, 0xd5, 0xea, 0xf7, 0x9a, 0xbb, 0x1a, 0xcf, 0xc3, 0x8d, 0x5a,
+	0x13, 0xf3, 0x2b, 0x01, 0xc8, 0xbe, 0xd4, 0xb4, 0xe7, 0xad, 0x57, 0x4a, 0x1a, 0xff, 0x77, 0xb5,
+	0x5a, 0xb7, 0xd3, 0x56, 0x32, 0x6a, 0x1f, 0x6e, 0xbe, 0xc4, 0xdc, 0xf3, 0xad, 0x6d, 0x14, 0x3a,
+	0x53, 0x3a, 0xea, 0x4c, 0xea, 0x1f, 0x24, 0x50, 0x22, 0xc2, 0x1b, 0x74, 0xe4, 0x1b, 0x64, 0x53,
+	0x14, 0x6a, 0xfc, 0xfc, 0xba, 0xb3, 0x68, 0x11, 0xc6, 0x16, 0x2d, 0xd6, 0xfe, 0x1b, 0x64, 0xbc,
+	0xf4, 0x9d, 0x89, 0xd8, 0xbf, 0xb9, 0x38, 0x84, 0x5f, 0x0d, 0x39, 0x17, 0xee, 0xd8, 0xd0, 0x75,
+	0xc6, 0x7d, 0x76, 0xfd, 0xe3, 0x3e, 0x93, 0x47, 0x84, 0x6e, 0xd8, 0xc7, 0xea, 0x9a, 0x28, 0x64,
+	0x00, 0xb2, 0xcd, 0x36, 0x56, 0x1b, 0xca, 0x15, 0xfc, 0xaf, 0x6b, 0xbb, 0x9d, 0x17, 0x68, 0xb6,
+	0x3c, 0x64, 0xd8, 0xbf, 0x94, 0xfa, 0x6b, 0x09, 0xae, 0x47, 0xa6, 0xd8, 0x9f, 0x98, 0x86, 0xcf,
+	0x8a, 0x55, 0x2c, 0x90, 0x50, 0xf9, 0x8c, 0x8e, 0x7f, 0xf1, 0x5e, 0xe3, 0xd9, 0xc6, 0xc4, 0x3b,
+	0x72, 0xb8, 0x59, 0xf2, 0xfa, 0x0c, 0x26, 0x0f, 0xc3, 0x4b, 0x2e, 0xef, 0xbf, 0x2c, 0xd5, 0x3d,
+	0xe0, 0x23, 0x1b, 0x90, 0x35, 0xd1, 0x00, 0x41, 0xe9, 0x79, 0x73, 0x89, 0x81, 0x74, 0xc1, 0xa6,
+	0xfe, 0x4a, 0x82, 0xb7, 0x31, 0x77, 0x24, 0xec, 0xe2, 0x6d, 0x28, 0x60, 0x1e, 0x89, 0x5e, 0xe0,
+	0xf2, 0x88, 0x68, 0x8b, 0x8b, 0xef, 0x25, 0xee, 0x1e, 0xb1, 0x30, 0xc8, 0x9c, 0x1b, 0x06, 0xf2,
+	0xc2, 0xc5, 0xe4, 0x6b, 0x09, 0x6e, 0xb6, 0x30, 0x6a, 0x97, 0x78, 0x1a, 0x92, 0x66, 0x9e, 0x86,
+	0xc0, 0xbf, 0x53, 0xb9, 0x7f, 0xc6, 0x9d, 0x74, 0x79, 0xf7, 0x80, 0x67, 0xab, 0xd4, 0x62, 0xb6,
+	0x4a, 0x27, 0x66, 0xab, 0x4c, 0x74, 0x3d, 0xb1, 0x9d, 0x90, 0xe7, 0x76, 0xe2, 0x33, 0x80, 0x01,
+	0x5e, 0x8c, 0xfa, 0xbe, 0x35, 0xa6, 0x22, 0x81, 0xdf, 0x5a, 0xb8, 0x02, 0x37, 0x44, 0xbf, 0x50,
+	0x2f, 0x30, 0x66, 0xbc, 0x11, 0x2f, 0x24, 0xc1, 0x5c, 0x62, 0xc3, 0x41, 0xd8, 0x06, 0x1b, 0x0e,
+	0x79, 0xc6, 0x20, 0xac, 0xf5, 0x9c, 0x9e, 0xa9, 0x7f, 0x93, 0xe0, 0x46, 0x52, 0x7b, 0xe6, 0xe1,
+	0x7c, 0x7b, 0xe6, 0x62, 0xcf, 0x4d, 0x6c, 0xc8, 0xa0, 0x3f, 0xf3, 0xd9, 0xc4, 0xc1, 0xb8, 0x54,
+	0x8e, 0x60, 0x23, 0x9f, 0xce, 0xe5, 0xd4, 0x73, 0x27, 0x8f, 0xb0, 0x46, 0x52, 0xa6, 0x1c, 0x4b,
+	0x99, 0x5f, 0x40, 0x71, 0xdb, 0x18, 0xcf, 0x2e, 0xba, 0xef, 0xc6, 0xf2, 0x4f, 0x70, 0x98, 0x47,
+	0xd2, 0xcd, 0xdc, 0x3e, 0xab, 0x7f, 0x96, 0xa0, 0xc4, 0x05, 0x08, 0xdb, 0x5c, 0x28, 0xe1, 0x7d,
+	0xe0, 0x4d, 0x5f, 0x91, 0xb0, 0x4a, 0x41, 0xab, 0x00, 0x7f, 0x77, 0xae, 0xe8, 0x9c, 0x48, 0xee,
+	0x43, 0xce, 0x70, 0x07, 0x47, 0xd6, 0x09, 0x9d, 0x2b, 0x59, 0x6a, 0x1c, 0x8b, 0xc7, 0xb5, 0x60,
+	0x40, 0x89, 0xa1, 0x57, 0x85, 0x12, 0x59, 0x50, 0xa1, 0xc4, 0xe0, 0x4c, 0xcc, 0xbc, 0x9e, 0x5a,
+	0xbe, 0xa8, 0x17, 0x02, 0xc5, 0xbe, 0x9c, 0x5a, 0x38, 0x2b, 0x23, 0x6d, 0xe5, 0x44, 0x85, 0xaf,
+	0xfe, 0x42, 0x02, 0x99, 0x29, 0x84, 0xbe, 0x79, 0x68, 0x8c, 0x69, 0xdf, 0x39, 0xa1, 0x2e, 0x5b,
+	0x53, 0x5e, 0xcf, 0x23, 0xa2, 0x73, 0x42, 0x5d, 0x74, 0x1f, 0x9b, 0xfe, 0xd0, 0xef, 0xf3, 0xd9,
+	0x79, 0x7e, 0x2b, 0x20, 0xa6, 0x15, 0xc4, 0x69, 0x42, 0x0c, 0xdc, 0x05, 0x60, 0xde, 0x7e, 0x30,
+	0x72, 0x9c, 0xa0, 0x4c, 0x66, 0xfe, 0xbf, 0x85, 0x08, 0x24, 0x63, 0xd5, 0x22, 0xc8, 0x3c, 0x22,
+	0x0b, 0x88, 0x61, 0x64, 0xf5, 0x73, 0xc8, 0x09, 0x0b, 0x2c, 0xb6, 0x06, 0xe6, 0x6e, 0xb1, 0xa9,
+	0xb9, 0x5b, 0xac, 0x5a, 0x07, 0x99, 0x2b, 0xb6, 0x38, 0x72, 0x2d, 0xac, 0x91, 0x92, 0x6b, 0xc8,
+	0x80, 0xac, 0x56, 0x20, 0x83, 0x36, 0x5b, 0x94, 0xa1, 0xfe, 0x00, 0xae, 0x77, 0xe9, 0x88, 0x0e,
+	0x7c, 0x56, 0x1f, 0x2e, 0x69, 0xea, 0xc4, 0x62, 0x3d, 0x15, 0x8f, 0x75, 0xd6, 0x84, 0x72, 0x0c,
+	0xf3, 0x82, 0x56, 0xc9, 0x4f, 0x25, 0x28, 0x46, 0xaa, 0x7e, 0xf4, 0x38, 0x94, 0x90, 0x74, 0x27,
+	0x62, 0x04, 0x64, 0x60, 0x95, 0x62, 0x42, 0x11, 0xce, 0x08, 0xd1, 0xd5, 0xa7, 0xcf, 0x5d, 0x3d,
+	0x0f, 0xe3, 0x63, 0x6a, 0x8b, 0x44, 0xca, 0x01, 0x95, 0x80, 0x82, 0x6d, 0x31, 0x9c, 0x32, 0x68,
+	0x9d, 0xa9, 0x3f, 0x93, 0x20, 0x83, 0x88, 0xc4, 0x07, 0x89, 0x0a, 0xe4, 0x4c, 0xea, 0x1b, 0xd6,
+	0xc8, 0x13, 0x36, 0x08, 0x40, 0x4c, 0x5a, 0x86, 0xef, 0x1b, 0x83, 0xe3, 0xfe, 0xc4, 0x39, 0x15,
+	0x79, 0x41, 0xd6, 0x8b, 0x1c, 0xb7, 0x87, 0x28, 0x72, 0x0f, 0xca, 0x26, 0x1d, 0x52, 0xdb, 0xa3,
+	0x82, 0x87, 0xfb, 0x50, 0x49, 0x20, 0x39, 0xd3, 0x0a, 0xc8, 0x51, 0x0f, 0xe2, 0x80, 0xfa, 0x1b,
+	0x09, 0x32, 0xb8, 0xee, 0xff, 0x24, 0xa5, 0xc2, 0x43, 0x21, 0x1b, 0x39, 0x14, 0xd4, 0x9f, 0xa4,
+	0x21, 0x27, 0xcc, 0x8f, 0xbe, 0xb6, 0xdf, 0x6c, 0x04, 0xbe, 0xb6, 0xdf, 0x6c, 0x9c, 0xeb, 0x46,
+	0xe4, 0x43, 0xb8, 0x36, 0xb2, 0x4e, 0x68, 0x3f, 0x12, 0x66, 0x5c, 0xe3, 0x32, 0xa2, 0x77, 0x66,
+	0xa1, 0x16, 0xf0, 0x45, 0xe2, 0x2d, 0x13, 0xf2, 0x6d, 0x05, 0x31, 0x87, 0x6b, 0x1b, 0x4c, 0x5d,
+	0x97, 0xda, 0x41, 0xa4, 0x73, 0xf5, 0x4b, 0x02, 0x39, 0x17, 0xec, 0xd9, 0x68, 0xb0, 0x7f, 0x0f,
+	0x4a, 0x22, 0x4b, 0xf5, 0xb1, 0x42, 0xaa, 0xe4, 0x2e, 0x6c, 0xe1, 0x16, 0x05, 0x7f, 0x03, 0x0b,
+	0xaa, 0x06, 0x28, 0x6c, 0xc6, 0xbe, 0xe7, 0x1b, 0xae, 0xe8, 0x7b, 0xe7, 0x2f, 0x14, 0x71, 0x95,
+	0x8d, 0xe9, 0xf2, 0x21, 0x35, 0x1f, 0xa5, 0x30, 0x6d, 0xfa, 0x53, 0x56, 0xa6, 0x31, 0x29, 0x85,
+	0x8b, 0xa5, 0xb0, 0x31, 0xbc, 0xb2, 0xc3, 0x16, 0xfa, 0x3f, 0x24, 0xde, 0xfc, 0xad, 0x4d, 0x4d,
+	0xcb, 0x6f, 0x39, 0x87, 0x91, 0x1a, 0xc5, 0x18, 0xf8, 0x8e, 0x2b, 0xb6, 0x87, 0x03, 0xe4, 0x0e,
+	0x14, 0x9c, 0x09, 0xe5, 0x87, 0x72, 0x90, 0x89, 0x66, 0x08, 0xbc, 0xe8, 0xfb, 0xae, 0x31, 0xa0,
+	0xe1, 0xb5, 0x21, 0xc7, 0xe0, 0xa6, 0x79, 0xf9, 0x6e, 0x37, 0x8e, 0x60, 0xbd, 0xb8, 0x8a, 0x7c,
+	0xf1, 0x08, 0xc6, 0x18, 0x16, 0x50, 0xd9, 0xe4, 0x02, 0x2a, 0x17, 0x7b, 0xa9, 0xf9, 0x7d, 0x0a,
+	0x80, 0x2d, 0x9a, 0x57, 0x3f, 0x61, 0x3a, 0x4a, 0xb3, 0x8c, 0x16, 0x7f, 0x95, 0x48, 0x5d, 0xe2,
+	0x55, 0x22, 0x34, 0x5d, 0x3a, 0x6a, 0x3a, 0x22, 0x1e, 0xa2, 0x78, 0x8a, 0x61, 0xff, 0xe3, 0xe6,
+	0x94, 0xe7, 0xcd, 0x79, 0x07, 0x0a, 0x86, 0x7b, 0x38, 0x1d, 0x53, 0xdb, 0xf7, 0xc4, 0x95, 0x28,
+	0x44, 0xe0, 0xba, 0x44, 0xd7, 0x88, 0x57, 0x40, 0x02, 0x62, 0xb3, 0xb3, 0x46, 0x51, 0x5e, 0xcc,
+	0x8e, 0xc0, 0xac, 0xd9, 0x53, 0x48, 0x6a, 0xf6, 0x40, 0xb4, 0xd9, 0x13, 0xdd, 0xc4, 0x62, 0x6c,
+	0x13, 0xd5, 0x57, 0xb0, 0x12, 0x77, 0x15, 0x51, 0x1c, 0xfc, 0xd7, 0x7c, 0xe1, 0x14, 0x5c, 0xdb,
+	0x43, 0xfb, 0x5e, 0x50, 0x32, 0xa9, 0x0d, 0x28, 0x89, 0xb4, 0xd0, 0x39, 0xb5, 0xa9, 0xcb, 0x5f,
+	0x4e, 0x3c, 0x1f, 0x3b, 0x76, 0x41, 0x15, 0x1f, 0xc0, 0xac, 0x1d, 0x65, 0x9a, 0x2e, 0xf5, 0x66,
+	0x19, 0x4d, 0x80, 0xf7, 0x37, 0xa0, 0x30, 0x7b, 0xd9, 0xc3, 0x2b, 0xce, 0xae, 0xb6, 0xbb, 0xc5,
+	0x9a, 0x76, 0x45, 0xc8, 0x75, 0x9e, 0x3e, 0x6d, 0xd6, 0x83, 0x76, 0x5d, 0xe7, 0x65, 0x5b, 0xd3,
+	0x95, 0xd4, 0xfd, 0xc7, 0xe2, 0x6a, 0x54, 0x00, 0xf9, 0x69, 0x73, 0x7b, 0xa7, 0xc7, 0x59, 0x6b,
+	0x7a, 0x7d, 0xa7, 0xf9, 0x42, 0xdc, 0x28, 0x5b, 0xda, 0x0b, 0xad, 0xa5, 0xa4, 0xf0, 0x96, 0xf4,
+	0xe5, 0x7e, 0xb3, 0xa7, 0xa4, 0x1f, 0xfd, 0xfd, 0x1a, 0xe4, 0x59, 0x19, 0xd1, 0x3d, 0x19, 0x90,
+	0xc7, 0x50, 0x98, 0x1d, 0x12, 0x64, 0x56, 0xb3, 0xcd, 0x1d, 0x1b, 0xd5, 0xe8, 0xf1, 0xb5, 0x29,
+	0x91, 0xff, 0x87, 0x62, 0xe4, 0x44, 0x24, 0xb7, 0x82, 0x61, 0x0b, 0xa7, 0x64, 0x35, 0xa1, 0x1f,
+	0x46, 0x3e, 0x07, 0x08, 0x4f, 0x64, 0x52, 0x99, 0x71, 0xcc, 0x1d, 0xd2, 0x89, 0x63, 0x37, 0x20,
+	0x83, 0x75, 0x1d, 0x09, 0x68, 0x91, 0x2a, 0xb1, 0x7a, 0x23, 0x86, 0x13, 0x7b, 0xbb, 0x0d, 0xa5,
+	0xe8, 0x0b, 0x06, 0x09, 0x5a, 0xba, 0x09, 0xaf, 0x1f, 0xd5, 0xdb, 0x89, 0x34, 0x21, 0xe8, 0x13,
+	0x90, 0x59, 0x8f, 0x84, 0xdc, 0x88, 0x77, 0x4c, 0xf8, 0xd0, 0x95, 0xa4, 0x36, 0xca, 0xa6, 0x44,
+	0x1a, 0x50, 0x8c, 0x14, 0xc0, 0xa1, 0xa5, 0x16, 0x2e, 0x55, 0xd5, 0x6a, 0x12, 0x49, 0xcc, 0xbd,
+	0x07, 0xca, 0xfc, 0xad, 0x9f, 0xbc, 0x23, 0xf8, 0x97, 0xb4, 0x03, 0xaa, 0x95, 0x45, 0x79, 0x3c,
+	0x6b, 0xae, 0x49, 0x9b, 0x12, 0x69, 0xc1, 0xb5, 0xb9, 0x0b, 0x28, 0xb9, 0x1b, 0xd9, 0xe3, 0x4b,
+	0xea, 0xd7, 0x85, 0x15, 0xa6, 0xc6, 0x77, 0x27, 0x72, 0x53, 0x22, 0x6d, 0x50, 0xe6, 0x2f, 0xa0,
+	0xb3, 0x45, 0x2f, 0xb9, 0x99, 0x9e, 0xab, 0xe4, 0x3e, 0xbc, 0x25, 0x6c, 0xf5, 0xdd, 0x09, 0xdd,
+	0x94, 0xc8, 0x13, 0x90, 0x59, 0xc3, 0x70, 0xe6, 0x17, 0xd1, 0xf7, 0xab, 0xea, 0x4a, 0x1c, 0xc9,
+	0x47, 0xb1, 0x1d, 0xd8, 0x80, 0x32, 0xff, 0x0e, 0x62, 0xf6, 0x32, 0x2d, 0x58, 0x05, 0x5c, 0x9d,
+	0x83, 0xc9, 0x13, 0x80, 0xf0, 0xa9, 0x70, 0x16, 0x36, 0x0b, 0xaf, 0x87, 0x0b, 0xe3, 0x36, 0xa0,
+	0xcc, 0x37, 0xfe, 0x4d, 0x27, 0x7a, 0x06, 0xe5, 0xd8, 0x33, 0x22, 0x09, 0xe2, 0x22, 0xe9, 0x11,
+	0xb2, 0x7a, 0x27, 0x99, 0x38, 0x8b, 0x9a, 0xb2, 0x60, 0x14, 0x6f, 0xd4, 0xf1, 0x07, 0xe7, 0x40,
+	0x48, 0xfc, 0x65, 0x96, 0x3c, 0x86, 0x52, 0x6d, 0x30, 0xa0, 0x93, 0x4b, 0x0d, 0xfa, 0x04, 0xd5,
+	0x1e, 0x8c, 0x2c, 0x9b, 0x5e, 0x66, 0x14, 0x06, 0x68, 0xf8, 0x76, 0x1c, 0x06, 0xe8, 0xc2, 0xab,
+	0x73, 0xb5, 0x9a, 0x44, 0x12, 0xcb, 0xd4, 0x81, 0x2c, 0xbe, 0x20, 0x93, 0xd5, 0xd8, 0x54, 0xde,
+	0x25, 0xfd, 0xf5, 0x09, 0x14, 0x23, 0x1f, 0xca, 0xcc, 0x34, 0x5b, 0xfc, 0x78, 0xa6, 0x5a, 0x8a,
+	0x7e, 0x86, 0x41, 0x36, 0x21, 0x1f, 0x7c, 0x26, 0x43, 0xde, 0x0e, 0xbd, 0xe4, 0x9c, 0x11, 0x8f,
+	0xa0, 0x30, 0xfb, 0x62, 0x66, 0x76, 0x06, 0xcc, 0x7f, 0x43, 0x33, 0x37, 0xa6, 0x06, 0x10, 0x7e,
+	0x08, 0x43, 0x22, 0xc9, 0x26, 0xfe, 0x01, 0x4d, 0xf5, 0x56, 0x02, 0x45, 0x2c, 0xf0, 0x73, 0xb8,
+	0xf6, 0xdc, 0x1a, 0x1c, 0x47, 0x3f, 0x42, 0xba, 0x95, 0xf0, 0xad, 0x53, 0xe2, 0xf4, 0x9f, 0xe2,
+	0x79, 0xeb, 0x87, 0x87, 0xe5, 0x1b, 0x0f, 0xec, 0x82, 0x32, 0xff, 0xb5, 0xca, 0x2c, 0x01, 0x2c,
+	0xf9, 0x84, 0xa6, 0xfa, 0xee, 0x05, 0x9f, 0xb9, 0x90, 0xaf, 0x44, 0x6a, 0xf9, 0xce, 0x25, 0x6f,
+	0x4a, 0x78, 0x80, 0x45, 0x8b, 0x16, 0x12, 0x75, 0xc3, 0xb9, 0xa2, 0xb7, 0x7a, 0x3b, 0x91, 0xc6,
+	0x45, 0x6d, 0x15, 0xbe, 0xca, 0xad, 0xff, 0x1f, 0xa3, 0x1f, 0x64, 0x59, 0x01, 0xf8, 0xf8, 0x5f,
+	0x03, 0x00, 0x4e, 0x34, 0xc9, 0x9d, 0xfe, 0x26, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// FightSvcClient is the client API for FightSvc service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type FightSvcClient interface {
+	// here stream is used to trans a big mount of data.
+	ListHeros(ctx context.Context, in *ListHerosRequest, opts ...grpc.CallOption) (FightSvc_ListHerosClient, error)
+	LoadSession(ctx context.Context, in *LoadSessionRequest, opts ...grpc.CallOption) (*SessionView, error)
+	SelectHero(ctx context.Context, in *SelectHeroRequest, opts ...grpc.CallOption) (*SessionView, error)
+	Game(ctx context.Context, in *GameRequest, opts ...grpc.CallOption) (*GameResponse, error)
+	ClearSession(ctx context.Context, in *ClearSessionRequest, opts ...grpc.CallOption) (*ClearSessionResponse, error)
+	// here stream is used to keep the long-alive connection and get real top10
+	Top10(ctx context.Context, in *Top10Request, opts ...grpc.CallOption) (FightSvc_Top10Client, error)
+	// pages the ranking of the online sessions, and locates a player in it
+	Leaderboard(ctx context.Context, in *LeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardResponse, error)
+	// streams the changes of the ranking of the online sessions: a snapshot
+	// first, then the deltas. A resync request gets a new snapshot.
+	WatchLeaderboard(ctx context.Context, opts ...grpc.CallOption) (FightSvc_WatchLeaderboardClient, error)
+	// ranks the best scores reached with the hero
+	HeroLeaderboard(ctx context.Context, in *HeroLeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardResponse, error)
+	WatchHeroLeaderboard(ctx context.Context, in *HeroLeaderboardRequest, opts ...grpc.CallOption) (FightSvc_WatchHeroLeaderboardClient, error)
+	// ranks the fastest clears of the boss level
+	LevelLeaderboard(ctx context.Context, in *LevelLeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardResponse, error)
+	WatchLevelLeaderboard(ctx context.Context, in *LevelLeaderboardRequest, opts ...grpc.CallOption) (FightSvc_WatchLevelLeaderboardClient, error)
+	Admin(ctx context.Context, opts ...grpc.CallOption) (FightSvc_AdminClient, error)
+	// the profile of the player of a session, shown in the leaderboards.
+	// Creating, updating and deleting it requires the session token.
+	CreateProfile(ctx context.Context, in *Profile, opts ...grpc.CallOption) (*Profile, error)
+	GetProfile(ctx context.Context, in *GetProfileRequest, opts ...grpc.CallOption) (*Profile, error)
+	UpdateProfile(ctx context.Context, in *Profile, opts ...grpc.CallOption) (*Profile, error)
+	DeleteProfile(ctx context.Context, in *DeleteProfileRequest, opts ...grpc.CallOption) (*DeleteProfileResponse, error)
+	// the friends are addressed by their display names, the requests of the
+	// session require its token
+	RequestFriend(ctx context.Context, in *FriendRequest, opts ...grpc.CallOption) (*Friend, error)
+	AcceptFriend(ctx context.Context, in *FriendRequest, opts ...grpc.CallOption) (*Friend, error)
+	DeclineFriend(ctx context.Context, in *FriendRequest, opts ...grpc.CallOption) (*Friend, error)
+	ListFriends(ctx context.Context, in *ListFriendsRequest, opts ...grpc.CallOption) (*ListFriendsResponse, error)
+	// ranks the player and its friends
+	FriendsLeaderboard(ctx context.Context, in *FriendsLeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardResponse, error)
+	// a player is a member of one guild at most, the members are addressed
+	// by their display names
+	CreateGuild(ctx context.Context, in *CreateGuildRequest, opts ...grpc.CallOption) (*Guild, error)
+	GetGuild(ctx context.Context, in *GetGuildRequest, opts ...grpc.CallOption) (*Guild, error)
+	JoinGuild(ctx context.Context, in *JoinGuildRequest, opts ...grpc.CallOption) (*Guild, error)
+	LeaveGuild(ctx context.Context, in *LeaveGuildRequest, opts ...grpc.CallOption) (*LeaveGuildResponse, error)
+	// the owner kicks the officers and the members, the officers kick the members
+	KickGuildMember(ctx context.Context, in *GuildMemberRequest, opts ...grpc.CallOption) (*Guild, error)
+	// the owner sets the roles, setting OWNER hands the guild over
+	SetGuildRole(ctx context.Context, in *GuildMemberRequest, opts ...grpc.CallOption) (*Guild, error)
+	// ranks the guilds by the sum of the best scores of their members
+	GuildLeaderboard(ctx context.Context, in *GuildLeaderboardRequest, opts ...grpc.CallOption) (*GuildLeaderboardResponse, error)
+	WatchGuildLeaderboard(ctx context.Context, in *GuildLeaderboardRequest, opts ...grpc.CallOption) (FightSvc_WatchGuildLeaderboardClient, error)
+	// pages the audit log of the admin requests and the ClearSession calls,
+	// the latest first
+	ListAuditLog(ctx context.Context, in *ListAuditLogRequest, opts ...grpc.CallOption) (*ListAuditLogResponse, error)
+}
+
+type fightSvcClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewFightSvcClient(cc *grpc.ClientConn) FightSvcClient {
+	return &fightSvcClient{cc}
+}
+
+func (c *fightSvcClient) ListHeros(ctx context.Context, in *ListHerosRequest, opts ...grpc.CallOption) (FightSvc_ListHerosClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_FightSvc_serviceDesc.Streams[0], "/fight.FightSvc/ListHeros", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fightSvcListHerosClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FightSvc_ListHerosClient interface {
+	Recv() (*Hero, error)
+	grpc.ClientStream
+}
+
+type fightSvcListHerosClient struct {
+	grpc.ClientStream
+}
+
+func (x *fightSvcListHerosClient) Recv() (*Hero, error) {
+	m := new(Hero)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fightSvcClient) LoadSession(ctx context.Context, in *LoadSessionRequest, opts ...grpc.CallOption) (*SessionView, error) {
+	out := new(SessionView)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/LoadSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) SelectHero(ctx context.Context, in *SelectHeroRequest, opts ...grpc.CallOption) (*SessionView, error) {
+	out := new(SessionView)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/SelectHero", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) Game(ctx context.Context, in *GameRequest, opts ...grpc.CallOption) (*GameResponse, error) {
+	out := new(GameResponse)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/Game", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) ClearSession(ctx context.Context, in *ClearSessionRequest, opts ...grpc.CallOption) (*ClearSessionResponse, error) {
+	out := new(ClearSessionResponse)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/ClearSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) Top10(ctx context.Context, in *Top10Request, opts ...grpc.CallOption) (FightSvc_Top10Client, error) {
+	stream, err := c.cc.NewStream(ctx, &_FightSvc_serviceDesc.Streams[1], "/fight.FightSvc/Top10", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fightSvcTop10Client{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FightSvc_Top10Client interface {
+	Recv() (*Top10Response, error)
+	grpc.ClientStream
+}
+
+type fightSvcTop10Client struct {
+	grpc.ClientStream
+}
+
+func (x *fightSvcTop10Client) Recv() (*Top10Response, error) {
+	m := new(Top10Response)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fightSvcClient) Leaderboard(ctx context.Context, in *LeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardResponse, error) {
+	out := new(LeaderboardResponse)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/Leaderboard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) WatchLeaderboard(ctx context.Context, opts ...grpc.CallOption) (FightSvc_WatchLeaderboardClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_FightSvc_serviceDesc.Streams[2], "/fight.FightSvc/WatchLeaderboard", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fightSvcWatchLeaderboardClient{stream}
+	return x, nil
+}
+
+type FightSvc_WatchLeaderboardClient interface {
+	Send(*WatchLeaderboardRequest) error
+	Recv() (*LeaderboardUpdate, error)
+	grpc.ClientStream
+}
+
+type fightSvcWatchLeaderboardClient struct {
+	grpc.ClientStream
+}
+
+func (x *fightSvcWatchLeaderboardClient) Send(m *WatchLeaderboardRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *fightSvcWatchLeaderboardClient) Recv() (*LeaderboardUpdate, error) {
+	m := new(LeaderboardUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fightSvcClient) HeroLeaderboard(ctx context.Context, in *HeroLeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardResponse, error) {
+	out := new(LeaderboardResponse)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/HeroLeaderboard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) WatchHeroLeaderboard(ctx context.Context, in *HeroLeaderboardRequest, opts ...grpc.CallOption) (FightSvc_WatchHeroLeaderboardClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_FightSvc_serviceDesc.Streams[3], "/fight.FightSvc/WatchHeroLeaderboard", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fightSvcWatchHeroLeaderboardClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FightSvc_WatchHeroLeaderboardClient interface {
+	Recv() (*LeaderboardResponse, error)
+	grpc.ClientStream
+}
+
+type fightSvcWatchHeroLeaderboardClient struct {
+	grpc.ClientStream
+}
+
+func (x *fightSvcWatchHeroLeaderboardClient) Recv() (*LeaderboardResponse, error) {
+	m := new(LeaderboardResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fightSvcClient) LevelLeaderboard(ctx context.Context, in *LevelLeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardResponse, error) {
+	out := new(LeaderboardResponse)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/LevelLeaderboard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) WatchLevelLeaderboard(ctx context.Context, in *LevelLeaderboardRequest, opts ...grpc.CallOption) (FightSvc_WatchLevelLeaderboardClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_FightSvc_serviceDesc.Streams[4], "/fight.FightSvc/WatchLevelLeaderboard", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fightSvcWatchLevelLeaderboardClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FightSvc_WatchLevelLeaderboardClient interface {
+	Recv() (*LeaderboardResponse, error)
+	grpc.ClientStream
+}
+
+type fightSvcWatchLevelLeaderboardClient struct {
+	grpc.ClientStream
+}
+
+func (x *fightSvcWatchLevelLeaderboardClient) Recv() (*LeaderboardResponse, error) {
+	m := new(LeaderboardResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fightSvcClient) Admin(ctx context.Context, opts ...grpc.CallOption) (FightSvc_AdminClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_FightSvc_serviceDesc.Streams[5], "/fight.FightSvc/Admin", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fightSvcAdminClient{stream}
+	return x, nil
+}
+
+type FightSvc_AdminClient interface {
+	Send(*AdminRequest) error
+	Recv() (*AdminResponse, error)
+	grpc.ClientStream
+}
+
+type fightSvcAdminClient struct {
+	grpc.ClientStream
+}
+
+func (x *fightSvcAdminClient) Send(m *AdminRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *fightSvcAdminClient) Recv() (*AdminResponse, error) {
+	m := new(AdminResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fightSvcClient) CreateProfile(ctx context.Context, in *Profile, opts ...grpc.CallOption) (*Profile, error) {
+	out := new(Profile)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/CreateProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) GetProfile(ctx context.Context, in *GetProfileRequest, opts ...grpc.CallOption) (*Profile, error) {
+	out := new(Profile)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/GetProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) UpdateProfile(ctx context.Context, in *Profile, opts ...grpc.CallOption) (*Profile, error) {
+	out := new(Profile)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/UpdateProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) DeleteProfile(ctx context.Context, in *DeleteProfileRequest, opts ...grpc.CallOption) (*DeleteProfileResponse, error) {
+	out := new(DeleteProfileResponse)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/DeleteProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) RequestFriend(ctx context.Context, in *FriendRequest, opts ...grpc.CallOption) (*Friend, error) {
+	out := new(Friend)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/RequestFriend", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) AcceptFriend(ctx context.Context, in *FriendRequest, opts ...grpc.CallOption) (*Friend, error) {
+	out := new(Friend)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/AcceptFriend", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) DeclineFriend(ctx context.Context, in *FriendRequest, opts ...grpc.CallOption) (*Friend, error) {
+	out := new(Friend)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/DeclineFriend", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) ListFriends(ctx context.Context, in *ListFriendsRequest, opts ...grpc.CallOption) (*ListFriendsResponse, error) {
+	out := new(ListFriendsResponse)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/ListFriends", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) FriendsLeaderboard(ctx context.Context, in *FriendsLeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardResponse, error) {
+	out := new(LeaderboardResponse)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/FriendsLeaderboard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) CreateGuild(ctx context.Context, in *CreateGuildRequest, opts ...grpc.CallOption) (*Guild, error) {
+	out := new(Guild)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/CreateGuild", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) GetGuild(ctx context.Context, in *GetGuildRequest, opts ...grpc.CallOption) (*Guild, error) {
+	out := new(Guild)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/GetGuild", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) JoinGuild(ctx context.Context, in *JoinGuildRequest, opts ...grpc.CallOption) (*Guild, error) {
+	out := new(Guild)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/JoinGuild", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) LeaveGuild(ctx context.Context, in *LeaveGuildRequest, opts ...grpc.CallOption) (*LeaveGuildResponse, error) {
+	out := new(LeaveGuildResponse)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/LeaveGuild", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) KickGuildMember(ctx context.Context, in *GuildMemberRequest, opts ...grpc.CallOption) (*Guild, error) {
+	out := new(Guild)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/KickGuildMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) SetGuildRole(ctx context.Context, in *GuildMemberRequest, opts ...grpc.CallOption) (*Guild, error) {
+	out := new(Guild)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/SetGuildRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) GuildLeaderboard(ctx context.Context, in *GuildLeaderboardRequest, opts ...grpc.CallOption) (*GuildLeaderboardResponse, error) {
+	out := new(GuildLeaderboardResponse)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/GuildLeaderboard", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fightSvcClient) WatchGuildLeaderboard(ctx context.Context, in *GuildLeaderboardRequest, opts ...grpc.CallOption) (FightSvc_WatchGuildLeaderboardClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_FightSvc_serviceDesc.Streams[6], "/fight.FightSvc/WatchGuildLeaderboard", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fightSvcWatchGuildLeaderboardClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FightSvc_WatchGuildLeaderboardClient interface {
+	Recv() (*GuildLeaderboardResponse, error)
+	grpc.ClientStream
+}
+
+type fightSvcWatchGuildLeaderboardClient struct {
+	grpc.ClientStream
+}
+
+func (x *fightSvcWatchGuildLeaderboardClient) Recv() (*GuildLeaderboardResponse, error) {
+	m := new(GuildLeaderboardResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fightSvcClient) ListAuditLog(ctx context.Context, in *ListAuditLogRequest, opts ...grpc.CallOption) (*ListAuditLogResponse, error) {
+	out := new(ListAuditLogResponse)
+	err := c.cc.Invoke(ctx, "/fight.FightSvc/ListAuditLog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FightSvcServer is the server API for FightSvc service.
+type FightSvcServer interface {
+	// here stream is used to trans a big mount of data.
+	ListHeros(*ListHerosRequest, FightSvc_ListHerosServer) error
+	LoadSession(context.Context, *LoadSessionRequest) (*SessionView, error)
+	SelectHero(context.Context, *SelectHeroRequest) (*SessionView, error)
+	Game(context.Context, *GameRequest) (*GameResponse, error)
+	ClearSession(context.Context, *ClearSessionRequest) (*ClearSessionResponse, error)
+	// here stream is used to keep the long-alive connection and get real top10
+	Top10(*Top10Request, FightSvc_Top10Server) error
+	// pages the ranking of the online sessions, and locates a player in it
+	Leaderboard(context.Context, *LeaderboardRequest) (*LeaderboardResponse, error)
+	// streams the changes of the ranking of the online sessions: a snapshot
+	// first, then the deltas. A resync request gets a new snapshot.
+	WatchLeaderboard(FightSvc_WatchLeaderboardServer) error
+	// ranks the best scores reached with the hero
+	HeroLeaderboard(context.Context, *HeroLeaderboardRequest) (*LeaderboardResponse, error)
+	WatchHeroLeaderboard(*HeroLeaderboardRequest, FightSvc_WatchHeroLeaderboardServer) error
+	// ranks the fastest clears of the boss level
+	LevelLeaderboard(context.Context, *LevelLeaderboardRequest) (*LeaderboardResponse, error)
+	WatchLevelLeaderboard(*LevelLeaderboardRequest, FightSvc_WatchLevelLeaderboardServer) error
+	Admin(FightSvc_AdminServer) error
+	// the profile of the player of a session, shown in the leaderboards.
+	// Creating, updating and deleting it requires the session token.
+	CreateProfile(context.Context, *Profile) (*Profile, error)
+	GetProfile(context.Context, *GetProfileRequest) (*Profile, error)
+	UpdateProfile(context.Context, *Profile) (*Profile, error)
+	DeleteProfile(context.Context, *DeleteProfileRequest) (*DeleteProfileResponse, error)
+	// the friends are addressed by their display names, the requests of the
+	// session require its token
+	RequestFriend(context.Context, *FriendRequest) (*Friend, error)
+	AcceptFriend(context.Context, *FriendRequest) (*Friend, error)
+	DeclineFriend(context.Context, *FriendRequest) (*Friend, error)
+	ListFriends(context.Context, *ListFriendsRequest) (*ListFriendsResponse, error)
+	// ranks the player and its friends
+	FriendsLeaderboard(context.Context, *FriendsLeaderboardRequest) (*LeaderboardResponse, error)
+	// a player is a member of one guild at most, the members are addressed
+	// by their display names
+	CreateGuild(context.Context, *CreateGuildRequest) (*Guild, error)
+	GetGuild(context.Context, *GetGuildRequest) (*Guild, error)
+	JoinGuild(context.Context, *JoinGuildRequest) (*Guild, error)
+	LeaveGuild(context.Context, *LeaveGuildRequest) (*LeaveGuildResponse, error)
+	// the owner kicks the officers and the members, the officers kick the members
+	KickGuildMember(context.Context, *GuildMemberRequest) (*Guild, error)
+	// the owner sets the roles, setting OWNER hands the guild over
+	SetGuildRole(context.Context, *GuildMemberRequest) (*Guild, error)
+	// ranks the guilds by the sum of the best scores of their members
+	GuildLeaderboard(context.Context, *GuildLeaderboardRequest) (*GuildLeaderboardResponse, error)
+	WatchGuildLeaderboard(*GuildLeaderboardRequest, FightSvc_WatchGuildLeaderboardServer) error
+	// pages the audit log of the admin requests and the ClearSession calls,
+	// the latest first
+	ListAuditLog(context.Context, *ListAuditLogRequest) (*ListAuditLogResponse, error)
+}
+
+// UnimplementedFightSvcServer can be embedded to have forward compatible implementations.
+type UnimplementedFightSvcServer struct {
+}
+
+func (*UnimplementedFightSvcServer) ListHeros(req *ListHerosRequest, srv FightSvc_ListHerosServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListHeros not implemented")
+}
+func (*UnimplementedFightSvcServer) LoadSession(ctx context.Context, req *LoadSessionRequest) (*SessionView, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoadSession not implemented")
+}
+func (*UnimplementedFightSvcServer) SelectHero(ctx context.Context, req *SelectHeroRequest) (*SessionView, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SelectHero not implemented")
+}
+func (*UnimplementedFightSvcServer) Game(ctx context.Context, req *GameRequest) (*GameResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Game not implemented")
+}
+func (*UnimplementedFightSvcServer) ClearSession(ctx context.Context, req *ClearSessionRequest) (*ClearSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearSession not implemented")
+}
+func (*UnimplementedFightSvcServer) Top10(req *Top10Request, srv FightSvc_Top10Server) error {
+	return status.Errorf(codes.Unimplemented, "method Top10 not implemented")
+}
+func (*UnimplementedFightSvcServer) Leaderboard(ctx context.Context, req *LeaderboardRequest) (*LeaderboardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Leaderboard not implemented")
+}
+func (*UnimplementedFightSvcServer) WatchLeaderboard(srv FightSvc_WatchLeaderboardServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchLeaderboard not implemented")
+}
+func (*UnimplementedFightSvcServer) HeroLeaderboard(ctx context.Context, req *HeroLeaderboardRequest) (*LeaderboardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HeroLeaderboard not implemented")
+}
+func (*UnimplementedFightSvcServer) WatchHeroLeaderboard(req *HeroLeaderboardRequest, srv FightSvc_WatchHeroLeaderboardServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchHeroLeaderboard not implemented")
+}
+func (*UnimplementedFightSvcServer) LevelLeaderboard(ctx context.Context, req *LevelLeaderboardRequest) (*LeaderboardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LevelLeaderboard not implemented")
+}
+func (*UnimplementedFightSvcServer) WatchLevelLeaderboard(req *LevelLeaderboardRequest, srv FightSvc_WatchLevelLeaderboardServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchLevelLeaderboard not implemented")
+}
+func (*UnimplementedFightSvcServer) Admin(srv FightSvc_AdminServer) error {
+	return status.Errorf(codes.Unimplemented, "method Admin not implemented")
+}
+func (*UnimplementedFightSvcServer) CreateProfile(ctx context.Context, req *Profile) (*Profile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateProfile not implemented")
+}
+func (*UnimplementedFightSvcServer) GetProfile(ctx context.Context, req *GetProfileRequest) (*Profile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProfile not implemented")
+}
+func (*UnimplementedFightSvcServer) UpdateProfile(ctx context.Context, req *Profile) (*Profile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateProfile not implemented")
+}
+func (*UnimplementedFightSvcServer) DeleteProfile(ctx context.Context, req *DeleteProfileRequest) (*DeleteProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteProfile not implemented")
+}
+func (*UnimplementedFightSvcServer) RequestFriend(ctx context.Context, req *FriendRequest) (*Friend, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestFriend not implemented")
+}
+func (*UnimplementedFightSvcServer) AcceptFriend(ctx context.Context, req *FriendRequest) (*Friend, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptFriend not implemented")
+}
+func (*UnimplementedFightSvcServer) DeclineFriend(ctx context.Context, req *FriendRequest) (*Friend, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeclineFriend not implemented")
+}
+func (*UnimplementedFightSvcServer) ListFriends(ctx context.Context, req *ListFriendsRequest) (*ListFriendsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFriends not implemented")
+}
+func (*UnimplementedFightSvcServer) FriendsLeaderboard(ctx context.Context, req *FriendsLeaderboardRequest) (*LeaderboardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FriendsLeaderboard not implemented")
+}
+func (*UnimplementedFightSvcServer) CreateGuild(ctx context.Context, req *CreateGuildRequest) (*Guild, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGuild not implemented")
+}
+func (*UnimplementedFightSvcServer) GetGuild(ctx context.Context, req *GetGuildRequest) (*Guild, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGuild not implemented")
+}
+func (*UnimplementedFightSvcServer) JoinGuild(ctx context.Context, req *JoinGuildRequest) (*Guild, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JoinGuild not implemented")
+}
+func (*UnimplementedFightSvcServer) LeaveGuild(ctx context.Context, req *LeaveGuildRequest) (*LeaveGuildResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaveGuild not implemented")
+}
+func (*UnimplementedFightSvcServer) KickGuildMember(ctx context.Context, req *GuildMemberRequest) (*Guild, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KickGuildMember not implemented")
+}
+func (*UnimplementedFightSvcServer) SetGuildRole(ctx context.Context, req *GuildMemberRequest) (*Guild, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetGuildRole not implemented")
+}
+func (*UnimplementedFightSvcServer) GuildLeaderboard(ctx context.Context, req *GuildLeaderboardRequest) (*GuildLeaderboardResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GuildLeaderboard not implemented")
+}
+func (*UnimplementedFightSvcServer) WatchGuildLeaderboard(req *GuildLeaderboardRequest, srv FightSvc_WatchGuildLeaderboardServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchGuildLeaderboard not implemented")
+}
+func (*UnimplementedFightSvcServer) ListAuditLog(ctx context.Context, req *ListAuditLogRequest) (*ListAuditLogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditLog not implemented")
+}
+
+func RegisterFightSvcServer(s *grpc.Server, srv FightSvcServer) {
+	s.RegisterService(&_FightSvc_serviceDesc, srv)
+}
+
+func _FightSvc_ListHeros_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListHerosRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FightSvcServer).ListHeros(m, &fightSvcListHerosServer{stream})
+}
+
+type FightSvc_ListHerosServer interface {
+	Send(*Hero) error
+	grpc.ServerStream
+}
+
+type fightSvcListHerosServer struct {
+	grpc.ServerStream
+}
+
+func (x *fightSvcListHerosServer) Send(m *Hero) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FightSvc_LoadSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FightSvcServer).LoadSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fight.FightSvc/LoadSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FightSvcServer).LoadSession(ctx, req.(*LoadSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FightSvc_SelectHero_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SelectHeroRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FightSvcServer).SelectHero(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fight.FightSvc/SelectHero",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FightSvcServer).SelectHero(ctx, req.(*SelectHeroRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FightSvc_Game_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FightSvcServer).Game(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fight.FightSvc/Game",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FightSvcServer).Game(ctx, req.(*GameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FightSvc_ClearSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearSessionRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
+	if interceptor == nil {
+		return srv.(FightSvcServer).ClearSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fight.FightSvc/ClearSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FightSvcServer).ClearSession(ctx, req.(*ClearSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FightSvc_Top10_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Top10Request)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FightSvcServer).Top10(m, &fightSvcTop10Server{stream})
+}
+
+type FightSvc_Top10Server interface {
+	Send(*Top10Response) error
+	grpc.ServerStream
+}
+
+type fightSvcTop10Server struct {
+	grpc.ServerStream
+}
+
+func (x *fightSvcTop10Server) Send(m *Top10Response) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FightSvc_Leaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaderboardRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return x, nil
+	if interceptor == nil {
+		return srv.(FightSvcServer).Leaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fight.FightSvc/Leaderboard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FightSvcServer).Leaderboard(ctx, req.(*LeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type FightSvc_ListHerosClient interface {
-	Recv() (*Hero, error)
-	grpc.ClientStream
+func _FightSvc_WatchLeaderboard_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FightSvcServer).WatchLeaderboard(&fightSvcWatchLeaderboardServer{stream})
 }
 
-type fightSvcListHerosClient struct {
-	grpc.ClientStream
+type FightSvc_WatchLeaderboardServer interface {
+	Send(*LeaderboardUpdate) error
+	Recv() (*WatchLeaderboardRequest, error)
+	grpc.ServerStream
 }
 
-func (x *fightSvcListHerosClient) Recv() (*Hero, error) {
-	m := new(Hero)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+type fightSvcWatchLeaderboardServer struct {
+	grpc.ServerStream
+}
+
+func (x *fightSvcWatchLeaderboardServer) Send(m *LeaderboardUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *fightSvcWatchLeaderboardServer) Recv() (*WatchLeaderboardRequest, error) {
+	m := new(WatchLeaderboardRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func (c *fightSvcClient) LoadSession(ctx context.Context, in *LoadSessionRequest, opts ...grpc.CallOption) (*SessionView, error) {
-	out := new(SessionView)
-	err := c.cc.Invoke(ctx, "/fight.FightSvc/LoadSession", in, out, opts...)
-	if err != nil {
+func _FightSvc_HeroLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeroLeaderboardRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(FightSvcServer).HeroLeaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fight.FightSvc/HeroLeaderboard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FightSvcServer).HeroLeaderboard(ctx, req.(*HeroLeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *fightSvcClient) SelectHero(ctx context.Context, in *SelectHeroRequest, opts ...grpc.CallOption) (*SessionView, error) {
-	out := new(SessionView)
-	err := c.cc.Invoke(ctx, "/fight.FightSvc/SelectHero", in, out, opts...)
-	if err != nil {
+func _FightSvc_WatchHeroLeaderboard_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(HeroLeaderboardRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FightSvcServer).WatchHeroLeaderboard(m, &fightSvcWatchHeroLeaderboardServer{stream})
+}
+
+type FightSvc_WatchHeroLeaderboardServer interface {
+	Send(*LeaderboardResponse) error
+	grpc.ServerStream
+}
+
+type fightSvcWatchHeroLeaderboardServer struct {
+	grpc.ServerStream
+}
+
+func (x *fightSvcWatchHeroLeaderboardServer) Send(m *LeaderboardResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FightSvc_LevelLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LevelLeaderboardRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(FightSvcServer).LevelLeaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fight.FightSvc/LevelLeaderboard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FightSvcServer).LevelLeaderboard(ctx, req.(*LevelLeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *fightSvcClient) Game(ctx context.Context, in *GameRequest, opts ...grpc.CallOption) (*GameResponse, error) {
-	out := new(GameResponse)
-	err := c.cc.Invoke(ctx, "/fight.FightSvc/Game", in, out, opts...)
-	if err != nil {
+func _FightSvc_WatchLevelLeaderboard_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LevelLeaderboardRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FightSvcServer).WatchLevelLeaderboard(m, &fightSvcWatchLevelLeaderboardServer{stream})
+}
+
+type FightSvc_WatchLevelLeaderboardServer interface {
+	Send(*LeaderboardResponse) error
+	grpc.ServerStream
+}
+
+type fightSvcWatchLevelLeaderboardServer struct {
+	grpc.ServerStream
+}
+
+func (x *fightSvcWatchLevelLeaderboardServer) Send(m *LeaderboardResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FightSvc_Admin_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FightSvcServer).Admin(&fightSvcAdminServer{stream})
+}
+
+type FightSvc_AdminServer interface {
+	Send(*AdminResponse) error
+	Recv() (*AdminRequest, error)
+	grpc.ServerStream
+}
+
+type fightSvcAdminServer struct {
+	grpc.ServerStream
+}
+
+func (x *fightSvcAdminServer) Send(m *AdminResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *fightSvcAdminServer) Recv() (*AdminRequest, error) {
+	m := new(AdminRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return out, nil
+	return m, nil
 }
 
-func (c *fightSvcClient) ClearSession(ctx context.Context, in *ClearSessionRequest, opts ...grpc.CallOption) (*ClearSessionResponse, error) {
-	out := new(ClearSessionResponse)
-	err := c.cc.Invoke(ctx, "/fight.FightSvc/ClearSession", in, out, opts...)
-	if err != nil {
+func _FightSvc_CreateProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Profile)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FightSvcServer).CreateProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fight.FightSvc/CreateProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FightSvcServer).CreateProfile(ctx, req.(*Profile))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FightSvc_GetProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FightSvcServer).GetProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fight.FightSvc/GetProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FightSvcServer).GetProfile(ctx, req.(*GetProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FightSvc_UpdateProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Profile)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FightSvcServer).UpdateProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fight.FightSvc/UpdateProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FightSvcServer).UpdateProfile(ctx, req.(*Profile))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FightSvc_DeleteProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FightSvcServer).DeleteProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fight.FightSvc/DeleteProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FightSvcServer).DeleteProfile(ctx, req.(*DeleteProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FightSvc_RequestFriend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FriendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FightSvcServer).RequestFriend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fight.FightSvc/RequestFriend",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FightSvcServer).RequestFriend(ctx, req.(*FriendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FightSvc_AcceptFriend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FriendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FightSvcServer).AcceptFriend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fight.FightSvc/AcceptFriend",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FightSvcServer).AcceptFriend(ctx, req.(*FriendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FightSvc_DeclineFriend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FriendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FightSvcServer).DeclineFriend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fight.FightSvc/DeclineFriend",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FightSvcServer).DeclineFriend(ctx, req.(*FriendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FightSvc_ListFriends_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFriendsRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(FightSvcServer).ListFriends(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fight.FightSvc/ListFriends",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FightSvcServer).ListFriends(ctx, req.(*ListFriendsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *fightSvcClient) Top10(ctx context.Context, in *Top10Request, opts ...grpc.CallOption) (FightSvc_Top10Client, error) {
-	stream, err := c.cc.NewStream(ctx, &_FightSvc_serviceDesc.Streams[1], "/fight.FightSvc/Top10", opts...)
-	if err != nil {
+func _FightSvc_FriendsLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FriendsLeaderboardRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &fightSvcTop10Client{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(FightSvcServer).FriendsLeaderboard(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fight.FightSvc/FriendsLeaderboard",
 	}
-	return x, nil
-}
-
-type FightSvc_Top10Client interface {
-	Recv() (*Top10Response, error)
-	grpc.ClientStream
-}
-
-type fightSvcTop10Client struct {
-	grpc.ClientStream
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FightSvcServer).FriendsLeaderboard(ctx, req.(*FriendsLeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *fightSvcTop10Client) Recv() (*Top10Response, error) {
-	m := new(Top10Response)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func _FightSvc_CreateGuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateGuildRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(FightSvcServer).CreateGuild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fight.FightSvc/CreateGuild",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FightSvcServer).CreateGuild(ctx, req.(*CreateGuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *fightSvcClient) Admin(ctx context.Context, opts ...grpc.CallOption) (FightSvc_AdminClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_FightSvc_serviceDesc.Streams[2], "/fight.FightSvc/Admin", opts...)
-	if err != nil {
+func _FightSvc_GetGuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGuildRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &fightSvcAdminClient{stream}
-	return x, nil
-}
-
-type FightSvc_AdminClient interface {
-	Send(*AdminRequest) error
-	Recv() (*AdminResponse, error)
-	grpc.ClientStream
-}
-
-type fightSvcAdminClient struct {
-	grpc.ClientStream
-}
-
-func (x *fightSvcAdminClient) Send(m *AdminRequest) error {
-	return x.ClientStream.SendMsg(m)
+	if interceptor == nil {
+		return srv.(FightSvcServer).GetGuild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fight.FightSvc/GetGuild",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FightSvcServer).GetGuild(ctx, req.(*GetGuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *fightSvcAdminClient) Recv() (*AdminResponse, error) {
-	m := new(AdminResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func _FightSvc_JoinGuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinGuildRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
-}
-
-// FightSvcServer is the server API for FightSvc service.
-type FightSvcServer interface {
-	// here stream is used to trans a big mount of data.
-	ListHeros(*ListHerosRequest, FightSvc_ListHerosServer) error
-	LoadSession(context.Context, *LoadSessionRequest) (*SessionView, error)
-	SelectHero(context.Context, *SelectHeroRequest) (*SessionView, error)
-	Game(context.Context, *GameRequest) (*GameResponse, error)
-	ClearSession(context.Context, *ClearSessionRequest) (*ClearSessionResponse, error)
-	// here stream is used to keep the long-alive connection and get real top10
-	Top10(*Top10Request, FightSvc_Top10Server) error
-	Admin(FightSvc_AdminServer) error
-}
-
-// UnimplementedFightSvcServer can be embedded to have forward compatible implementations.
-type UnimplementedFightSvcServer struct {
-}
-
-func (*UnimplementedFightSvcServer) ListHeros(req *ListHerosRequest, srv FightSvc_ListHerosServer) error {
-	return status.Errorf(codes.Unimplemented, "method ListHeros not implemented")
-}
-func (*UnimplementedFightSvcServer) LoadSession(ctx context.Context, req *LoadSessionRequest) (*SessionView, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method LoadSession not implemented")
-}
-func (*UnimplementedFightSvcServer) SelectHero(ctx context.Context, req *SelectHeroRequest) (*SessionView, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SelectHero not implemented")
-}
-func (*UnimplementedFightSvcServer) Game(ctx context.Context, req *GameRequest) (*GameResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Game not implemented")
-}
-func (*UnimplementedFightSvcServer) ClearSession(ctx context.Context, req *ClearSessionRequest) (*ClearSessionResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ClearSession not implemented")
-}
-func (*UnimplementedFightSvcServer) Top10(req *Top10Request, srv FightSvc_Top10Server) error {
-	return status.Errorf(codes.Unimplemented, "method Top10 not implemented")
-}
-func (*UnimplementedFightSvcServer) Admin(srv FightSvc_AdminServer) error {
-	return status.Errorf(codes.Unimplemented, "method Admin not implemented")
-}
-
-func RegisterFightSvcServer(s *grpc.Server, srv FightSvcServer) {
-	s.RegisterService(&_FightSvc_serviceDesc, srv)
-}
-
-func _FightSvc_ListHeros_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(ListHerosRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+	if interceptor == nil {
+		return srv.(FightSvcServer).JoinGuild(ctx, in)
 	}
-	return srv.(FightSvcServer).ListHeros(m, &fightSvcListHerosServer{stream})
-}
-
-type FightSvc_ListHerosServer interface {
-	Send(*Hero) error
-	grpc.ServerStream
-}
-
-type fightSvcListHerosServer struct {
-	grpc.ServerStream
-}
-
-func (x *fightSvcListHerosServer) Send(m *Hero) error {
-	return x.ServerStream.SendMsg(m)
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fight.FightSvc/JoinGuild",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FightSvcServer).JoinGuild(ctx, req.(*JoinGuildRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _FightSvc_LoadSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LoadSessionRequest)
+func _FightSvc_LeaveGuild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaveGuildRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(FightSvcServer).LoadSession(ctx, in)
+		return srv.(FightSvcServer).LeaveGuild(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/fight.FightSvc/LoadSession",
+		FullMethod: "/fight.FightSvc/LeaveGuild",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(FightSvcServer).LoadSession(ctx, req.(*LoadSessionRequest))
+		return srv.(FightSvcServer).LeaveGuild(ctx, req.(*LeaveGuildRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _FightSvc_SelectHero_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SelectHeroRequest)
+func _FightSvc_KickGuildMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GuildMemberRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(FightSvcServer).SelectHero(ctx, in)
+		return srv.(FightSvcServer).KickGuildMember(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/fight.FightSvc/SelectHero",
+		FullMethod: "/fight.FightSvc/KickGuildMember",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(FightSvcServer).SelectHero(ctx, req.(*SelectHeroRequest))
+		return srv.(FightSvcServer).KickGuildMember(ctx, req.(*GuildMemberRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _FightSvc_Game_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GameRequest)
+func _FightSvc_SetGuildRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GuildMemberRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(FightSvcServer).Game(ctx, in)
+		return srv.(FightSvcServer).SetGuildRole(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/fight.FightSvc/Game",
+		FullMethod: "/fight.FightSvc/SetGuildRole",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(FightSvcServer).Game(ctx, req.(*GameRequest))
+		return srv.(FightSvcServer).SetGuildRole(ctx, req.(*GuildMemberRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _FightSvc_ClearSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ClearSessionRequest)
+func _FightSvc_GuildLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GuildLeaderboardRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(FightSvcServer).ClearSession(ctx, in)
+		return srv.(FightSvcServer).GuildLeaderboard(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/fight.FightSvc/ClearSession",
+		FullMethod: "/fight.FightSvc/GuildLeaderboard",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(FightSvcServer).ClearSession(ctx, req.(*ClearSessionRequest))
+		return srv.(FightSvcServer).GuildLeaderboard(ctx, req.(*GuildLeaderboardRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _FightSvc_Top10_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(Top10Request)
+func _FightSvc_WatchGuildLeaderboard_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GuildLeaderboardRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(FightSvcServer).Top10(m, &fightSvcTop10Server{stream})
-}
-
-type FightSvc_Top10Server interface {
-	Send(*Top10Response) error
-	grpc.ServerStream
-}
-
-type fightSvcTop10Server struct {
-	grpc.ServerStream
-}
-
-func (x *fightSvcTop10Server) Send(m *Top10Response) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func _FightSvc_Admin_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(FightSvcServer).Admin(&fightSvcAdminServer{stream})
+	return srv.(FightSvcServer).WatchGuildLeaderboard(m, &fightSvcWatchGuildLeaderboardServer{stream})
 }
 
-type FightSvc_AdminServer interface {
-	Send(*AdminResponse) error
-	Recv() (*AdminRequest, error)
+type FightSvc_WatchGuildLeaderboardServer interface {
+	Send(*GuildLeaderboardResponse) error
 	grpc.ServerStream
 }
 
-type fightSvcAdminServer struct {
+type fightSvcWatchGuildLeaderboardServer struct {
 	grpc.ServerStream
 }
 
-func (x *fightSvcAdminServer) Send(m *AdminResponse) error {
+func (x *fightSvcWatchGuildLeaderboardServer) Send(m *GuildLeaderboardResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func (x *fightSvcAdminServer) Recv() (*AdminRequest, error) {
-	m := new(AdminRequest)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
+func _FightSvc_ListAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditLogRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(FightSvcServer).ListAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fight.FightSvc/ListAuditLog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FightSvcServer).ListAuditLog(ctx, req.(*ListAuditLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
 var _FightSvc_serviceDesc = grpc.ServiceDesc{
@@ -1607,6 +5461,86 @@ var _FightSvc_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ClearSession",
 			Handler:    _FightSvc_ClearSession_Handler,
 		},
+		{
+			MethodName: "Leaderboard",
+			Handler:    _FightSvc_Leaderboard_Handler,
+		},
+		{
+			MethodName: "HeroLeaderboard",
+			Handler:    _FightSvc_HeroLeaderboard_Handler,
+		},
+		{
+			MethodName: "LevelLeaderboard",
+			Handler:    _FightSvc_LevelLeaderboard_Handler,
+		},
+		{
+			MethodName: "CreateProfile",
+			Handler:    _FightSvc_CreateProfile_Handler,
+		},
+		{
+			MethodName: "GetProfile",
+			Handler:    _FightSvc_GetProfile_Handler,
+		},
+		{
+			MethodName: "UpdateProfile",
+			Handler:    _FightSvc_UpdateProfile_Handler,
+		},
+		{
+			MethodName: "DeleteProfile",
+			Handler:    _FightSvc_DeleteProfile_Handler,
+		},
+		{
+			MethodName: "RequestFriend",
+			Handler:    _FightSvc_RequestFriend_Handler,
+		},
+		{
+			MethodName: "AcceptFriend",
+			Handler:    _FightSvc_AcceptFriend_Handler,
+		},
+		{
+			MethodName: "DeclineFriend",
+			Handler:    _FightSvc_DeclineFriend_Handler,
+		},
+		{
+			MethodName: "ListFriends",
+			Handler:    _FightSvc_ListFriends_Handler,
+		},
+		{
+			MethodName: "FriendsLeaderboard",
+			Handler:    _FightSvc_FriendsLeaderboard_Handler,
+		},
+		{
+			MethodName: "CreateGuild",
+			Handler:    _FightSvc_CreateGuild_Handler,
+		},
+		{
+			MethodName: "GetGuild",
+			Handler:    _FightSvc_GetGuild_Handler,
+		},
+		{
+			MethodName: "JoinGuild",
+			Handler:    _FightSvc_JoinGuild_Handler,
+		},
+		{
+			MethodName: "LeaveGuild",
+			Handler:    _FightSvc_LeaveGuild_Handler,
+		},
+		{
+			MethodName: "KickGuildMember",
+			Handler:    _FightSvc_KickGuildMember_Handler,
+		},
+		{
+			MethodName: "SetGuildRole",
+			Handler:    _FightSvc_SetGuildRole_Handler,
+		},
+		{
+			MethodName: "GuildLeaderboard",
+			Handler:    _FightSvc_GuildLeaderboard_Handler,
+		},
+		{
+			MethodName: "ListAuditLog",
+			Handler:    _FightSvc_ListAuditLog_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1619,12 +5553,33 @@ var _FightSvc_serviceDesc = grpc.ServiceDesc{
 			Handler:       _FightSvc_Top10_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchLeaderboard",
+			Handler:       _FightSvc_WatchLeaderboard_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchHeroLeaderboard",
+			Handler:       _FightSvc_WatchHeroLeaderboard_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchLevelLeaderboard",
+			Handler:       _FightSvc_WatchLevelLeaderboard_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "Admin",
 			Handler:       _FightSvc_Admin_Handler,
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "WatchGuildLeaderboard",
+			Handler:       _FightSvc_WatchGuildLeaderboard_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "pd/fight/fight.proto",
 }
@@ -0,0 +1,250 @@
+package authz
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// APIKeyMetadataKey is the metadata key of the api key of the request.
+const APIKeyMetadataKey = "x-api-key"
+
+// wildcard grants all the RPCs or all the admin operations.
+const wildcard = "*"
+
+// Role is the set of the permissions of a role in the policy file.
+type Role struct {
+	// the roles whose permissions are granted too
+	Inherits []string `json:"inherits,omitempty"`
+	// the names of the RPCs, e.g. "Game", or "*"
+	RPCs []string `json:"rpcs"`
+	// the types of the admin requests, e.g. "CREATE_HERO", or "*"
+	AdminOps []string `json:"admin_ops,omitempty"`
+	// clears any session without its session token
+	AnySession bool `json:"any_session,omitempty"`
+}
+
+// Key is an api key of the policy file, only its sha256 is kept.
+type Key struct {
+	Name   string `json:"name"`
+	Role   string `json:"role"`
+	SHA256 string `json:"sha256"`
+}
+
+// Policy maps the api keys to their roles, the requests without an api key
+// have the default role.
+type Policy struct {
+	DefaultRole string          `json:"default_role"`
+	Roles       map[string]Role `json:"roles"`
+	Keys        []Key           `json:"keys"`
+
+	// the permissions of each role, including the inherited ones
+	grants map[string]*grant
+	hashes map[string][]byte
+}
+
+type grant struct {
+	rpcs       map[string]bool
+	adminOps   map[string]bool
+	anySession bool
+}
+
+// Principal is the caller of a request.
+type Principal struct {
+	// the name of the api key, empty for the default role
+	Name string
+	Role string
+
+	grant *grant
+}
+
+// LoadPolicy loads the policy file.
+func LoadPolicy(file string) (*Policy, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var p Policy
+	if err = json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid policy file '%s': %v", file, err)
+	}
+	if err = p.compile(); err != nil {
+		return nil, fmt.Errorf("invalid policy file '%s': %v", file, err)
+	}
+	return &p, nil
+}
+
+// compile resolves the inherited roles and decodes the key hashes.
+func (p *Policy) compile() error {
+	p.grants = make(map[string]*grant, len(p.Roles))
+	for name := range p.Roles {
+		g := &grant{rpcs: make(map[string]bool), adminOps: make(map[string]bool)}
+		if err := p.collect(g, name, map[string]bool{}); err != nil {
+			return err
+		}
+		p.grants[name] = g
+	}
+	if _, ok := p.grants[p.DefaultRole]; !ok {
+		return fmt.Errorf("undefined default role: '%s'", p.DefaultRole)
+	}
+
+	p.hashes = make(map[string][]byte, len(p.Keys))
+	for _, key := range p.Keys {
+		if _, ok := p.hashes[key.Name]; ok {
+			return fmt.Errorf("duplicate key name: '%s'", key.Name)
+		}
+		if _, ok := p.grants[key.Role]; !ok {
+			return fmt.Errorf("undefined role of the key '%s': '%s'", key.Name, key.Role)
+		}
+		hash, err := hex.DecodeString(key.SHA256)
+		if err != nil || len(hash) != sha256.Size {
+			return fmt.Errorf("invalid sha256 of the key '%s'", key.Name)
+		}
+		p.hashes[key.Name] = hash
+	}
+	return nil
+}
+
+func (p *Policy) collect(g *grant, name string, seen map[string]bool) error {
+	role, ok := p.Roles[name]
+	if !ok {
+		return fmt.Errorf("undefined role: '%s'", name)
+	}
+	if seen[name] {
+		return fmt.Errorf("role '%s' inherits itself", name)
+	}
+	seen[name] = true
+
+	for _, rpc := range role.RPCs {
+		g.rpcs[rpc] = true
+	}
+	for _, op := range role.AdminOps {
+		g.adminOps[op] = true
+	}
+	g.anySession = g.anySession || role.AnySession
+	for _, parent := range role.Inherits {
+		if err := p.collect(g, parent, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Authenticate returns the principal of the api key, or of the default
+// role if the key is empty.
+func (p *Policy) Authenticate(apiKey string) (Principal, error) {
+	if apiKey == "" {
+		return Principal{Role: p.DefaultRole, grant: p.grants[p.DefaultRole]}, nil
+	}
+
+	hash := sha256.Sum256([]byte(apiKey))
+	for _, key := range p.Keys {
+		if subtle.ConstantTimeCompare(hash[:], p.hashes[key.Name]) == 1 {
+			return Principal{Name: key.Name, Role: key.Role, grant: p.grants[key.Role]}, nil
+		}
+	}
+	return Principal{}, status.Error(codes.Unauthenticated, "invalid api key")
+}
+
+// CanCall reports whether the principal calls the RPC.
+func (pr Principal) CanCall(rpc string) bool {
+	return pr.grant != nil && (pr.grant.rpcs[wildcard] || pr.grant.rpcs[rpc])
+}
+
+// CanApply reports whether the principal applies the admin operation.
+func (pr Principal) CanApply(op string) bool {
+	return pr.grant != nil && (pr.grant.adminOps[wildcard] || pr.grant.adminOps[op])
+}
+
+// AnySession reports whether the principal clears any session without its
+// session token.
+func (pr Principal) AnySession() bool {
+	return pr.grant != nil && pr.grant.anySession
+}
+
+type principalKey struct{}
+
+// NewContext returns the context carrying the principal.
+func NewContext(ctx context.Context, pr Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, pr)
+}
+
+// FromContext returns the principal of the request, if the requests are
+// authorized.
+func FromContext(ctx context.Context) (Principal, bool) {
+	pr, ok := ctx.Value(principalKey{}).(Principal)
+	return pr, ok
+}
+
+// AuthorizeAdminOp returns PermissionDenied if the principal of the
+// request does not apply the admin operation. Every operation is allowed
+// if the requests are not authorized.
+func AuthorizeAdminOp(ctx context.Context, op string) error {
+	pr, ok := FromContext(ctx)
+	if !ok || pr.CanApply(op) {
+		return nil
+	}
+	return status.Errorf(codes.PermissionDenied, "role '%s' can not apply '%s'", pr.Role, op)
+}
+
+// authorize returns the context of the request carrying its principal, or
+// the error refusing the request.
+func (p *Policy) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	var apiKey string
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(APIKeyMetadataKey); len(values) > 0 {
+		apiKey = values[0]
+	}
+
+	pr, err := p.Authenticate(apiKey)
+	if err != nil {
+		return nil, err
+	}
+	rpc := path.Base(fullMethod)
+	if !pr.CanCall(rpc) {
+		return nil, status.Errorf(codes.PermissionDenied, "role '%s' can not call '%s'", pr.Role, rpc)
+	}
+	return NewContext(ctx, pr), nil
+}
+
+// UnaryServerInterceptor authorizes the unary requests.
+func (p *Policy) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := p.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor authorizes the streaming requests.
+func (p *Policy) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := p.authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// serverStream overrides the context of the stream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ss *serverStream) Context() context.Context {
+	return ss.ctx
+}
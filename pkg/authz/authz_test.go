@@ -0,0 +1,161 @@
+package authz
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func newTestPolicy(t *testing.T) *Policy {
+	hash := sha256.Sum256([]byte("secret"))
+	p := &Policy{
+		DefaultRole: "player",
+		Roles: map[string]Role{
+			"player":     {RPCs: []string{"Game", "ClearSession"}},
+			"moderator":  {Inherits: []string{"player"}, RPCs: []string{"Admin"}, AdminOps: []string{"SUBSCRIBE"}, AnySession: true},
+			"game-admin": {Inherits: []string{"moderator"}, RPCs: []string{"*"}, AdminOps: []string{"*"}},
+		},
+		Keys: []Key{{Name: "mod", Role: "moderator", SHA256: hex.EncodeToString(hash[:])}},
+	}
+	if err := p.compile(); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestLoadPolicy(t *testing.T) {
+	p, err := LoadPolicy("../../config/policy.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr, _ := p.Authenticate("")
+	if !pr.CanCall("Game") || pr.CanCall("Admin") {
+		t.Errorf("want the default role calling Game but not Admin, but get role '%s'", pr.Role)
+	}
+}
+
+func TestCompile(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy Policy
+	}{
+		{"undefined default role", Policy{DefaultRole: "guest", Roles: map[string]Role{"player": {}}}},
+		{"undefined inherited role", Policy{DefaultRole: "player", Roles: map[string]Role{"player": {Inherits: []string{"guest"}}}}},
+		{"inheritance cycle", Policy{DefaultRole: "a", Roles: map[string]Role{"a": {Inherits: []string{"b"}}, "b": {Inherits: []string{"a"}}}}},
+		{"undefined key role", Policy{DefaultRole: "player", Roles: map[string]Role{"player": {}}, Keys: []Key{{Name: "k", Role: "admin"}}}},
+		{"duplicate key name", Policy{DefaultRole: "player", Roles: map[string]Role{"player": {}}, Keys: []Key{
+			{Name: "k", Role: "player", SHA256: strings.Repeat("a", 64)},
+			{Name: "k", Role: "player", SHA256: strings.Repeat("b", 64)},
+		}}},
+		{"invalid key hash", Policy{DefaultRole: "player", Roles: map[string]Role{"player": {}}, Keys: []Key{{Name: "k", Role: "player", SHA256: "abc"}}}},
+	}
+	for _, c := range cases {
+		if err := c.policy.compile(); err == nil {
+			t.Errorf("%s: want an error, but get nil", c.name)
+		}
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	p := newTestPolicy(t)
+
+	pr, err := p.Authenticate("")
+	if err != nil || pr.Role != "player" || pr.Name != "" {
+		t.Errorf("want the default role, but get '%v', '%v'", pr, err)
+	}
+	pr, err = p.Authenticate("secret")
+	if err != nil || pr.Role != "moderator" || pr.Name != "mod" {
+		t.Errorf("want the key 'mod' of role moderator, but get '%v', '%v'", pr, err)
+	}
+	if _, err = p.Authenticate("guess"); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("want Unauthenticated, but get '%v'", err)
+	}
+}
+
+func TestGrants(t *testing.T) {
+	p := newTestPolicy(t)
+	player := Principal{Role: "player", grant: p.grants["player"]}
+	moderator := Principal{Role: "moderator", grant: p.grants["moderator"]}
+	admin := Principal{Role: "game-admin", grant: p.grants["game-admin"]}
+
+	cases := []struct {
+		pr   Principal
+		rpc  string
+		want bool
+	}{
+		{player, "Game", true},
+		{player, "Admin", false},
+		{moderator, "Game", true},
+		{moderator, "Admin", true},
+		{moderator, "ListHeros", false},
+		{admin, "ListHeros", true},
+		{Principal{}, "Game", false},
+	}
+	for _, c := range cases {
+		if got := c.pr.CanCall(c.rpc); got != c.want {
+			t.Errorf("role '%s' calling '%s': want %v, but get %v", c.pr.Role, c.rpc, c.want, got)
+		}
+	}
+
+	if !moderator.CanApply("SUBSCRIBE") || moderator.CanApply("CREATE_HERO") || !admin.CanApply("CREATE_HERO") {
+		t.Errorf("want the moderator applying SUBSCRIBE only and the game-admin everything")
+	}
+	if player.AnySession() || !moderator.AnySession() || !admin.AnySession() {
+		t.Errorf("want any_session inherited by the game-admin but not granted to the player")
+	}
+}
+
+func TestAuthorizeAdminOp(t *testing.T) {
+	p := newTestPolicy(t)
+	ctx := NewContext(context.Background(), Principal{Role: "moderator", grant: p.grants["moderator"]})
+
+	if err := AuthorizeAdminOp(ctx, "SUBSCRIBE"); err != nil {
+		t.Errorf("want nil, but get '%v'", err)
+	}
+	if err := AuthorizeAdminOp(ctx, "DELETE_HERO"); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("want PermissionDenied, but get '%v'", err)
+	}
+	if err := AuthorizeAdminOp(context.Background(), "DELETE_HERO"); err != nil {
+		t.Errorf("want nil without authorization, but get '%v'", err)
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	p := newTestPolicy(t)
+	interceptor := p.UnaryServerInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		pr, ok := FromContext(ctx)
+		if !ok {
+			t.Error("want the principal in the context")
+		}
+		return pr.Role, nil
+	}
+
+	cases := []struct {
+		apiKey string
+		method string
+		code   codes.Code
+	}{
+		{"", "/fight.Fight/Game", codes.OK},
+		{"", "/fight.Fight/ListHeros", codes.PermissionDenied},
+		{"secret", "/fight.Fight/ClearSession", codes.OK},
+		{"guess", "/fight.Fight/Game", codes.Unauthenticated},
+	}
+	for _, c := range cases {
+		ctx := context.Background()
+		if c.apiKey != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(APIKeyMetadataKey, c.apiKey))
+		}
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: c.method}, handler)
+		if status.Code(err) != c.code {
+			t.Errorf("'%s' with key '%s': want %v, but get '%v'", c.method, c.apiKey, c.code, err)
+		}
+	}
+}
@@ -24,7 +24,14 @@ func Create(filename string) (*sql.DB, *pq.Listener, error) {
 		config.DBName,
 	)
 
-	listener := pq.NewListener(pgConn, 5*time.Second, 20*time.Second, nil)
+	listener := pq.NewListener(pgConn, 5*time.Second, 20*time.Second, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventDisconnected, pq.ListenerEventConnectionAttemptFailed:
+			klog.Warningf("events listener disconnected: %v", err)
+		case pq.ListenerEventReconnected:
+			klog.Info("events listener reconnected")
+		}
+	})
 
 	db, err := sql.Open("postgres", pgConn)
 	if err != nil {
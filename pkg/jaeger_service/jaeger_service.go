@@ -22,6 +22,12 @@ func ServerOption(tracer opentracing.Tracer) grpc.ServerOption {
 	return grpc.UnaryInterceptor(serverInterceptor(tracer))
 }
 
+// UnaryServerInterceptor traces the unary requests, to be chained with the
+// other interceptors
+func UnaryServerInterceptor(tracer opentracing.Tracer) grpc.UnaryServerInterceptor {
+	return serverInterceptor(tracer)
+}
+
 var Tracer opentracing.Tracer
 
 func NewJaegerTracer(serviceName string, jaegerHostPort string) (opentracing.Tracer, io.Closer, error) {
@@ -32,6 +32,10 @@ type Session struct {
 	CurrentLevel  int
 	Score         int
 	ArchiveDate   time.Time
+	// the time the session started fighting the boss of the current level
+	LevelStartedAt time.Time
+	// the time the score was reached
+	ScoreUpdatedAt time.Time
 }
 
 // SessionView ...
@@ -39,4 +43,25 @@ type SessionView struct {
 	Session
 	Boss
 	Hero
+	// the sequence number of the last event of the session
+	Seq int64
+}
+
+// Profile ...
+type Profile struct {
+	UID         string
+	DisplayName string
+	AvatarKey   string
+	CreatedAt   time.Time
+}
+
+// Guild ...
+type Guild struct {
+	ID        int64
+	Name      string
+	Score     int
+	Members   int
+	CreatedAt time.Time
+	// 1-based, set by the guild leaderboard only
+	Rank int
 }
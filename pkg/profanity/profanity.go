@@ -0,0 +1,100 @@
+package profanity
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// words are matched against the whole words of the normalized text, so
+// that the names containing one of them, e.g. 'Arsenal' or 'Hancock', are
+// allowed. The usual forms of the words are listed as well.
+var words = toSet(
+	"arse", "arsehole",
+	"asshole", "assholes",
+	"bastard", "bastards",
+	"bitch", "bitches", "bitchy",
+	"bollock", "bollocks",
+	"bullshit",
+	"cock", "cocks", "cocksucker",
+	"cunt", "cunts",
+	"dick", "dickhead", "dicks",
+	"fag", "faggot", "fags",
+	"fuck", "fucked", "fucker", "fuckers", "fuckface", "fucking", "fucks",
+	"motherfucker", "motherfucking",
+	"nazi", "nazis",
+	"nigga", "niggas", "nigger", "niggers",
+	"penis",
+	"piss", "pissed",
+	"porn", "porno",
+	"pussy",
+	"rape", "raped", "rapist",
+	"retard", "retarded", "retards",
+	"shit", "shithead", "shits", "shitty",
+	"slut", "sluts",
+	"twat",
+	"vagina",
+	"wank", "wanker",
+	"whore", "whores",
+)
+
+func toSet(words ...string) map[string]struct{} {
+	set := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		set[word] = struct{}{}
+	}
+	return set
+}
+
+// the look-alike characters
+var replacer = strings.NewReplacer(
+	"0", "o",
+	"1", "i",
+	"!", "i",
+	"3", "e",
+	"4", "a",
+	"@", "a",
+	"5", "s",
+	"$", "s",
+	"7", "t",
+	"+", "t",
+)
+
+// tokenize lowers the text, replaces the look-alike characters and splits
+// it into the words of letters.
+func tokenize(text string) []string {
+	text = replacer.Replace(strings.ToLower(text))
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+}
+
+// Contains reports whether the text contains a profane word. Besides the
+// words of the text, the pairs of adjacent words and the runs of single
+// letters are matched, so that 'ass hole' and 'F.u_c k' are matched as well.
+func Contains(text string) bool {
+	var (
+		tokens  = tokenize(text)
+		letters string
+	)
+	for i, token := range tokens {
+		if _, ok := words[token]; ok {
+			return true
+		}
+		if i > 0 {
+			if _, ok := words[tokens[i-1]+token]; ok {
+				return true
+			}
+		}
+
+		if utf8.RuneCountInString(token) > 1 {
+			letters = ""
+			continue
+		}
+		letters += token
+		if _, ok := words[letters]; ok {
+			return true
+		}
+	}
+	return false
+}
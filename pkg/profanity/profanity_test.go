@@ -0,0 +1,36 @@
+package profanity
+
+import "testing"
+
+func TestContains(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"PostgreSql", false},
+		{"Scunthorpe United", false},
+		{"the_therapist", false},
+		{"Peacock", false},
+		{"shit", true},
+		{"SHIT", true},
+		{"sh1t_lord", true},
+		{"F.u_c k", true},
+		{"@ss hole", true},
+		{"Classic", false},
+		{"a$$hole", true},
+		{"b!tch", true},
+		{"Arsenal", false},
+		{"Hancock", false},
+		{"Matsushita", false},
+		{"Dickens", false},
+		{"cockpit", false},
+		{"mother fucker", true},
+		{"big_dick", true},
+		{"Shits-n-Giggles", true},
+	}
+	for _, test := range tests {
+		if got := Contains(test.text); got != test.want {
+			t.Errorf("'%s': want %v, but get %v", test.text, test.want, got)
+		}
+	}
+}
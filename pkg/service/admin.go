@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/authz"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog"
+)
+
+// adminOp applies the i-th hero or boss of an admin request. It returns the
+// change of the live sessions, if any, to apply once the transaction is
+// committed.
+type adminOp func(ctx context.Context, q dbtx, i int) (func() error, error)
+
+// Admin ...
+func (s *Service) Admin(stream fight.FightSvc_AdminServer) error {
+	var (
+		ctx, cancel = context.WithCancel(stream.Context())
+		lock        sync.Mutex
+		wg          sync.WaitGroup
+	)
+	// the events goroutine ends with the subscription, before the stream
+	defer wg.Wait()
+	defer cancel()
+
+	// the events and the results are sent from two goroutines
+	send := func(resp *fight.AdminResponse) error {
+		lock.Lock()
+		defer lock.Unlock()
+		return stream.Send(resp)
+	}
+
+	sub := s.dispatcher.Subscribe(ctx, EventFilter{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for e := range sub.C {
+			// the buffered events of an ended stream are dropped
+			if ctx.Err() != nil {
+				continue
+			}
+			resp, err := convertEvent2AdminResponse(e)
+			if err != nil {
+				klog.Warning(err)
+				continue
+			}
+			if err = send(resp); err != nil {
+				klog.Warning(err)
+			}
+		}
+	}()
+
+	// a failed request is reported in its result, the stream stays open
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		var (
+			result = &fight.AdminResult{RequestId: req.GetRequestId()}
+			change = newAuditChange(ctx, req)
+		)
+		applied, err := s.admin(ctx, req, sub, result, change)
+		if err != nil {
+			klog.Warningf("admin request '%s' failed: %v", req.GetRequestId(), err)
+		}
+
+		// the changes are audited in their transactions, the failures and
+		// the requests without a transaction are audited on their own
+		if err != nil || !change.committed {
+			err = s.audit(change.failure(err), err)
+		}
+
+		if err = send(&fight.AdminResponse{Result: adminResult(result, applied, err)}); err != nil {
+			return err
+		}
+	}
+}
+
+// admin applies the admin request of the stream subscribed by sub, and
+// returns the number of the heros or bosses applied. The diff of a balance
+// patch is set in the result, and the changed rows in change.
+func (s *Service) admin(ctx context.Context, req *fight.AdminRequest, sub *Subscription, result *fight.AdminResult, change *auditChange) (int, error) {
+	if err := authz.AuthorizeAdminOp(ctx, req.GetType().String()); err != nil {
+		return 0, err
+	}
+
+	var (
+		heros  = req.GetHeros()
+		bosses = req.GetBosses()
+	)
+	heroName := func(i int) string { return heros[i].GetName() }
+	bossName := func(i int) string { return bosses[i].GetName() }
+	switch req.GetType() {
+	case fight.AdminRequest_CREATE_HERO:
+		return s.applyAdmin(ctx, len(heros), req.GetAtomic(), change, change.track("hero", heroName, func(ctx context.Context, q dbtx, i int) (func() error, error) {
+			return nil, createHero(ctx, q, heros[i])
+		}))
+
+	case fight.AdminRequest_UPDATE_HERO:
+		return s.applyAdmin(ctx, len(heros), req.GetAtomic(), change, change.track("hero", heroName, func(ctx context.Context, q dbtx, i int) (func() error, error) {
+			return nil, updateHero(ctx, q, heros[i])
+		}))
+
+	case fight.AdminRequest_UPSERT_HERO:
+		return s.applyAdmin(ctx, len(heros), req.GetAtomic(), change, change.track("hero", heroName, func(ctx context.Context, q dbtx, i int) (func() error, error) {
+			return nil, upsertHero(ctx, q, heros[i])
+		}))
+
+	case fight.AdminRequest_DELETE_HERO:
+		return s.applyAdmin(ctx, len(heros), req.GetAtomic(), change, change.track("hero", heroName, func(ctx context.Context, q dbtx, i int) (func() error, error) {
+			return s.deleteHero(ctx, q, heros[i].GetName(), req.GetMigrateTo())
+		}))
+
+	case fight.AdminRequest_ADJUST_HERO:
+		return s.balancePatch(ctx, adjustHeroPatch, result, change)
+
+	case fight.AdminRequest_BALANCE_PATCH:
+		return s.balancePatch(ctx, req.GetPatch(), result, change)
+
+	case fight.AdminRequest_REVERT_BALANCE_PATCH:
+		var diff []heroDiff
+		_, err := s.applyAdmin(ctx, 1, true, change, func(ctx context.Context, q dbtx, i int) (func() error, error) {
+			var err error
+			diff, err = revertBalancePatch(ctx, q, req.GetPatchId())
+			change.trackDiff(diff)
+			return nil, err
+		})
+		if err != nil {
+			return 0, err
+		}
+		result.Diff = convertHeroDiffs(diff)
+		return len(diff), nil
+
+	case fight.AdminRequest_CREATE_BOSS:
+		return s.applyAdmin(ctx, len(bosses), req.GetAtomic(), change, change.track("boss", bossName, func(ctx context.Context, q dbtx, i int) (func() error, error) {
+			return s.records.publishLevels, createBoss(ctx, q, bosses[i])
+		}))
+
+	case fight.AdminRequest_UPDATE_BOSS:
+		return s.applyAdmin(ctx, len(bosses), req.GetAtomic(), change, change.track("boss", bossName, func(ctx context.Context, q dbtx, i int) (func() error, error) {
+			return nil, updateBoss(ctx, q, bosses[i])
+		}))
+
+	case fight.AdminRequest_DELETE_BOSS:
+		return s.applyAdmin(ctx, len(bosses), req.GetAtomic(), change, change.track("boss", bossName, func(ctx context.Context, q dbtx, i int) (func() error, error) {
+			return s.records.publishLevels, s.deleteBoss(ctx, q, bosses[i].GetName())
+		}))
+
+	case fight.AdminRequest_REORDER_BOSSES:
+		names := make([]string, len(bosses))
+		for i, boss := range bosses {
+			names[i] = boss.GetName()
+		}
+		// a reorder is a single change of all the bosses
+		applied, err := s.applyAdmin(ctx, 1, true, change, func(ctx context.Context, q dbtx, i int) (func() error, error) {
+			return s.records.publishLevels, change.trackAll(ctx, q, "boss", func() error {
+				return reorderBosses(ctx, q, names)
+			})
+		})
+		return applied * len(bosses), err
+
+	case fight.AdminRequest_SNAPSHOT_SESSIONS:
+		return 0, s.Snapshot()
+
+	case fight.AdminRequest_REPLAY_SESSION:
+		sv, err := s.replaySession(ctx, req.GetSessionId(), req.GetUntilSeq())
+		if err != nil {
+			return 0, err
+		}
+		result.Session = convertSV2FightSV(sv)
+		return 1, nil
+
+	case fight.AdminRequest_SUBSCRIBE:
+		filter, err := NewEventFilter(req.GetTables(), req.GetActions())
+		if err != nil {
+			return 0, err
+		}
+		s.dispatcher.SetFilter(sub, filter)
+		return 0, nil
+
+	default:
+		return 0, status.Errorf(codes.InvalidArgument, "undefined admin request type: '%v'", req.GetType())
+	}
+}
+
+// balancePatch applies the balance patch, or previews it, in one
+// transaction.
+func (s *Service) balancePatch(ctx context.Context, patch *fight.BalancePatch, result *fight.AdminResult, change *auditChange) (int, error) {
+	var (
+		diff []heroDiff
+		id   int64
+	)
+	_, err := s.applyAdmin(ctx, 1, true, change, func(ctx context.Context, q dbtx, i int) (func() error, error) {
+		var err error
+		if diff, id, err = applyBalancePatch(ctx, q, patch); err != nil || patch.GetDryRun() {
+			return nil, err
+		}
+		change.trackPatch(id, diff)
+		return nil, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	result.Diff, result.PatchId = convertHeroDiffs(diff), id
+	if patch.GetDryRun() {
+		return 0, nil
+	}
+	return len(diff), nil
+}
+
+// applyAdmin applies the n heros or bosses of a request: all of them in one
+// transaction if it is atomic, otherwise each one in its own transaction
+// until one fails. Every transaction appends the audit entry of its change.
+// It returns the number of the heros or bosses applied.
+func (s *Service) applyAdmin(ctx context.Context, n int, atomic bool, change *auditChange, op adminOp) (int, error) {
+	if atomic {
+		if err := s.applyAdminTx(ctx, 0, n, change, op); err != nil {
+			return 0, err
+		}
+		return n, nil
+	}
+
+	for i := 0; i < n; i++ {
+		if err := s.applyAdminTx(ctx, i, i+1, change, op); err != nil {
+			return i, err
+		}
+	}
+	return n, nil
+}
+
+// applyAdminTx applies the heros or bosses from the index from to the index
+// to in one transaction with their audit entry, then the changes of the
+// live sessions.
+func (s *Service) applyAdminTx(ctx context.Context, from, to int, change *auditChange, op adminOp) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	change.begin()
+
+	var committed []func() error
+	for i := from; i < to; i++ {
+		f, err := op(ctx, tx, i)
+		if err != nil {
+			return err
+		}
+		if f != nil {
+			committed = append(committed, f)
+		}
+	}
+	if err = change.append(ctx, s.auditLog, tx); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	change.commit()
+
+	// the db is changed already, the failures are only logged
+	for _, f := range committed {
+		if err = f(); err != nil {
+			klog.Warning(err)
+		}
+	}
+	return nil
+}
+
+// adminResult completes the result of an admin request.
+func adminResult(result *fight.AdminResult, applied int, err error) *fight.AdminResult {
+	result.Code = int32(status.Code(err))
+	result.Applied = int32(applied)
+	if err != nil {
+		result.Error = status.Convert(err).Message()
+	}
+	return result
+}
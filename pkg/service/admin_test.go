@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdminResult(t *testing.T) {
+	result := adminResult(&fight.AdminResult{RequestId: "1"}, 2, nil)
+	if result.RequestId != "1" || result.Code != int32(codes.OK) || result.Error != "" || result.Applied != 2 {
+		t.Errorf("want an OK result of 2 applied, but get: '%v'", result)
+	}
+
+	result = adminResult(&fight.AdminResult{RequestId: "2"}, 1, status.Error(codes.NotFound, "undefined hero: 'Oracle'"))
+	if result.RequestId != "2" || result.Code != int32(codes.NotFound) || result.Error != "undefined hero: 'Oracle'" || result.Applied != 1 {
+		t.Errorf("want a NotFound result of 1 applied, but get: '%v'", result)
+	}
+}
+
+func TestAdminFailures(t *testing.T) {
+	s := &Service{sessions: NewMemoryStore()}
+
+	if _, err := s.admin(context.Background(), &fight.AdminRequest{Type: fight.AdminRequest_Type(100)}, nil, &fight.AdminResult{}, nil); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("want InvalidArgument for an undefined type, but get '%v'", err)
+	}
+	if _, err := s.admin(context.Background(), &fight.AdminRequest{Type: fight.AdminRequest_SNAPSHOT_SESSIONS}, nil, &fight.AdminResult{}, nil); err != ErrSnapshotDisabled {
+		t.Errorf("want '%v', but get '%v'", ErrSnapshotDisabled, err)
+	}
+}
+
+type adminStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	recv chan error
+
+	lock     sync.Mutex
+	returned bool
+	sent     int
+	late     int
+}
+
+func (s *adminStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *adminStream) Recv() (*fight.AdminRequest, error) {
+	return nil, <-s.recv
+}
+
+func (s *adminStream) Send(*fight.AdminResponse) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.sent++
+	if s.returned {
+		s.late++
+	}
+	return nil
+}
+
+func TestAdminJoinsEvents(t *testing.T) {
+	d, _ := newTestDispatcher()
+	s := &Service{dispatcher: d, sessions: NewMemoryStore()}
+	stream := &adminStream{ctx: context.Background(), recv: make(chan error)}
+	e := Event{Table: "hero", Action: "INSERT", New: json.RawMessage(`{"name": "Redis"}`)}
+
+	done := make(chan error)
+	go func() { done <- s.Admin(stream) }()
+	for sent := 0; sent == 0; {
+		d.dispatch(e)
+		stream.lock.Lock()
+		sent = stream.sent
+		stream.lock.Unlock()
+	}
+	for i := 0; i < 100; i++ {
+		d.dispatch(e)
+	}
+
+	stream.recv <- io.EOF
+	if err := <-done; err != io.EOF {
+		t.Errorf("want EOF, but get '%v'", err)
+	}
+	stream.lock.Lock()
+	stream.returned = true
+	stream.lock.Unlock()
+
+	d.dispatch(e)
+	time.Sleep(50 * time.Millisecond)
+	stream.lock.Lock()
+	defer stream.lock.Unlock()
+	if stream.late != 0 {
+		t.Errorf("want no event sent after Admin returns, but get %d", stream.late)
+	}
+}
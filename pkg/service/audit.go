@@ -0,0 +1,553 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/authz"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+	"github.com/opentracing/opentracing-go"
+	"github.com/uber/jaeger-client-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"k8s.io/klog"
+)
+
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 500
+)
+
+// traceIDMetadataKey is the b3 header of the trace id, set by the mesh on
+// the requests which are not traced by the server, e.g. the streams.
+const traceIDMetadataKey = "x-b3-traceid"
+
+// AuditEntry is a row of the audit_log table.
+type AuditEntry struct {
+	ID        int64
+	CreatedAt time.Time
+	Actor     string
+	Role      string
+	Operation string
+	// json of the request, and of the rows before and after it
+	Arguments json.RawMessage
+	Before    json.RawMessage
+	After     json.RawMessage
+	Code      codes.Code
+	Error     string
+	TraceID   string
+}
+
+// AuditFilter selects the entries of the audit log, an empty field matches
+// all of them.
+type AuditFilter struct {
+	Actor     string
+	Operation string
+	TraceID   string
+	Since     time.Time
+	Until     time.Time
+	Offset    int
+	Limit     int
+}
+
+// AuditLog appends the admin requests and the ClearSession calls to the
+// audit_log table, which refuses the updates and the deletes. A nil
+// *AuditLog does not record anything.
+type AuditLog struct {
+	db *sql.DB
+}
+
+// NewAuditLog ...
+func NewAuditLog(db *sql.DB) *AuditLog {
+	return &AuditLog{db: db}
+}
+
+// Append appends the entry with q, the db or the transaction of the
+// audited change, its id and creation time are set by the db.
+func (al *AuditLog) Append(ctx context.Context, q dbtx, entry AuditEntry) error {
+	if al == nil {
+		return nil
+	}
+	sqlStatement := `INSERT INTO audit_log(actor, role, operation, arguments, before, after, code, error, traceid) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9);`
+	_, err := q.ExecContext(ctx, sqlStatement, entry.Actor, entry.Role, entry.Operation,
+		nullJSON(entry.Arguments), nullJSON(entry.Before), nullJSON(entry.After), int(entry.Code), entry.Error, entry.TraceID)
+	return err
+}
+
+// List returns the page of the entries selected by the filter, the latest
+// first, and the number of the selected entries.
+func (al *AuditLog) List(ctx context.Context, filter AuditFilter) ([]AuditEntry, int, error) {
+	if al == nil {
+		return nil, 0, nil
+	}
+	where, args := filter.where()
+
+	var total int
+	if err := al.db.QueryRowContext(ctx, "SELECT count(*) FROM audit_log"+where+";", args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sqlStatement := fmt.Sprintf(`SELECT id, createdat, actor, role, operation, arguments, before, after, code, error, traceid FROM audit_log%s ORDER BY id DESC LIMIT $%d OFFSET $%d;`, where, len(args)+1, len(args)+2)
+	rows, err := al.db.QueryContext(ctx, sqlStatement, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var entries []AuditEntry
+	for rows.Next() {
+		var (
+			entry                    AuditEntry
+			arguments, before, after []byte
+			code                     int
+		)
+		err = rows.Scan(&entry.ID, &entry.CreatedAt, &entry.Actor, &entry.Role, &entry.Operation,
+			&arguments, &before, &after, &code, &entry.Error, &entry.TraceID)
+		if err != nil {
+			return nil, 0, err
+		}
+		entry.Arguments, entry.Before, entry.After, entry.Code = arguments, before, after, codes.Code(code)
+		entries = append(entries, entry)
+	}
+	return entries, total, rows.Err()
+}
+
+// where returns the where clause of the filter and its arguments.
+func (f AuditFilter) where() (string, []interface{}) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if f.Actor != "" {
+		add("actor = $%d", f.Actor)
+	}
+	if f.Operation != "" {
+		add("operation = $%d", f.Operation)
+	}
+	if f.TraceID != "" {
+		add("traceid = $%d", f.TraceID)
+	}
+	if !f.Since.IsZero() {
+		add("createdat >= $%d", f.Since)
+	}
+	if !f.Until.IsZero() {
+		add("createdat < $%d", f.Until)
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func nullJSON(data json.RawMessage) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return []byte(data)
+}
+
+// auditChange keeps the entry of an admin request, appended in every
+// transaction of the request with the rows of the heros or bosses applied
+// by the transaction, before and after they are applied, in the order they
+// are applied.
+type auditChange struct {
+	entry AuditEntry
+	args  adminArguments
+
+	before []json.RawMessage
+	after  []json.RawMessage
+	// whether a transaction committed with the entry
+	committed bool
+}
+
+func newAuditChange(ctx context.Context, req *fight.AdminRequest) *auditChange {
+	return &auditChange{
+		entry: newAuditEntry(ctx, req.GetType().String(), nil),
+		args:  newAdminArguments(req),
+	}
+}
+
+// begin drops the rows of the previous transaction, they are audited or
+// rolled back.
+func (c *auditChange) begin() {
+	if c != nil {
+		c.before, c.after = nil, nil
+	}
+}
+
+// commit marks the entry committed with a transaction.
+func (c *auditChange) commit() {
+	if c != nil {
+		c.committed = true
+	}
+}
+
+// append appends the entry with the rows of the transaction q, it is
+// committed with the transaction or not at all.
+func (c *auditChange) append(ctx context.Context, al *AuditLog, q dbtx) error {
+	if c == nil {
+		return nil
+	}
+	entry := c.entry
+	entry.Arguments = marshalAudit(c.args)
+	entry.Before, entry.After = c.values()
+	if err := al.Append(ctx, q, entry); err != nil {
+		return status.Errorf(codes.Internal, "failed to audit '%s': %v", entry.Operation, err)
+	}
+	return nil
+}
+
+// failure returns the entry of the request failed with err, or done without
+// a transaction.
+func (c *auditChange) failure(err error) AuditEntry {
+	entry := c.entry
+	entry.Arguments = marshalAudit(c.args)
+	return entryOf(entry, err)
+}
+
+// track wraps the op applying the heros or bosses of the table, so that the
+// row of the i-th name is kept before and after the op, null if there is
+// none.
+func (c *auditChange) track(table string, name func(i int) string, op adminOp) adminOp {
+	if c == nil {
+		return op
+	}
+	row := func(ctx context.Context, q dbtx, i int) (json.RawMessage, error) {
+		rows, err := auditRows(ctx, q, table, []string{name(i)})
+		if err != nil || len(rows) == 0 {
+			return nil, err
+		}
+		return rows[0], nil
+	}
+
+	return func(ctx context.Context, q dbtx, i int) (func() error, error) {
+		before, err := row(ctx, q, i)
+		if err != nil {
+			return nil, err
+		}
+		f, err := op(ctx, q, i)
+		if err != nil {
+			return nil, err
+		}
+		after, err := row(ctx, q, i)
+		if err != nil {
+			return nil, err
+		}
+		c.before, c.after = append(c.before, before), append(c.after, after)
+		return f, nil
+	}
+}
+
+// trackAll runs the op changing the rows of the table, and keeps all of
+// them before and after the op.
+func (c *auditChange) trackAll(ctx context.Context, q dbtx, table string, op func() error) error {
+	if c == nil {
+		return op()
+	}
+	before, err := auditRows(ctx, q, table, nil)
+	if err != nil {
+		return err
+	}
+	if err = op(); err != nil {
+		return err
+	}
+	after, err := auditRows(ctx, q, table, nil)
+	if err != nil {
+		return err
+	}
+	c.before, c.after = before, after
+	return nil
+}
+
+// trackDiff keeps the rows of the heros changed by a balance patch.
+func (c *auditChange) trackDiff(diff []heroDiff) {
+	if c == nil {
+		return
+	}
+	c.before, c.after = nil, nil
+	for _, d := range diff {
+		c.before = append(c.before, marshalAudit(newHeroRow(d.Before)))
+		c.after = append(c.after, marshalAudit(newHeroRow(d.After)))
+	}
+}
+
+// trackPatch keeps the id and the rows of the heros changed by a balance
+// patch.
+func (c *auditChange) trackPatch(id int64, diff []heroDiff) {
+	if c == nil {
+		return
+	}
+	c.args.PatchID = id
+	c.trackDiff(diff)
+}
+
+// heroRow is the json form of a row of the hero table, as row_to_json
+// returns it.
+type heroRow struct {
+	Name         string `json:"name"`
+	Detail       string `json:"detail"`
+	AttackPower  int    `json:"attackpower"`
+	DefensePower int    `json:"defensepower"`
+	Blood        int    `json:"blood"`
+}
+
+func newHeroRow(hero module.Hero) heroRow {
+	return heroRow{
+		Name:         hero.Name,
+		Detail:       hero.Detail,
+		AttackPower:  hero.AttackPower,
+		DefensePower: hero.DefensePower,
+		Blood:        hero.Blood,
+	}
+}
+
+// values returns the json of the rows of the heros or bosses applied in
+// the transaction.
+func (c *auditChange) values() (json.RawMessage, json.RawMessage) {
+	if c == nil || len(c.before) == 0 {
+		return nil, nil
+	}
+	return marshalAudit(c.before), marshalAudit(c.after)
+}
+
+var auditStatements = map[string]string{
+	"hero": "SELECT row_to_json(t) FROM hero t%s ORDER BY t.name;",
+	"boss": "SELECT row_to_json(t) FROM boss t%s ORDER BY t.level;",
+}
+
+// auditRows returns the json of the rows of the names, or of all the rows
+// if there is no name.
+func auditRows(ctx context.Context, q dbtx, table string, names []string) ([]json.RawMessage, error) {
+	var (
+		where string
+		args  []interface{}
+	)
+	if len(names) > 0 {
+		where, args = " WHERE t.name = ANY($1)", []interface{}{pq.Array(names)}
+	}
+	rows, err := q.QueryContext(ctx, fmt.Sprintf(auditStatements[table], where), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []json.RawMessage
+	for rows.Next() {
+		var row []byte
+		if err = rows.Scan(&row); err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// auditSession returns the json of the live session, or of the row of the
+// archived session, null if there is none.
+func (s *Service) auditSession(ctx context.Context, id string) (json.RawMessage, error) {
+	sv, err := s.sessions.Get(id)
+	if err == nil {
+		return marshalAudit(convertSV2FightSV(*sv)), nil
+	}
+	if err != ErrorNotFound {
+		return nil, err
+	}
+
+	var row []byte
+	err = s.db.QueryRowContext(ctx, "SELECT row_to_json(t) FROM session t WHERE t.uid = $1;", id).Scan(&row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return row, err
+}
+
+// adminArguments is the json of an admin request in the audit log.
+type adminArguments struct {
+	RequestID string        `json:"request_id,omitempty"`
+	Heros     []*fight.Hero `json:"heros,omitempty"`
+	Bosses    []*fight.Boss `json:"bosses,omitempty"`
+	MigrateTo string        `json:"migrate_to,omitempty"`
+	Atomic    bool          `json:"atomic,omitempty"`
+	Patch     *storedPatch  `json:"patch,omitempty"`
+	DryRun    bool          `json:"dry_run,omitempty"`
+	PatchID   int64         `json:"patch_id,omitempty"`
+	Tables    []string      `json:"tables,omitempty"`
+	Actions   []string      `json:"actions,omitempty"`
+}
+
+func newAdminArguments(req *fight.AdminRequest) adminArguments {
+	args := adminArguments{
+		RequestID: req.GetRequestId(),
+		Heros:     req.GetHeros(),
+		Bosses:    req.GetBosses(),
+		MigrateTo: req.GetMigrateTo(),
+		Atomic:    req.GetAtomic(),
+		PatchID:   req.GetPatchId(),
+		Tables:    req.GetTables(),
+		Actions:   req.GetActions(),
+	}
+	patch := req.GetPatch()
+	if req.GetType() == fight.AdminRequest_ADJUST_HERO {
+		patch = adjustHeroPatch
+	}
+	if patch != nil {
+		stored := newStoredPatch(patch)
+		args.Patch, args.DryRun = &stored, patch.GetDryRun()
+	}
+	return args
+}
+
+// marshalAudit returns the json of v, a failure is only logged as the
+// audit does not fail the request.
+func marshalAudit(v interface{}) json.RawMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		klog.Warningf("audit: %v", err)
+		return nil
+	}
+	return data
+}
+
+// newAuditEntry returns the entry of the operation, its actor, role and
+// trace id are the ones of the request.
+func newAuditEntry(ctx context.Context, operation string, arguments json.RawMessage) AuditEntry {
+	entry := AuditEntry{Operation: operation, Arguments: arguments, TraceID: traceID(ctx)}
+	entry.Actor, entry.Role = actorOf(ctx)
+	return entry
+}
+
+// entryOf sets the status of the request ended by err in the entry.
+func entryOf(entry AuditEntry, err error) AuditEntry {
+	entry.Code = status.Code(err)
+	if err != nil {
+		entry.Error = status.Convert(err).Message()
+	}
+	return entry
+}
+
+// audit appends the entry of the request ended by err in its own
+// statement, the request fails with Internal if it is not audited.
+func (s *Service) audit(entry AuditEntry, err error) error {
+	// the request may be cancelled once it is done
+	if aerr := s.auditLog.Append(context.Background(), s.db, entryOf(entry, err)); aerr != nil {
+		klog.Warningf("failed to audit '%s' of '%s': %v", entry.Operation, entry.Actor, aerr)
+		if err == nil {
+			return status.Errorf(codes.Internal, "failed to audit '%s': %v", entry.Operation, aerr)
+		}
+	}
+	return err
+}
+
+// actorOf returns the name of the api key and the role of the request, or
+// the peer address if there is no key.
+func actorOf(ctx context.Context) (string, string) {
+	pr, _ := authz.FromContext(ctx)
+	if pr.Name != "" {
+		return pr.Name, pr.Role
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		return p.Addr.String(), pr.Role
+	}
+	return "", pr.Role
+}
+
+// traceID returns the id of the trace of the request, if any.
+func traceID(ctx context.Context) string {
+	if span := opentracing.SpanFromContext(ctx); span != nil {
+		if sc, ok := span.Context().(jaeger.SpanContext); ok {
+			return sc.TraceID().String()
+		}
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(traceIDMetadataKey); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// ListAuditLog ...
+func (s *Service) ListAuditLog(ctx context.Context, req *fight.ListAuditLogRequest) (*fight.ListAuditLogResponse, error) {
+	filter, err := newAuditFilter(req)
+	if err != nil {
+		return &fight.ListAuditLogResponse{}, err
+	}
+
+	entries, total, err := s.auditLog.List(ctx, filter)
+	if err != nil {
+		return &fight.ListAuditLogResponse{}, err
+	}
+	resp := &fight.ListAuditLogResponse{
+		Entries: make([]*fight.AuditEntry, len(entries)),
+		Total:   int32(total),
+	}
+	for i, entry := range entries {
+		resp.Entries[i] = convertAuditEntry2FightAuditEntry(entry)
+	}
+	return resp, nil
+}
+
+// newAuditFilter validates the filter of the request and sets the default
+// limit.
+func newAuditFilter(req *fight.ListAuditLogRequest) (AuditFilter, error) {
+	filter := AuditFilter{
+		Actor:     req.GetActor(),
+		Operation: req.GetOperation(),
+		TraceID:   req.GetTraceId(),
+		Offset:    int(req.GetOffset()),
+		Limit:     int(req.GetLimit()),
+	}
+	switch {
+	case filter.Limit < 0 || filter.Offset < 0:
+		return filter, status.Error(codes.InvalidArgument, "limit and offset must not be negative")
+	case filter.Limit == 0:
+		filter.Limit = defaultAuditLimit
+	case filter.Limit > maxAuditLimit:
+		filter.Limit = maxAuditLimit
+	}
+
+	if req.GetSince() != nil {
+		if err := req.GetSince().CheckValid(); err != nil {
+			return filter, status.Errorf(codes.InvalidArgument, "invalid since: %v", err)
+		}
+		filter.Since = req.GetSince().AsTime()
+	}
+	if req.GetUntil() != nil {
+		if err := req.GetUntil().CheckValid(); err != nil {
+			return filter, status.Errorf(codes.InvalidArgument, "invalid until: %v", err)
+		}
+		filter.Until = req.GetUntil().AsTime()
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Since.Before(filter.Until) {
+		return filter, status.Error(codes.InvalidArgument, "since must be before until")
+	}
+	return filter, nil
+}
+
+func convertAuditEntry2FightAuditEntry(entry AuditEntry) *fight.AuditEntry {
+	return &fight.AuditEntry{
+		Id:        entry.ID,
+		CreatedAt: timestamppb.New(entry.CreatedAt),
+		Actor:     entry.Actor,
+		Role:      entry.Role,
+		Operation: entry.Operation,
+		Arguments: string(entry.Arguments),
+		Before:    string(entry.Before),
+		After:     string(entry.After),
+		Code:      int32(entry.Code),
+		Error:     entry.Error,
+		TraceId:   entry.TraceID,
+	}
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestAuditFilterWhere(t *testing.T) {
+	since := time.Date(2021, 3, 12, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		filter AuditFilter
+		where  string
+		args   []interface{}
+	}{
+		{AuditFilter{}, "", nil},
+		{AuditFilter{Actor: "ops"}, " WHERE actor = $1", []interface{}{"ops"}},
+		{
+			AuditFilter{Operation: "BALANCE_PATCH", Since: since, Until: since.Add(24 * time.Hour)},
+			" WHERE operation = $1 AND createdat >= $2 AND createdat < $3",
+			[]interface{}{"BALANCE_PATCH", since, since.Add(24 * time.Hour)},
+		},
+		{AuditFilter{Actor: "ops", TraceID: "abc"}, " WHERE actor = $1 AND traceid = $2", []interface{}{"ops", "abc"}},
+	}
+	for _, c := range cases {
+		where, args := c.filter.where()
+		if where != c.where || !reflect.DeepEqual(args, c.args) {
+			t.Errorf("want '%s' %v, but get '%s' %v", c.where, c.args, where, args)
+		}
+	}
+}
+
+func TestNewAuditFilter(t *testing.T) {
+	filter, err := newAuditFilter(&fight.ListAuditLogRequest{})
+	if err != nil || filter.Limit != defaultAuditLimit {
+		t.Errorf("want the default limit %d, but get %d, '%v'", defaultAuditLimit, filter.Limit, err)
+	}
+	filter, err = newAuditFilter(&fight.ListAuditLogRequest{Limit: 10000})
+	if err != nil || filter.Limit != maxAuditLimit {
+		t.Errorf("want the max limit %d, but get %d, '%v'", maxAuditLimit, filter.Limit, err)
+	}
+
+	since := timestamppb.New(time.Date(2021, 3, 12, 0, 0, 0, 0, time.UTC))
+	cases := []*fight.ListAuditLogRequest{
+		{Offset: -1},
+		{Since: since, Until: since},
+		{Since: &timestamppb.Timestamp{Nanos: -1}},
+	}
+	for _, req := range cases {
+		if _, err = newAuditFilter(req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("want InvalidArgument for '%v', but get '%v'", req, err)
+		}
+	}
+}
+
+func TestAuditChangeValues(t *testing.T) {
+	c := &auditChange{
+		before: []json.RawMessage{json.RawMessage(`{"name":"a"}`), nil},
+		after:  []json.RawMessage{json.RawMessage(`{"name":"a","blood":1}`), json.RawMessage(`{"name":"b"}`)},
+	}
+	before, after := c.values()
+	if string(before) != `[{"name":"a"},null]` || string(after) != `[{"name":"a","blood":1},{"name":"b"}]` {
+		t.Errorf("want null before a created hero, but get %s, %s", before, after)
+	}
+	c.begin()
+	if before, after = c.values(); before != nil || after != nil {
+		t.Errorf("want no rows in a new transaction, but get %s, %s", before, after)
+	}
+
+	c.trackDiff([]heroDiff{{
+		Before: module.Hero{Name: "a", Detail: "detail of a", AttackPower: 10, DefensePower: 10, Blood: 100},
+		After:  module.Hero{Name: "a", Detail: "detail of a", AttackPower: 12, DefensePower: 12, Blood: 100},
+	}})
+	before, after = c.values()
+	if string(after) != `[{"name":"a","detail":"detail of a","attackpower":12,"defensepower":12,"blood":100}]` {
+		t.Errorf("want the row of the patched hero, but get %s", after)
+	}
+}
+
+func TestNewAdminArguments(t *testing.T) {
+	args := newAdminArguments(&fight.AdminRequest{Type: fight.AdminRequest_ADJUST_HERO, RequestId: "1"})
+	if args.RequestID != "1" || args.Patch == nil || len(args.Patch.Changes) != 2 || args.Patch.Changes[0].Mode != "MULTIPLY" {
+		t.Errorf("want the patch of ADJUST_HERO, but get '%v'", args)
+	}
+	args = newAdminArguments(&fight.AdminRequest{Type: fight.AdminRequest_DELETE_HERO, MigrateTo: "PostgreSql"})
+	if args.Patch != nil || args.MigrateTo != "PostgreSql" {
+		t.Errorf("want no patch and the migrate_to, but get '%v'", args)
+	}
+}
+
+func TestActorAndTraceID(t *testing.T) {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000}})
+	if actor, role := actorOf(ctx); actor != "10.0.0.1:5000" || role != "" {
+		t.Errorf("want the peer address without a role, but get '%s', '%s'", actor, role)
+	}
+
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(traceIDMetadataKey, "463ac35c9f6413ad"))
+	if id := traceID(ctx); id != "463ac35c9f6413ad" {
+		t.Errorf("want the trace id of the metadata, but get '%s'", id)
+	}
+	if id := traceID(context.Background()); id != "" {
+		t.Errorf("want no trace id, but get '%s'", id)
+	}
+}
+
+func TestAdminAudit(t *testing.T) {
+	db, f := newFakeDB(t)
+	s := &Service{db: db, sessions: NewMemoryStore(), auditLog: NewAuditLog(db)}
+	req := &fight.AdminRequest{Type: fight.AdminRequest_CREATE_HERO, Heros: []*fight.Hero{
+		{Name: "Redis", Details: "the fast cache", AttackPower: 30, DefensePower: 20, Blood: 100},
+		{Name: "Oracle", Details: "the great evil", AttackPower: 80, DefensePower: 50, Blood: 200},
+	}}
+	row := []string{"row_to_json"}
+
+	// the entry of each transaction commits with it
+	f.expect("BEGIN")
+	f.expect("SELECT row_to_json(t) FROM hero").returns(row)
+	f.expect("INSERT INTO hero", "Redis", "the fast cache", 30, 20, 100)
+	f.expect("SELECT row_to_json(t) FROM hero").returns(row, []interface{}{[]byte(`{"name":"Redis"}`)})
+	f.expect("INSERT INTO audit_log")
+	f.expect("COMMIT")
+	f.expect("BEGIN")
+	f.expect("SELECT row_to_json(t) FROM hero").returns(row)
+	f.expect("INSERT INTO hero", "Oracle", "the great evil", 80, 50, 200).fails(&pq.Error{Code: uniqueViolation})
+	f.expect("ROLLBACK")
+	change := newAuditChange(context.Background(), req)
+	applied, err := s.admin(context.Background(), req, nil, &fight.AdminResult{}, change)
+	if applied != 1 || status.Code(err) != codes.AlreadyExists || !change.committed {
+		t.Errorf("want the first hero applied and audited, but get %d, '%v'", applied, err)
+	}
+	f.expect("INSERT INTO audit_log", "", "", "CREATE_HERO", []byte(marshalAudit(change.args)), nil, nil, int(codes.AlreadyExists), "hero 'Oracle' already exists", "")
+	if err = s.audit(change.failure(err), err); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("want the failure audited, but get '%v'", err)
+	}
+
+	// no change lands without its entry
+	req.Heros = req.Heros[:1]
+	f.expect("BEGIN")
+	f.expect("SELECT row_to_json(t) FROM hero").returns(row)
+	f.expect("INSERT INTO hero", "Redis", "the fast cache", 30, 20, 100)
+	f.expect("SELECT row_to_json(t) FROM hero").returns(row, []interface{}{[]byte(`{"name":"Redis"}`)})
+	f.expect("INSERT INTO audit_log").fails(errors.New("disk full"))
+	f.expect("ROLLBACK")
+	change = newAuditChange(context.Background(), req)
+	if applied, err = s.admin(context.Background(), req, nil, &fight.AdminResult{}, change); applied != 0 || status.Code(err) != codes.Internal || change.committed {
+		t.Errorf("want the hero rolled back with its entry, but get %d, '%v'", applied, err)
+	}
+
+	// a request without a transaction fails if it is not audited
+	f.expect("INSERT INTO audit_log").fails(errors.New("disk full"))
+	if err = s.audit(newAuditEntry(context.Background(), "SUBSCRIBE", nil), nil); status.Code(err) != codes.Internal {
+		t.Errorf("want Internal, but get '%v'", err)
+	}
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"math"
+
+	"github.com/lib/pq"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// adjustHeroPatch is the balance patch of ADJUST_HERO.
+var adjustHeroPatch = &fight.BalancePatch{
+	Changes: []*fight.BalancePatch_Change{
+		{Stat: fight.BalancePatch_ATTACK_POWER, Mode: fight.BalancePatch_MULTIPLY, Value: 1.2},
+		{Stat: fight.BalancePatch_DEFENSE_POWER, Mode: fight.BalancePatch_MULTIPLY, Value: 1.2},
+	},
+}
+
+// heroDiff is a change of a hero by a balance patch, kept in the diff
+// column of the balance_patch table.
+type heroDiff struct {
+	Before module.Hero `json:"before"`
+	After  module.Hero `json:"after"`
+}
+
+// storedPatch is the json form of a balance patch in the patch column of
+// the balance_patch table.
+type storedPatch struct {
+	Heros   []string       `json:"heros,omitempty"`
+	Changes []storedChange `json:"changes"`
+}
+
+type storedChange struct {
+	Stat  string  `json:"stat"`
+	Mode  string  `json:"mode"`
+	Value float64 `json:"value"`
+}
+
+func newStoredPatch(patch *fight.BalancePatch) storedPatch {
+	stored := storedPatch{Heros: patch.GetHeros()}
+	for _, change := range patch.GetChanges() {
+		stored.Changes = append(stored.Changes, storedChange{
+			Stat:  change.GetStat().String(),
+			Mode:  change.GetMode().String(),
+			Value: change.GetValue(),
+		})
+	}
+	return stored
+}
+
+// patchHero returns the hero changed by the balance patch, the stats are
+// rounded to the nearest integer and must stay non-negative.
+func patchHero(hero module.Hero, changes []*fight.BalancePatch_Change) (module.Hero, error) {
+	for _, change := range changes {
+		var stat *int
+		switch change.GetStat() {
+		case fight.BalancePatch_ATTACK_POWER:
+			stat = &hero.AttackPower
+		case fight.BalancePatch_DEFENSE_POWER:
+			stat = &hero.DefensePower
+		case fight.BalancePatch_BLOOD:
+			stat = &hero.Blood
+		default:
+			return hero, status.Errorf(codes.InvalidArgument, "undefined stat: '%v'", change.GetStat())
+		}
+
+		value := change.GetValue()
+		switch change.GetMode() {
+		case fight.BalancePatch_SET:
+		case fight.BalancePatch_ADD:
+			value += float64(*stat)
+		case fight.BalancePatch_MULTIPLY:
+			value *= float64(*stat)
+		default:
+			return hero, status.Errorf(codes.InvalidArgument, "undefined mode: '%v'", change.GetMode())
+		}
+
+		value = math.Round(value)
+		if math.IsNaN(value) || value < 0 || value > math.MaxInt32 {
+			return hero, status.Errorf(codes.InvalidArgument, "%v of the hero '%s' would be out of range: %v", change.GetStat(), hero.Name, value)
+		}
+		*stat = int(value)
+	}
+	return hero, nil
+}
+
+// applyBalancePatch changes the stats of the heros selected by the patch,
+// and stores the patch unless it is a dry run. It returns the diff and the
+// id of the stored patch.
+func applyBalancePatch(ctx context.Context, q dbtx, patch *fight.BalancePatch) ([]heroDiff, int64, error) {
+	if len(patch.GetChanges()) == 0 {
+		return nil, 0, status.Error(codes.InvalidArgument, "a balance patch changes one stat at least")
+	}
+
+	heros, err := selectHeros(ctx, q, patch.GetHeros())
+	if err != nil {
+		return nil, 0, err
+	}
+	diff := make([]heroDiff, len(heros))
+	for i, hero := range heros {
+		after, err := patchHero(hero, patch.GetChanges())
+		if err != nil {
+			return nil, 0, err
+		}
+		diff[i] = heroDiff{Before: hero, After: after}
+	}
+	if patch.GetDryRun() {
+		return diff, 0, nil
+	}
+
+	for _, d := range diff {
+		if err = setHeroStats(ctx, q, d.After); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	stored, err := json.Marshal(newStoredPatch(patch))
+	if err != nil {
+		return nil, 0, err
+	}
+	storedDiff, err := json.Marshal(diff)
+	if err != nil {
+		return nil, 0, err
+	}
+	var id int64
+	err = q.QueryRowContext(ctx, "INSERT INTO balance_patch(patch, diff, appliedat) VALUES($1, $2, now()) RETURNING id;", stored, storedDiff).Scan(&id)
+	return diff, id, err
+}
+
+// revertBalancePatch restores the stats of the heros changed by the patch,
+// the revert fails if a hero changed since. It returns the diff of the
+// revert.
+func revertBalancePatch(ctx context.Context, q dbtx, id int64) ([]heroDiff, error) {
+	var (
+		stored   []byte
+		reverted bool
+	)
+	err := q.QueryRowContext(ctx, "SELECT diff, revertedat IS NOT NULL FROM balance_patch WHERE id = $1 FOR UPDATE;", id).Scan(&stored, &reverted)
+	if err == sql.ErrNoRows {
+		return nil, status.Errorf(codes.NotFound, "undefined balance patch: '%d'", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if reverted {
+		return nil, status.Errorf(codes.FailedPrecondition, "balance patch '%d' is reverted already", id)
+	}
+
+	var diff []heroDiff
+	if err = json.Unmarshal(stored, &diff); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(diff))
+	for i, d := range diff {
+		names[i] = d.Before.Name
+	}
+	heros, err := selectHeros(ctx, q, names)
+	if status.Code(err) == codes.NotFound {
+		return nil, status.Errorf(codes.FailedPrecondition, "can not revert the balance patch '%d': %s", id, status.Convert(err).Message())
+	}
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]module.Hero, len(heros))
+	for _, hero := range heros {
+		current[hero.Name] = hero
+	}
+
+	revert := make([]heroDiff, len(diff))
+	for i, d := range diff {
+		if !sameStats(current[d.After.Name], d.After) {
+			return nil, status.Errorf(codes.FailedPrecondition, "hero '%s' changed since the balance patch '%d'", d.After.Name, id)
+		}
+		if err = setHeroStats(ctx, q, d.Before); err != nil {
+			return nil, err
+		}
+		revert[i] = heroDiff{Before: current[d.After.Name], After: d.Before}
+	}
+
+	_, err = q.ExecContext(ctx, "UPDATE balance_patch SET revertedat = now() WHERE id = $1;", id)
+	return revert, err
+}
+
+// selectHeros locks and returns the heros of the names, or all the heros if
+// there is no name.
+func selectHeros(ctx context.Context, q dbtx, names []string) ([]module.Hero, error) {
+	sqlStatement, args := `SELECT name, detail, attackpower, defensepower, blood FROM hero ORDER BY name FOR UPDATE;`, []interface{}{}
+	if len(names) > 0 {
+		sqlStatement, args = `SELECT name, detail, attackpower, defensepower, blood FROM hero WHERE name = ANY($1) ORDER BY name FOR UPDATE;`, []interface{}{pq.Array(names)}
+	}
+	rows, err := q.QueryContext(ctx, sqlStatement, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		heros []module.Hero
+		found = make(map[string]struct{})
+	)
+	for rows.Next() {
+		var hero module.Hero
+		if err = rows.Scan(&hero.Name, &hero.Detail, &hero.AttackPower, &hero.DefensePower, &hero.Blood); err != nil {
+			return nil, err
+		}
+		heros = append(heros, hero)
+		found[hero.Name] = struct{}{}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		if _, ok := found[name]; !ok {
+			return nil, status.Errorf(codes.NotFound, "undefined hero: '%s'", name)
+		}
+	}
+	return heros, nil
+}
+
+func setHeroStats(ctx context.Context, q dbtx, hero module.Hero) error {
+	sqlStatement := `UPDATE hero SET attackpower = $2, defensepower = $3, blood = $4 WHERE name = $1;`
+	_, err := q.ExecContext(ctx, sqlStatement, hero.Name, hero.AttackPower, hero.DefensePower, hero.Blood)
+	return err
+}
+
+func sameStats(a, b module.Hero) bool {
+	return a.AttackPower == b.AttackPower && a.DefensePower == b.DefensePower && a.Blood == b.Blood
+}
+
+func convertHeroDiffs(diff []heroDiff) []*fight.HeroDiff {
+	diffs := make([]*fight.HeroDiff, len(diff))
+	for i, d := range diff {
+		diffs[i] = &fight.HeroDiff{
+			Before: convertModuleHero2FightHero(d.Before),
+			After:  convertModuleHero2FightHero(d.After),
+		}
+	}
+	return diffs
+}
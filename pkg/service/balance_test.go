@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPatchHero(t *testing.T) {
+	hero := module.Hero{Name: "PostgreSql", AttackPower: 50, DefensePower: 30, Blood: 100}
+
+	tests := []struct {
+		name    string
+		changes []*fight.BalancePatch_Change
+		want    module.Hero
+	}{
+		{
+			"adjust hero",
+			adjustHeroPatch.Changes,
+			module.Hero{Name: "PostgreSql", AttackPower: 60, DefensePower: 36, Blood: 100},
+		},
+		{
+			"set and add",
+			[]*fight.BalancePatch_Change{
+				{Stat: fight.BalancePatch_BLOOD, Mode: fight.BalancePatch_SET, Value: 120},
+				{Stat: fight.BalancePatch_ATTACK_POWER, Mode: fight.BalancePatch_ADD, Value: -5},
+			},
+			module.Hero{Name: "PostgreSql", AttackPower: 45, DefensePower: 30, Blood: 120},
+		},
+		{
+			"rounded",
+			[]*fight.BalancePatch_Change{
+				{Stat: fight.BalancePatch_DEFENSE_POWER, Mode: fight.BalancePatch_MULTIPLY, Value: 1.05},
+			},
+			module.Hero{Name: "PostgreSql", AttackPower: 50, DefensePower: 32, Blood: 100},
+		},
+	}
+	for _, tt := range tests {
+		got, err := patchHero(hero, tt.changes)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: want '%v', but get '%v'", tt.name, tt.want, got)
+		}
+	}
+
+	_, err := patchHero(hero, []*fight.BalancePatch_Change{
+		{Stat: fight.BalancePatch_BLOOD, Mode: fight.BalancePatch_ADD, Value: -101},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("want InvalidArgument for a negative stat, but get '%v'", err)
+	}
+}
+
+func TestRevertBalancePatch(t *testing.T) {
+	db, f := newFakeDB(t)
+	before := module.Hero{Name: "PostgreSql", Detail: "master of opensource", AttackPower: 50, DefensePower: 30, Blood: 100}
+	after := before
+	after.AttackPower = 60
+	diff, err := json.Marshal([]heroDiff{{Before: before, After: after}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	patch := []string{"diff", "reverted"}
+	heros := []string{"name", "detail", "attackpower", "defensepower", "blood"}
+
+	f.expect("FROM balance_patch WHERE id", 1).returns(patch, []interface{}{diff, false})
+	f.expect("FROM hero WHERE name = ANY").returns(heros, []interface{}{"PostgreSql", "master of opensource", 60, 30, 100})
+	f.expect("UPDATE hero SET attackpower", "PostgreSql", 50, 30, 100).affects(1)
+	f.expect("UPDATE balance_patch SET revertedat", 1).affects(1)
+	revert, err := revertBalancePatch(context.Background(), db, 1)
+	if err != nil || len(revert) != 1 || revert[0].Before.AttackPower != 60 || revert[0].After.AttackPower != 50 {
+		t.Errorf("want the attack power reverted from 60 to 50, but get '%v', '%v'", revert, err)
+	}
+
+	cases := []struct {
+		name   string
+		script func()
+		code   codes.Code
+	}{
+		{"undefined", func() {
+			f.expect("FROM balance_patch WHERE id", 1).returns(patch)
+		}, codes.NotFound},
+		{"reverted", func() {
+			f.expect("FROM balance_patch WHERE id", 1).returns(patch, []interface{}{diff, true})
+		}, codes.FailedPrecondition},
+		{"changed since", func() {
+			f.expect("FROM balance_patch WHERE id", 1).returns(patch, []interface{}{diff, false})
+			f.expect("FROM hero WHERE name = ANY").returns(heros, []interface{}{"PostgreSql", "master of opensource", 70, 30, 100})
+		}, codes.FailedPrecondition},
+		{"deleted since", func() {
+			f.expect("FROM balance_patch WHERE id", 1).returns(patch, []interface{}{diff, false})
+			f.expect("FROM hero WHERE name = ANY").returns(heros)
+		}, codes.FailedPrecondition},
+	}
+	for _, c := range cases {
+		c.script()
+		if _, err = revertBalancePatch(context.Background(), db, 1); status.Code(err) != c.code {
+			t.Errorf("%s: want %v, but get '%v'", c.name, c.code, err)
+		}
+	}
+}
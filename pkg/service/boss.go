@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"unicode/utf8"
+
+	"github.com/lib/pq"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxBossName is the length of the name column of the boss table.
+const maxBossName = 20
+
+// validateBoss mirrors the constraints of the boss table, so that a bad
+// boss fails with InvalidArgument instead of a db error.
+func validateBoss(boss *fight.Boss) error {
+	if boss.GetName() == "" || utf8.RuneCountInString(boss.GetName()) > maxBossName {
+		return status.Errorf(codes.InvalidArgument, "boss name must be 1 to %d characters: '%s'", maxBossName, boss.GetName())
+	}
+	if utf8.RuneCountInString(boss.GetDetails()) <= 4 {
+		return status.Errorf(codes.InvalidArgument, "details of the boss '%s' must be longer than 4 characters", boss.GetName())
+	}
+	if boss.GetAttackPower() < 0 || boss.GetDefensePower() < 0 || boss.GetBlood() < 0 || boss.GetLevel() < 0 {
+		return status.Errorf(codes.InvalidArgument, "stats and level of the boss '%s' must not be negative", boss.GetName())
+	}
+	return nil
+}
+
+// shiftLevels moves the bosses from the level on by delta. The levels are
+// unique, so they are negated first and restored in a second statement;
+// the sessions follow their boss through the foreign key, and the level
+// clears are moved the same way. The events of the negative levels are
+// ignored by watchEvents.
+func shiftLevels(ctx context.Context, q dbtx, from, delta int) error {
+	for _, table := range []string{"boss", "level_clear"} {
+		if _, err := q.ExecContext(ctx, "UPDATE "+table+" SET level = -(level + $2) WHERE level >= $1;", from, delta); err != nil {
+			return err
+		}
+		if _, err := q.ExecContext(ctx, "UPDATE "+table+" SET level = -level WHERE level < 0;"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func countBosses(ctx context.Context, q dbtx) (int, error) {
+	var n int
+	err := q.QueryRowContext(ctx, "SELECT count(*) FROM boss;").Scan(&n)
+	return n, err
+}
+
+// lockBosses blocks the concurrent changes of the levels until the end of
+// the transaction, the reads of the bosses go on.
+func lockBosses(ctx context.Context, q dbtx) error {
+	_, err := q.ExecContext(ctx, "LOCK TABLE boss IN SHARE ROW EXCLUSIVE MODE;")
+	return err
+}
+
+// createBoss inserts the boss at its level and moves the following levels
+// down, a boss without a level is appended after the last level.
+func createBoss(ctx context.Context, q dbtx, boss *fight.Boss) error {
+	if err := validateBoss(boss); err != nil {
+		return err
+	}
+	if err := lockBosses(ctx, q); err != nil {
+		return err
+	}
+	n, err := countBosses(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	level := int(boss.GetLevel())
+	switch {
+	case level == 0 || level == n+1:
+		level = n + 1
+	case level > n+1:
+		return status.Errorf(codes.InvalidArgument, "level of the boss '%s' must be 1 to %d: '%d'", boss.Name, n+1, level)
+	default:
+		if err = shiftLevels(ctx, q, level, 1); err != nil {
+			return err
+		}
+	}
+
+	sqlStatement := `INSERT INTO boss(name, detail, attackpower, defensepower, blood, level) VALUES($1, $2, $3, $4, $5, $6);`
+	_, err = q.ExecContext(ctx, sqlStatement, boss.Name, boss.Details, boss.AttackPower, boss.DefensePower, boss.Blood, level)
+	if e, ok := err.(*pq.Error); ok && e.Code == uniqueViolation {
+		if e.Constraint == "boss_level_key" {
+			return status.Errorf(codes.Aborted, "level %d of the boss '%s' is taken by a concurrent change", level, boss.Name)
+		}
+		return status.Errorf(codes.AlreadyExists, "boss '%s' already exists", boss.Name)
+	}
+	return err
+}
+
+// updateBoss updates the stats of the boss, its level is set by
+// reorderBosses only.
+func updateBoss(ctx context.Context, q dbtx, boss *fight.Boss) error {
+	if err := validateBoss(boss); err != nil {
+		return err
+	}
+
+	var level int
+	err := q.QueryRowContext(ctx, "SELECT level FROM boss WHERE name = $1;", boss.Name).Scan(&level)
+	if err == sql.ErrNoRows {
+		return status.Errorf(codes.NotFound, "undefined boss: '%s'", boss.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if boss.GetLevel() != 0 && int(boss.GetLevel()) != level {
+		return status.Errorf(codes.InvalidArgument, "level of the boss '%s' is changed by REORDER_BOSSES", boss.Name)
+	}
+
+	sqlStatement := `UPDATE boss SET detail = $2, attackpower = $3, defensepower = $4, blood = $5 WHERE name = $1;`
+	_, err = q.ExecContext(ctx, sqlStatement, boss.Name, boss.Details, boss.AttackPower, boss.DefensePower, boss.Blood)
+	return err
+}
+
+// deleteBoss deletes the boss and moves the following levels up. The
+// delete fails with FailedPrecondition while active sessions fight the
+// boss: the live sessions of this replica and the leased sessions of the
+// other replicas. The archived sessions of the level move on to the boss
+// taking the level, or back to the previous boss after the last level, and
+// the clears of the level are deleted with the boss.
+func (s *Service) deleteBoss(ctx context.Context, q dbtx, name string) error {
+	if err := lockBosses(ctx, q); err != nil {
+		return err
+	}
+	var level int
+	err := q.QueryRowContext(ctx, "SELECT level FROM boss WHERE name = $1;", name).Scan(&level)
+	if err == sql.ErrNoRows {
+		return status.Errorf(codes.NotFound, "undefined boss: '%s'", name)
+	}
+	if err != nil {
+		return err
+	}
+
+	sessionViews, err := s.sessions.List()
+	if err != nil {
+		return err
+	}
+	var sessions int
+	live := make([]string, 0, len(sessionViews))
+	for _, sv := range sessionViews {
+		live = append(live, sv.UID)
+		if sv.Boss.Name == name {
+			sessions++
+		}
+	}
+	var leased int
+	sqlStatement := `SELECT count(*) FROM session JOIN session_lease USING (uid) WHERE session.currentlevel = $1 AND session_lease.expiresat >= now() AND NOT session.uid = ANY($2);`
+	if err = q.QueryRowContext(ctx, sqlStatement, level, pq.Array(live)).Scan(&leased); err != nil {
+		return err
+	}
+	if sessions += leased; sessions > 0 {
+		return status.Errorf(codes.FailedPrecondition, "%d sessions fight the boss '%s'", sessions, name)
+	}
+
+	n, err := countBosses(ctx, q)
+	if err != nil {
+		return err
+	}
+	if n == 1 {
+		var archived int
+		if err = q.QueryRowContext(ctx, "SELECT count(*) FROM session WHERE currentlevel = $1;", level).Scan(&archived); err != nil {
+			return err
+		}
+		if archived > 0 {
+			return status.Errorf(codes.FailedPrecondition, "%d archived sessions fight the last boss '%s'", archived, name)
+		}
+	} else {
+		next := level + 1
+		if level == n {
+			next = level - 1
+		}
+		if _, err = q.ExecContext(ctx, "UPDATE session SET currentlevel = $2 WHERE currentlevel = $1;", level, next); err != nil {
+			return err
+		}
+	}
+
+	if _, err = q.ExecContext(ctx, "DELETE FROM level_clear WHERE level = $1;", level); err != nil {
+		return err
+	}
+	if _, err = q.ExecContext(ctx, "DELETE FROM boss WHERE name = $1;", name); err != nil {
+		return err
+	}
+	return shiftLevels(ctx, q, level+1, -1)
+}
+
+// reorderBosses sets the levels of the bosses to their order, every boss
+// is listed once so that the levels stay contiguous. The level clears
+// follow their boss: the levels of both are negated first, then each boss
+// and its clears take the new level.
+func reorderBosses(ctx context.Context, q dbtx, names []string) error {
+	if err := lockBosses(ctx, q); err != nil {
+		return err
+	}
+	n, err := countBosses(ctx, q)
+	if err != nil {
+		return err
+	}
+	if len(names) != n {
+		return status.Errorf(codes.InvalidArgument, "all the %d bosses must be listed, but get %d", n, len(names))
+	}
+
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		if _, ok := seen[name]; ok {
+			return status.Errorf(codes.InvalidArgument, "boss '%s' is listed twice", name)
+		}
+		seen[name] = struct{}{}
+	}
+
+	if _, err = q.ExecContext(ctx, "UPDATE level_clear SET level = -level WHERE level IN (SELECT level FROM boss);"); err != nil {
+		return err
+	}
+	if _, err = q.ExecContext(ctx, "UPDATE boss SET level = -level;"); err != nil {
+		return err
+	}
+	for i, name := range names {
+		if _, err = q.ExecContext(ctx, "UPDATE level_clear SET level = $2 WHERE level = (SELECT level FROM boss WHERE name = $1);", name, i+1); err != nil {
+			return err
+		}
+		result, err := q.ExecContext(ctx, "UPDATE boss SET level = $2 WHERE name = $1;", name, i+1)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return status.Errorf(codes.NotFound, "undefined boss: '%s'", name)
+		}
+	}
+	return nil
+}
+
+// applyBossChange applies the changed boss to the live sessions fighting
+// it, the sessions follow their boss to its new level.
+func (s *Service) applyBossChange(boss module.Boss) error {
+	sessionViews, err := s.sessions.List()
+	if err != nil {
+		return err
+	}
+	for _, sv := range sessionViews {
+		if sv.Boss.Name != boss.Name {
+			continue
+		}
+		if err = s.applyLive(sv.UID, SessionEvent{Kind: BossChanged, Boss: &boss}, func(sv *module.SessionView) bool {
+			return sv.Boss.Name == boss.Name
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setBoss replaces the boss of the session, the live blood is clamped to
+// the blood of the new boss.
+func setBoss(sv *module.SessionView, boss module.Boss) {
+	sv.Boss = boss
+	sv.CurrentLevel = boss.Level
+	if sv.LiveBossBlood > boss.Blood {
+		sv.LiveBossBlood = boss.Blood
+	}
+	if sv.LiveBossBlood < 0 {
+		sv.LiveBossBlood = 0
+	}
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidateBoss(t *testing.T) {
+	valid := fight.Boss{Name: "MySQL", Details: "The leader", AttackPower: 40, DefensePower: 40, Blood: 100, Level: 2}
+
+	tests := []struct {
+		name   string
+		modify func(*fight.Boss)
+		valid  bool
+	}{
+		{"valid", func(*fight.Boss) {}, true},
+		{"no level", func(b *fight.Boss) { b.Level = 0 }, true},
+		{"no name", func(b *fight.Boss) { b.Name = "" }, false},
+		{"long name", func(b *fight.Boss) { b.Name = "MySQLMySQLMySQLMySQLX" }, false},
+		{"short details", func(b *fight.Boss) { b.Details = "1234" }, false},
+		{"negative blood", func(b *fight.Boss) { b.Blood = -1 }, false},
+		{"negative level", func(b *fight.Boss) { b.Level = -1 }, false},
+	}
+	for _, tt := range tests {
+		boss := valid
+		tt.modify(&boss)
+		err := validateBoss(&boss)
+		if tt.valid && err != nil {
+			t.Errorf("%s: want valid, but get '%v'", tt.name, err)
+		}
+		if !tt.valid && status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: want InvalidArgument, but get '%v'", tt.name, err)
+		}
+	}
+}
+
+func TestCreateBoss(t *testing.T) {
+	db, f := newFakeDB(t)
+	boss := &fight.Boss{Name: "Redis", Details: "The fast one", AttackPower: 30, DefensePower: 30, Blood: 100, Level: 2}
+
+	f.expect("LOCK TABLE boss")
+	f.expect("SELECT count(*) FROM boss").returns([]string{"count"}, []interface{}{4})
+	f.expect("UPDATE boss SET level = -(level + $2) WHERE level >= $1", 2, 1)
+	f.expect("UPDATE boss SET level = -level WHERE level < 0")
+	f.expect("UPDATE level_clear SET level = -(level + $2) WHERE level >= $1", 2, 1).affects(2)
+	f.expect("UPDATE level_clear SET level = -level WHERE level < 0")
+	f.expect("INSERT INTO boss", "Redis", "The fast one", 30, 30, 100, 2)
+	if err := createBoss(context.Background(), db, boss); err != nil {
+		t.Errorf("want the boss inserted at level 2, but get '%v'", err)
+	}
+
+	cases := []struct {
+		constraint string
+		code       codes.Code
+	}{
+		{"boss_name_key", codes.AlreadyExists},
+		{"boss_level_key", codes.Aborted},
+	}
+	for _, c := range cases {
+		f.expect("LOCK TABLE boss")
+		f.expect("SELECT count(*) FROM boss").returns([]string{"count"}, []interface{}{4})
+		f.expect("INSERT INTO boss", "Redis", "The fast one", 30, 30, 100, 5).fails(&pq.Error{Code: uniqueViolation, Constraint: c.constraint})
+		boss.Level = 0
+		if err := createBoss(context.Background(), db, boss); status.Code(err) != c.code {
+			t.Errorf("%s: want %v, but get '%v'", c.constraint, c.code, err)
+		}
+	}
+}
+
+func TestDeleteBoss(t *testing.T) {
+	db, f := newFakeDB(t)
+	s := &Service{sessions: NewMemoryStore()}
+	live := &module.SessionView{Session: module.Session{UID: "1", CurrentLevel: 2}, Boss: module.Boss{Name: "MySQL", Level: 2}}
+	if err := s.sessions.Add("1", live); err != nil {
+		t.Fatal(err)
+	}
+
+	// a live session fights the boss
+	f.expect("LOCK TABLE boss")
+	f.expect("SELECT level FROM boss WHERE name", "MySQL").returns([]string{"level"}, []interface{}{2})
+	f.expect("JOIN session_lease").returns([]string{"count"}, []interface{}{0})
+	if err := s.deleteBoss(context.Background(), db, "MySQL"); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("want FailedPrecondition, but get '%v'", err)
+	}
+
+	// the archived sessions move on to the next boss, which takes the level,
+	// the clears of the level go with the boss and the following ones move up
+	f.expect("LOCK TABLE boss")
+	f.expect("SELECT level FROM boss WHERE name", "Db2").returns([]string{"level"}, []interface{}{3})
+	f.expect("JOIN session_lease").returns([]string{"count"}, []interface{}{0})
+	f.expect("SELECT count(*) FROM boss").returns([]string{"count"}, []interface{}{4})
+	f.expect("UPDATE session SET currentlevel", 3, 4).affects(5)
+	f.expect("DELETE FROM level_clear WHERE level", 3).affects(4)
+	f.expect("DELETE FROM boss WHERE name", "Db2").affects(1)
+	f.expect("UPDATE boss SET level = -(level + $2) WHERE level >= $1", 4, -1)
+	f.expect("UPDATE boss SET level = -level WHERE level < 0")
+	f.expect("UPDATE level_clear SET level = -(level + $2) WHERE level >= $1", 4, -1).affects(2)
+	f.expect("UPDATE level_clear SET level = -level WHERE level < 0")
+	if err := s.deleteBoss(context.Background(), db, "Db2"); err != nil {
+		t.Errorf("want the boss deleted, but get '%v'", err)
+	}
+
+	// the archived sessions of the last level go back to the previous boss
+	f.expect("LOCK TABLE boss")
+	f.expect("SELECT level FROM boss WHERE name", "Oracle").returns([]string{"level"}, []interface{}{3})
+	f.expect("JOIN session_lease").returns([]string{"count"}, []interface{}{0})
+	f.expect("SELECT count(*) FROM boss").returns([]string{"count"}, []interface{}{3})
+	f.expect("UPDATE session SET currentlevel", 3, 2).affects(1)
+	f.expect("DELETE FROM level_clear WHERE level", 3)
+	f.expect("DELETE FROM boss WHERE name", "Oracle").affects(1)
+	f.expect("UPDATE boss SET level = -(level + $2) WHERE level >= $1", 4, -1)
+	f.expect("UPDATE boss SET level = -level WHERE level < 0")
+	f.expect("UPDATE level_clear SET level = -(level + $2) WHERE level >= $1", 4, -1).affects(2)
+	f.expect("UPDATE level_clear SET level = -level WHERE level < 0")
+	if err := s.deleteBoss(context.Background(), db, "Oracle"); err != nil {
+		t.Errorf("want the last boss deleted, but get '%v'", err)
+	}
+
+	// the only boss has nowhere to send its archived sessions
+	f.expect("LOCK TABLE boss")
+	f.expect("SELECT level FROM boss WHERE name", "SQLServer").returns([]string{"level"}, []interface{}{1})
+	f.expect("JOIN session_lease").returns([]string{"count"}, []interface{}{0})
+	f.expect("SELECT count(*) FROM boss").returns([]string{"count"}, []interface{}{1})
+	f.expect("SELECT count(*) FROM session WHERE currentlevel", 1).returns([]string{"count"}, []interface{}{2})
+	if err := s.deleteBoss(context.Background(), db, "SQLServer"); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("want FailedPrecondition for the only boss, but get '%v'", err)
+	}
+}
+
+func TestReorderBosses(t *testing.T) {
+	db, f := newFakeDB(t)
+
+	// the clears follow their boss to its new level
+
+	f.expect("LOCK TABLE boss")
+	f.expect("SELECT count(*) FROM boss").returns([]string{"count"}, []interface{}{2})
+	f.expect("UPDATE level_clear SET level = -level WHERE level IN (SELECT level FROM boss)")
+	f.expect("UPDATE boss SET level = -level;")
+	f.expect("UPDATE level_clear SET level = $2", "MySQL", 1)
+	f.expect("UPDATE boss SET level = $2 WHERE name", "MySQL", 1).affects(1)
+	f.expect("UPDATE level_clear SET level = $2", "SQLServer", 2)
+	f.expect("UPDATE boss SET level = $2 WHERE name", "SQLServer", 2).affects(1)
+	if err := reorderBosses(context.Background(), db, []string{"MySQL", "SQLServer"}); err != nil {
+		t.Errorf("want the bosses reordered, but get '%v'", err)
+	}
+
+	cases := []struct {
+		names []string
+		code  codes.Code
+	}{
+		{[]string{"MySQL"}, codes.InvalidArgument},
+		{[]string{"MySQL", "MySQL"}, codes.InvalidArgument},
+	}
+	for _, c := range cases {
+		f.expect("LOCK TABLE boss")
+		f.expect("SELECT count(*) FROM boss").returns([]string{"count"}, []interface{}{2})
+		if err := reorderBosses(context.Background(), db, c.names); status.Code(err) != c.code {
+			t.Errorf("%v: want %v, but get '%v'", c.names, c.code, err)
+		}
+	}
+
+	f.expect("LOCK TABLE boss")
+	f.expect("SELECT count(*) FROM boss").returns([]string{"count"}, []interface{}{2})
+	f.expect("UPDATE level_clear SET level = -level WHERE level IN (SELECT level FROM boss)")
+	f.expect("UPDATE boss SET level = -level;")
+	f.expect("UPDATE level_clear SET level = $2", "MySQL", 1)
+	f.expect("UPDATE boss SET level = $2 WHERE name", "MySQL", 1).affects(1)
+	f.expect("UPDATE level_clear SET level = $2", "Redis", 2)
+	f.expect("UPDATE boss SET level = $2 WHERE name", "Redis", 2).affects(0)
+	if err := reorderBosses(context.Background(), db, []string{"MySQL", "Redis"}); status.Code(err) != codes.NotFound {
+		t.Errorf("want NotFound, but get '%v'", err)
+	}
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+	"k8s.io/klog"
+)
+
+// HeroUpdatePolicy decides when the hero changes of the catalog are applied
+// to the live sessions.
+type HeroUpdatePolicy string
+
+const (
+	// ApplyImmediately applies the hero changes to the live sessions at once.
+	ApplyImmediately HeroUpdatePolicy = "immediate"
+	// ApplyOnNextLevel applies the hero changes when the session goes to the next level.
+	ApplyOnNextLevel HeroUpdatePolicy = "next-level"
+	// ApplyNever keeps the hero of the live sessions, the changes take effect for the new sessions only.
+	ApplyNever HeroUpdatePolicy = "never"
+)
+
+// ParseHeroUpdatePolicy ...
+func ParseHeroUpdatePolicy(policy string) (HeroUpdatePolicy, error) {
+	switch p := HeroUpdatePolicy(policy); p {
+	case ApplyImmediately, ApplyOnNextLevel, ApplyNever:
+		return p, nil
+	default:
+		return "", fmt.Errorf("undefined hero update policy: '%s'", policy)
+	}
+}
+
+// WithHeroUpdatePolicy sets when the hero changes are applied to the live
+// sessions, the default is ApplyImmediately.
+func WithHeroUpdatePolicy(policy HeroUpdatePolicy) Option {
+	return func(s *Service) {
+		s.heroPolicy = policy
+	}
+}
+
+// watchEvents applies the changes of the catalog to the live sessions
+// until the dispatcher stops.
+func (s *Service) watchEvents() {
+	if s.dispatcher == nil {
+		return
+	}
+
+	sub := s.dispatcher.Subscribe(context.Background(), EventFilter{Tables: []string{"hero", "boss"}, Actions: []string{"INSERT", "UPDATE"}})
+	for e := range sub.C {
+		if err := s.applyChange(e); err != nil {
+			klog.Warning(err)
+		}
+	}
+}
+
+// applyChange applies the change of the hero or the boss table to the live
+// sessions.
+func (s *Service) applyChange(e Event) error {
+	if e.Action == resyncAction {
+		return s.resync(context.Background())
+	}
+	switch e.Table {
+	case "hero":
+		_, hero, err := e.heroes()
+		if err != nil || hero == nil {
+			return err
+		}
+		return s.applyHeroChange(*hero)
+
+	case "boss":
+		_, boss, err := e.bosses()
+		// the temporary levels of a reorder are skipped
+		if err != nil || boss == nil || boss.Level <= 0 {
+			return err
+		}
+		return s.applyBossChange(*boss)
+	}
+	return nil
+}
+
+// applyHeroChange applies the changed hero to the live sessions playing it
+// according to the hero update policy.
+func (s *Service) applyHeroChange(hero module.Hero) error {
+	switch s.heroPolicy {
+	case ApplyNever:
+		return nil
+
+	case ApplyOnNextLevel:
+		s.heroLock.Lock()
+		s.pendingHeroes[hero.Name] = hero
+		s.heroLock.Unlock()
+		return nil
+	}
+
+	sessionViews, err := s.sessions.List()
+	if err != nil {
+		return err
+	}
+	for _, sv := range sessionViews {
+		if sv.HeroName != hero.Name {
+			continue
+		}
+		if err = s.applyLive(sv.UID, SessionEvent{Kind: HeroChanged, Hero: &hero}, func(sv *module.SessionView) bool {
+			return sv.HeroName == hero.Name
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyLive records the event of a catalog change on a copy of the live
+// session, if it is still live and matched, and swaps the copy in. The
+// session is locked against the requests of its player in the meantime.
+func (s *Service) applyLive(id string, e SessionEvent, match func(sv *module.SessionView) bool) error {
+	defer s.lockSession(id)()
+
+	sv, err := s.sessions.Get(id)
+	if err == ErrorNotFound {
+		return nil
+	}
+	if err != nil || !match(sv) {
+		return err
+	}
+	updated := *sv
+	if err = s.record(context.Background(), id, &updated, e); err != nil {
+		return err
+	}
+	return s.sessions.Update(id, &updated)
+}
+
+// resync reloads the heros and the bosses, and applies the ones changed
+// since the live sessions loaded them, as their events may have been lost.
+func (s *Service) resync(ctx context.Context) error {
+	heros := make(map[string]module.Hero)
+	rows, err := s.db.QueryContext(ctx, "SELECT name, detail, attackpower, defensepower, blood FROM hero;")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var hero module.Hero
+		if err = rows.Scan(&hero.Name, &hero.Detail, &hero.AttackPower, &hero.DefensePower, &hero.Blood); err != nil {
+			return err
+		}
+		heros[hero.Name] = hero
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	bosses := make(map[string]module.Boss)
+	rows, err = s.db.QueryContext(ctx, "SELECT name, detail, attackpower, defensepower, blood, level FROM boss WHERE level > 0;")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var boss module.Boss
+		if err = rows.Scan(&boss.Name, &boss.Detail, &boss.AttackPower, &boss.DefensePower, &boss.Blood, &boss.Level); err != nil {
+			return err
+		}
+		bosses[boss.Name] = boss
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	sessionViews, err := s.sessions.List()
+	if err != nil {
+		return err
+	}
+	var (
+		changedHeros  = make(map[string]module.Hero)
+		changedBosses = make(map[string]module.Boss)
+	)
+	for _, sv := range sessionViews {
+		if hero, ok := heros[sv.Hero.Name]; ok && hero != sv.Hero {
+			changedHeros[hero.Name] = hero
+		}
+		if boss, ok := bosses[sv.Boss.Name]; ok && boss != sv.Boss {
+			changedBosses[boss.Name] = boss
+		}
+	}
+	for _, hero := range changedHeros {
+		if err = s.applyHeroChange(hero); err != nil {
+			return err
+		}
+	}
+	for _, boss := range changedBosses {
+		if err = s.applyBossChange(boss); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pendingHero returns the hero change waiting for the next level.
+func (s *Service) pendingHero(name string) (module.Hero, bool) {
+	s.heroLock.Lock()
+	defer s.heroLock.Unlock()
+	hero, ok := s.pendingHeroes[name]
+	return hero, ok
+}
+
+// setHero replaces the hero of the session, the live blood is clamped to
+// the blood of the new hero.
+func setHero(sv *module.SessionView, hero module.Hero) {
+	sv.Hero = hero
+	if sv.LiveHeroBlood > hero.Blood {
+		sv.LiveHeroBlood = hero.Blood
+	}
+	if sv.LiveHeroBlood < 0 {
+		sv.LiveHeroBlood = 0
+	}
+}
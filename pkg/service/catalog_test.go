@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+)
+
+func TestApplyHeroChange(t *testing.T) {
+	var (
+		before = module.Hero{Name: "PostgreSql", AttackPower: 50, DefensePower: 30, Blood: 100}
+		after  = module.Hero{Name: "PostgreSql", AttackPower: 60, DefensePower: 36, Blood: 80}
+	)
+
+	for _, policy := range []HeroUpdatePolicy{ApplyImmediately, ApplyOnNextLevel, ApplyNever} {
+		s := &Service{
+			sessions:      NewMemoryStore(),
+			heroPolicy:    policy,
+			pendingHeroes: make(map[string]module.Hero),
+		}
+		s.sessions.Add("1", &module.SessionView{
+			Session: module.Session{UID: "1", HeroName: before.Name, LiveHeroBlood: 90},
+			Hero:    before,
+		})
+
+		if err := s.applyHeroChange(after); err != nil {
+			t.Fatal(err)
+		}
+		sv, _ := s.sessions.Get("1")
+
+		want, wantBlood := after, 80
+		if policy != ApplyImmediately {
+			want, wantBlood = before, 90
+		}
+		if sv.Hero != want || sv.LiveHeroBlood != wantBlood {
+			t.Errorf("%s: want hero '%v' with blood %d, but get: '%v' with blood %d", policy, want, wantBlood, sv.Hero, sv.LiveHeroBlood)
+		}
+
+		// the pending change is applied on the next level
+		if hero, ok := s.pendingHero(sv.HeroName); ok {
+			setHero(sv, hero)
+		}
+		if policy == ApplyOnNextLevel && (sv.Hero != after || sv.LiveHeroBlood != 80) {
+			t.Errorf("%s: want hero '%v' on the next level, but get: '%v'", policy, after, sv.Hero)
+		}
+	}
+}
+
+func TestApplyBossChange(t *testing.T) {
+	var (
+		before = module.Boss{Name: "MySQL", AttackPower: 40, DefensePower: 40, Blood: 100, Level: 2}
+		after  = module.Boss{Name: "MySQL", AttackPower: 40, DefensePower: 40, Blood: 80, Level: 3}
+	)
+
+	s := &Service{sessions: NewMemoryStore()}
+	s.sessions.Add("1", &module.SessionView{
+		Session: module.Session{UID: "1", LiveBossBlood: 90, CurrentLevel: 2},
+		Boss:    before,
+	})
+	s.sessions.Add("2", &module.SessionView{
+		Session: module.Session{UID: "2", LiveBossBlood: 90, CurrentLevel: 1},
+		Boss:    module.Boss{Name: "SQLServer", Blood: 100, Level: 1},
+	})
+
+	if err := s.applyBossChange(after); err != nil {
+		t.Fatal(err)
+	}
+
+	sv, _ := s.sessions.Get("1")
+	if sv.Boss != after || sv.LiveBossBlood != 80 || sv.CurrentLevel != 3 {
+		t.Errorf("want boss '%v' with blood 80 on level 3, but get: '%v' with blood %d on level %d", after, sv.Boss, sv.LiveBossBlood, sv.CurrentLevel)
+	}
+	if sv, _ = s.sessions.Get("2"); sv.Boss.Name != "SQLServer" || sv.CurrentLevel != 1 {
+		t.Errorf("want the other session unchanged, but get: '%v' on level %d", sv.Boss, sv.CurrentLevel)
+	}
+}
+
+func TestResync(t *testing.T) {
+	db, f := newFakeDB(t)
+	s := &Service{db: db, sessions: NewMemoryStore(), heroPolicy: ApplyImmediately}
+	hero := module.Hero{Name: "PostgreSql", Detail: "master of opensource", AttackPower: 50, DefensePower: 30, Blood: 100}
+	boss := module.Boss{Name: "MySQL", Detail: "the leader", AttackPower: 40, DefensePower: 40, Blood: 100, Level: 2}
+	s.sessions.Add("1", &module.SessionView{
+		Session: module.Session{UID: "1", HeroName: hero.Name, LiveHeroBlood: 100, LiveBossBlood: 100, CurrentLevel: 2},
+		Hero:    hero,
+		Boss:    boss,
+	})
+
+	// the hero changed while the listener was down
+	f.expect("FROM hero").returns([]string{"name", "detail", "attackpower", "defensepower", "blood"},
+		[]interface{}{"PostgreSql", "master of opensource", 60, 30, 80})
+	f.expect("FROM boss").returns([]string{"name", "detail", "attackpower", "defensepower", "blood", "level"},
+		[]interface{}{"MySQL", "the leader", 40, 40, 100, 2})
+	if err := s.applyChange(Event{Action: resyncAction}); err != nil {
+		t.Fatal(err)
+	}
+
+	sv, _ := s.sessions.Get("1")
+	if sv.Hero.AttackPower != 60 || sv.LiveHeroBlood != 80 || sv.Boss != boss {
+		t.Errorf("want the changed hero applied and the boss unchanged, but get '%v' with blood %d, '%v'", sv.Hero, sv.LiveHeroBlood, sv.Boss)
+	}
+}
+
+// TestGameWithCatalogChanges is meant to run with -race: the catalog
+// changes and the fight turns of a session are serialized.
+func TestGameWithCatalogChanges(t *testing.T) {
+	s := &Service{sessions: NewMemoryStore(), heroPolicy: ApplyImmediately}
+	hero := module.Hero{Name: "PostgreSql", AttackPower: 50, DefensePower: 30, Blood: 1000000}
+	s.sessions.Add("1", &module.SessionView{
+		Session: module.Session{UID: "1", HeroName: hero.Name, LiveHeroBlood: hero.Blood, LiveBossBlood: 1000000000, CurrentLevel: 1},
+		Hero:    hero,
+		Boss:    module.Boss{Name: "SQLServer", AttackPower: 1, DefensePower: 1, Blood: 1000000000, Level: 1},
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			if _, err := s.Game(context.Background(), &fight.GameRequest{Id: "1", Type: fight.Type_FIGHT}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			changed := hero
+			changed.AttackPower += i
+			if err := s.applyHeroChange(changed); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+
+	if sv, _ := s.sessions.Get("1"); sv.Hero.AttackPower != hero.AttackPower+999 {
+		t.Errorf("want the last hero change applied, but get '%v'", sv.Hero)
+	}
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog"
+)
+
+// eventsChannel is the channel notified by the notify_event trigger.
+const eventsChannel = "events"
+
+// pingInterval is the idle time after which the connection of the
+// listener is checked, so that a dead connection is re-established.
+const pingInterval = 90 * time.Second
+
+// resyncAction is the action of the event sent to every subscription
+// after the listener reconnected, the changes in the meantime are lost and
+// must be reloaded.
+const resyncAction = "RESYNC"
+
+// subscriptionBuffer is the number of the events buffered for a slow
+// subscriber, the later events are dropped.
+const subscriptionBuffer = 1024
+
+// EventFilter selects the events of a subscription by table and action,
+// an empty list selects all of them.
+type EventFilter struct {
+	Tables  []string
+	Actions []string
+}
+
+// NewEventFilter validates the tables and the actions of the filter, the
+// names are case insensitive.
+func NewEventFilter(tables, actions []string) (EventFilter, error) {
+	var filter EventFilter
+	for _, table := range tables {
+		table = strings.ToLower(table)
+		switch table {
+		case "hero", "boss", "session":
+		default:
+			return filter, status.Errorf(codes.InvalidArgument, "undefined event table: '%s'", table)
+		}
+		filter.Tables = append(filter.Tables, table)
+	}
+	for _, action := range actions {
+		action = strings.ToUpper(action)
+		switch action {
+		case "INSERT", "UPDATE", "DELETE":
+		default:
+			return filter, status.Errorf(codes.InvalidArgument, "undefined event action: '%s'", action)
+		}
+		filter.Actions = append(filter.Actions, action)
+	}
+	return filter, nil
+}
+
+// match reports whether the filter selects the event, the resync events
+// are selected by every filter.
+func (f EventFilter) match(e Event) bool {
+	if e.Action == resyncAction {
+		return true
+	}
+	return matchAny(f.Tables, e.Table) && matchAny(f.Actions, e.Action)
+}
+
+func matchAny(names []string, name string) bool {
+	if len(names) == 0 {
+		return true
+	}
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Dispatcher owns the listener of the events channel, and fans the decoded
+// events out to every subscription. A nil *Dispatcher sends no event.
+type Dispatcher struct {
+	notify <-chan *pq.Notification
+	listen func(channel string) error
+	ping   func() error
+
+	lock          sync.Mutex
+	subscriptions map[*Subscription]struct{}
+	stopped       bool
+	stop          chan struct{}
+}
+
+// Subscription receives the events selected by its filter on C, which is
+// closed when the subscription ends.
+type Subscription struct {
+	C <-chan Event
+
+	events chan Event
+	filter EventFilter
+}
+
+// NewDispatcher creates the dispatcher of the listener.
+func NewDispatcher(listener *pq.Listener) *Dispatcher {
+	if listener == nil {
+		return nil
+	}
+	return &Dispatcher{
+		notify:        listener.Notify,
+		listen:        listener.Listen,
+		ping:          listener.Ping,
+		subscriptions: make(map[*Subscription]struct{}),
+		stop:          make(chan struct{}),
+	}
+}
+
+// Subscribe subscribes to the events selected by the filter until the
+// context ends or the dispatcher stops.
+func (d *Dispatcher) Subscribe(ctx context.Context, filter EventFilter) *Subscription {
+	events := make(chan Event, subscriptionBuffer)
+	sub := &Subscription{C: events, events: events, filter: filter}
+	if d == nil {
+		go func() {
+			<-ctx.Done()
+			close(events)
+		}()
+		return sub
+	}
+
+	d.lock.Lock()
+	if d.stopped {
+		close(events)
+	} else {
+		d.subscriptions[sub] = struct{}{}
+	}
+	d.lock.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-d.stop:
+		}
+		d.unsubscribe(sub)
+	}()
+	return sub
+}
+
+// SetFilter replaces the filter of the subscription.
+func (d *Dispatcher) SetFilter(sub *Subscription, filter EventFilter) {
+	if d == nil {
+		return
+	}
+	d.lock.Lock()
+	sub.filter = filter
+	d.lock.Unlock()
+}
+
+func (d *Dispatcher) unsubscribe(sub *Subscription) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if _, ok := d.subscriptions[sub]; ok {
+		delete(d.subscriptions, sub)
+		close(sub.events)
+	}
+}
+
+// Run listens to the events channel and dispatches the events until Stop
+// is called.
+func (d *Dispatcher) Run() {
+	if d == nil {
+		return
+	}
+	if err := d.listen(eventsChannel); err != nil && err != pq.ErrChannelAlreadyOpen {
+		klog.Warning(err)
+	}
+
+	timer := time.NewTimer(pingInterval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-d.stop:
+			return
+
+		case notification, ok := <-d.notify:
+			if !ok {
+				return
+			}
+			// nil is sent after the connection is re-established, the
+			// notifications sent in the meantime are lost
+			if notification == nil {
+				klog.Warning("events listener reconnected, resyncing the subscriptions")
+				d.dispatch(Event{Action: resyncAction})
+				break
+			}
+
+			e, err := decodeEvent(notification.Extra)
+			if err != nil {
+				klog.Warning(err)
+				continue
+			}
+			d.dispatch(e)
+
+		case <-timer.C:
+			go func() {
+				if err := d.ping(); err != nil {
+					klog.Warningf("events listener: %v", err)
+				}
+			}()
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(pingInterval)
+	}
+}
+
+// dispatch sends the event to the subscriptions selecting it, a full
+// subscription drops it.
+func (d *Dispatcher) dispatch(e Event) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for sub := range d.subscriptions {
+		if !sub.filter.match(e) {
+			continue
+		}
+		select {
+		case sub.events <- e:
+		default:
+			klog.Warningf("event dropped: %s %s", e.Table, e.Action)
+		}
+	}
+}
+
+// Stop stops dispatching, and ends every subscription.
+func (d *Dispatcher) Stop() {
+	if d == nil {
+		return
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if !d.stopped {
+		d.stopped = true
+		close(d.stop)
+	}
+}
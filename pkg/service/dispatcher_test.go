@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestDispatcher() (*Dispatcher, chan *pq.Notification) {
+	notify := make(chan *pq.Notification)
+	return &Dispatcher{
+		notify:        notify,
+		listen:        func(string) error { return nil },
+		ping:          func() error { return nil },
+		subscriptions: make(map[*Subscription]struct{}),
+		stop:          make(chan struct{}),
+	}, notify
+}
+
+func receive(t *testing.T, sub *Subscription) (Event, bool) {
+	select {
+	case e, ok := <-sub.C:
+		return e, ok
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+		return Event{}, false
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	d, notify := newTestDispatcher()
+	go d.Run()
+	defer d.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var (
+		all    = d.Subscribe(context.Background(), EventFilter{})
+		bosses = d.Subscribe(ctx, EventFilter{Tables: []string{"boss"}, Actions: []string{"UPDATE"}})
+	)
+
+	notify <- &pq.Notification{Extra: `{"table": "hero", "action": "INSERT", "old": null, "new": {"name": "PostgreSql"}}`}
+	// a reconnect resyncs every subscription, an undefined table is skipped
+	notify <- nil
+	notify <- &pq.Notification{Extra: `{"table": "player", "action": "UPDATE", "new": {}}`}
+	notify <- &pq.Notification{Extra: `{"table": "boss", "action": "UPDATE", "old": {"name": "MySQL", "level": 3}, "new": {"name": "MySQL", "level": 2}}`}
+
+	if e, _ := receive(t, all); e.Table != "hero" {
+		t.Errorf("want the hero event, but get '%+v'", e)
+	}
+	for _, sub := range []*Subscription{all, bosses} {
+		if e, _ := receive(t, sub); e.Action != resyncAction {
+			t.Errorf("want the resync event, but get '%+v'", e)
+		}
+	}
+	if e, _ := receive(t, all); e.Table != "boss" {
+		t.Errorf("want the boss event, but get '%+v'", e)
+	}
+	if e, _ := receive(t, bosses); e.Table != "boss" {
+		t.Errorf("want the boss event only, but get '%+v'", e)
+	}
+
+	// the subscription ends with its context
+	cancel()
+	if e, ok := receive(t, bosses); ok {
+		t.Errorf("want the subscription closed, but get '%+v'", e)
+	}
+
+	// and every subscription ends with the dispatcher
+	d.Stop()
+	if e, ok := receive(t, all); ok {
+		t.Errorf("want the subscription closed, but get '%+v'", e)
+	}
+	if _, ok := receive(t, d.Subscribe(context.Background(), EventFilter{})); ok {
+		t.Error("want the subscription of a stopped dispatcher closed")
+	}
+}
+
+func TestNewEventFilter(t *testing.T) {
+	filter, err := NewEventFilter([]string{"Hero"}, []string{"delete"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !filter.match(Event{Table: "hero", Action: "DELETE"}) || filter.match(Event{Table: "hero", Action: "UPDATE"}) || filter.match(Event{Table: "boss", Action: "DELETE"}) {
+		t.Errorf("want hero deletes only, but get '%+v'", filter)
+	}
+
+	if _, err = NewEventFilter([]string{"player"}, nil); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("want InvalidArgument for an undefined table, but get '%v'", err)
+	}
+	if _, err = NewEventFilter(nil, []string{"TRUNCATE"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("want InvalidArgument for an undefined action, but get '%v'", err)
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+)
+
+// Event is a change of a row of the hero, boss or session table notified
+// by the notify_event trigger. Old is null for an INSERT and New for a
+// DELETE, they are decoded according to the table.
+type Event struct {
+	Table  string          `json:"table"`
+	Action string          `json:"action"`
+	Old    json.RawMessage `json:"old"`
+	New    json.RawMessage `json:"new"`
+}
+
+// decodeEvent decodes the notification of the notify_event trigger.
+func decodeEvent(payload string) (Event, error) {
+	var e Event
+	if err := json.Unmarshal([]byte(payload), &e); err != nil {
+		return Event{}, err
+	}
+	switch e.Table {
+	case "hero", "boss", "session":
+		return e, nil
+	default:
+		return Event{}, fmt.Errorf("undefined event table: '%s'", e.Table)
+	}
+}
+
+// decodeRow decodes the row into v, a pointer to a pointer left nil for a
+// null row.
+func decodeRow(row json.RawMessage, v interface{}) error {
+	if len(row) == 0 {
+		return nil
+	}
+	return json.Unmarshal(row, v)
+}
+
+// heroes returns the old and the new row of a change of the hero table.
+func (e Event) heroes() (before, after *module.Hero, err error) {
+	if err = decodeRow(e.Old, &before); err != nil {
+		return nil, nil, err
+	}
+	err = decodeRow(e.New, &after)
+	return before, after, err
+}
+
+// bosses returns the old and the new row of a change of the boss table.
+func (e Event) bosses() (before, after *module.Boss, err error) {
+	if err = decodeRow(e.Old, &before); err != nil {
+		return nil, nil, err
+	}
+	err = decodeRow(e.New, &after)
+	return before, after, err
+}
+
+// sessionRow is the json form of a row of the session table.
+type sessionRow struct {
+	UID          string `json:"uid"`
+	HeroName     string `json:"heroname"`
+	HeroBlood    int    `json:"heroblood"`
+	BossBlood    int    `json:"bossblood"`
+	CurrentLevel int    `json:"currentlevel"`
+	Score        int    `json:"score"`
+	// a timestamp without time zone
+	ArchiveDate string `json:"archivedate"`
+}
+
+func (row *sessionRow) session() *module.Session {
+	if row == nil {
+		return nil
+	}
+	// the timestamps of row_to_json have no time zone
+	archiveDate, _ := time.Parse("2006-01-02T15:04:05.999999999", row.ArchiveDate)
+	return &module.Session{
+		UID:           row.UID,
+		HeroName:      row.HeroName,
+		LiveHeroBlood: row.HeroBlood,
+		LiveBossBlood: row.BossBlood,
+		CurrentLevel:  row.CurrentLevel,
+		Score:         row.Score,
+		ArchiveDate:   archiveDate,
+	}
+}
+
+// sessions returns the old and the new row of a change of the session
+// table.
+func (e Event) sessions() (*module.Session, *module.Session, error) {
+	var before, after *sessionRow
+	if err := decodeRow(e.Old, &before); err != nil {
+		return nil, nil, err
+	}
+	if err := decodeRow(e.New, &after); err != nil {
+		return nil, nil, err
+	}
+	return before.session(), after.session(), nil
+}
+
+// convertEvent2AdminResponse returns the admin response of the change.
+func convertEvent2AdminResponse(e Event) (*fight.AdminResponse, error) {
+	resp := &fight.AdminResponse{}
+	if e.Action == resyncAction {
+		resp.Resync = true
+		return resp, nil
+	}
+	switch e.Table {
+	case "hero":
+		before, after, err := e.heroes()
+		if err != nil {
+			return nil, err
+		}
+		change := &fight.HeroChange{Action: e.Action}
+		if before != nil {
+			change.Old = convertModuleHero2FightHero(*before)
+			resp.Heros = []*fight.Hero{change.Old}
+		}
+		if after != nil {
+			change.New = convertModuleHero2FightHero(*after)
+			resp.Heros = []*fight.Hero{change.New}
+		}
+		resp.Change = &fight.AdminResponse_Hero{Hero: change}
+
+	case "boss":
+		before, after, err := e.bosses()
+		if err != nil {
+			return nil, err
+		}
+		change := &fight.BossChange{Action: e.Action}
+		if before != nil {
+			change.Old = convertModuleBoss2FightBoss(*before)
+			resp.Bosses = []*fight.Boss{change.Old}
+		}
+		if after != nil {
+			change.New = convertModuleBoss2FightBoss(*after)
+			resp.Bosses = []*fight.Boss{change.New}
+		}
+		resp.Change = &fight.AdminResponse_Boss{Boss: change}
+
+	case "session":
+		before, after, err := e.sessions()
+		if err != nil {
+			return nil, err
+		}
+		change := &fight.SessionChange{Action: e.Action}
+		if before != nil {
+			change.Old = convertModuleSession2FightSession(*before)
+		}
+		if after != nil {
+			change.New = convertModuleSession2FightSession(*after)
+		}
+		resp.Change = &fight.AdminResponse_Session{Session: change}
+	}
+	return resp, nil
+}
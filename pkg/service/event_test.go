@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+)
+
+func TestDecodeEvent(t *testing.T) {
+	e, err := decodeEvent(`{"table": "boss", "action": "UPDATE", "old": {"name": "MySQL", "detail": "The leader", "attackpower": 40, "defensepower": 30, "blood": 100, "level": 2}, "new": {"name": "MySQL", "detail": "The leader", "attackpower": 40, "defensepower": 30, "blood": 100, "level": 3}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, after, err := e.bosses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := module.Boss{Name: "MySQL", Detail: "The leader", AttackPower: 40, DefensePower: 30, Blood: 100, Level: 2}
+	if before == nil || *before != want {
+		t.Errorf("want old boss '%v', but get: '%v'", want, before)
+	}
+	if want.Level = 3; after == nil || *after != want {
+		t.Errorf("want new boss '%v', but get: '%v'", want, after)
+	}
+
+	e, err = decodeEvent(`{"table": "hero", "action": "DELETE", "old": {"name": "PostgreSql", "detail": "The master", "attackpower": 50, "defensepower": 30, "blood": 100}, "new": null}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before, after, err := e.heroes(); err != nil || before == nil || before.Name != "PostgreSql" || before.AttackPower != 50 || after != nil {
+		t.Errorf("want the deleted hero 'PostgreSql', but get: '%v', '%v': %v", before, after, err)
+	}
+
+	e, err = decodeEvent(`{"table": "session", "action": "INSERT", "old": null, "new": {"uid": "1", "heroname": null, "heroblood": 90, "bossblood": 80, "currentlevel": 2, "score": 30, "archivedate": "2021-03-11T10:20:30.123456"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldSession, newSession, err := e.sessions()
+	wantSession := module.Session{UID: "1", LiveHeroBlood: 90, LiveBossBlood: 80, CurrentLevel: 2, Score: 30, ArchiveDate: time.Date(2021, 3, 11, 10, 20, 30, 123456000, time.UTC)}
+	if err != nil || oldSession != nil || newSession == nil || *newSession != wantSession {
+		t.Errorf("want the new session '%v', but get: '%v', '%v': %v", wantSession, oldSession, newSession, err)
+	}
+
+	if _, err = decodeEvent(`{"table": "player", "action": "INSERT", "new": {}}`); err == nil {
+		t.Error("want an error for an undefined table")
+	}
+}
+
+func TestConvertEvent2AdminResponse(t *testing.T) {
+	e, err := decodeEvent(`{"table": "hero", "action": "UPDATE", "old": {"name": "PostgreSql", "attackpower": 50}, "new": {"name": "PostgreSql", "attackpower": 60}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := convertEvent2AdminResponse(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	change := resp.GetHero()
+	if change.GetAction() != "UPDATE" || change.GetOld().GetAttackPower() != 50 || change.GetNew().GetAttackPower() != 60 {
+		t.Errorf("want the hero change from 50 to 60, but get: '%v'", change)
+	}
+	if len(resp.GetHeros()) != 1 || resp.GetHeros()[0].GetAttackPower() != 60 {
+		t.Errorf("want the new hero in heros, but get: '%v'", resp.GetHeros())
+	}
+}
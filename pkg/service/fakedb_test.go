@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDB scripts the statements of a test: every statement, including the
+// BEGIN, COMMIT and ROLLBACK of the transactions, must match the next
+// expectation, and gets its rows or its result.
+type fakeDB struct {
+	t *testing.T
+
+	lock    sync.Mutex
+	expects []*fakeExpect
+}
+
+type fakeExpect struct {
+	// a fragment of the statement
+	query string
+	// the arguments, checked if not nil
+	args     []driver.Value
+	columns  []string
+	rows     [][]driver.Value
+	affected int64
+	err      error
+}
+
+var (
+	fakeDriverOnce sync.Once
+	fakeDBs        sync.Map
+)
+
+// newFakeDB returns the db of the script, the script must be done by the
+// end of the test.
+func newFakeDB(t *testing.T) (*sql.DB, *fakeDB) {
+	fakeDriverOnce.Do(func() {
+		sql.Register("fake", fakeDriver{})
+	})
+
+	f := &fakeDB{t: t}
+	name := fmt.Sprintf("%s/%p", t.Name(), f)
+	fakeDBs.Store(name, f)
+	db, err := sql.Open("fake", name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+
+	t.Cleanup(func() {
+		db.Close()
+		fakeDBs.Delete(name)
+		f.lock.Lock()
+		defer f.lock.Unlock()
+		for _, e := range f.expects {
+			t.Errorf("want the statement '%s', but get none", e.query)
+		}
+	})
+	return db, f
+}
+
+// expect appends the statement to the script, the arguments are checked if
+// any is given.
+func (f *fakeDB) expect(query string, args ...interface{}) *fakeExpect {
+	e := &fakeExpect{query: query}
+	for _, arg := range args {
+		v, err := driver.DefaultParameterConverter.ConvertValue(arg)
+		if err != nil {
+			f.t.Fatal(err)
+		}
+		e.args = append(e.args, v)
+	}
+
+	f.lock.Lock()
+	f.expects = append(f.expects, e)
+	f.lock.Unlock()
+	return e
+}
+
+// returns sets the columns and the rows returned by the statement.
+func (e *fakeExpect) returns(columns []string, rows ...[]interface{}) *fakeExpect {
+	e.columns = columns
+	for _, row := range rows {
+		values := make([]driver.Value, len(row))
+		for i, v := range row {
+			value, err := driver.DefaultParameterConverter.ConvertValue(v)
+			if err != nil {
+				panic(err)
+			}
+			values[i] = value
+		}
+		e.rows = append(e.rows, values)
+	}
+	return e
+}
+
+// affects sets the number of the rows affected by the statement.
+func (e *fakeExpect) affects(n int64) *fakeExpect {
+	e.affected = n
+	return e
+}
+
+// fails makes the statement fail with the error.
+func (e *fakeExpect) fails(err error) *fakeExpect {
+	e.err = err
+	return e
+}
+
+// next checks the statement against the next expectation.
+func (f *fakeDB) next(query string, args []driver.NamedValue) (*fakeExpect, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if len(f.expects) == 0 {
+		f.t.Errorf("want no more statement, but get '%s'", query)
+		return nil, errors.New("unexpected statement")
+	}
+	e := f.expects[0]
+	f.expects = f.expects[1:]
+
+	if !strings.Contains(query, e.query) {
+		f.t.Errorf("want the statement '%s', but get '%s'", e.query, query)
+		return nil, errors.New("unexpected statement")
+	}
+	if e.args != nil {
+		values := make([]driver.Value, len(args))
+		for i, arg := range args {
+			values[i] = arg.Value
+		}
+		if !reflect.DeepEqual(values, e.args) {
+			f.t.Errorf("want the arguments %v of '%s', but get %v", e.args, e.query, values)
+			return nil, errors.New("unexpected arguments")
+		}
+	}
+	return e, e.err
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	f, ok := fakeDBs.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("undefined fake db: '%s'", name)
+	}
+	return &fakeConn{db: f.(*fakeDB)}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if _, err := c.db.next("BEGIN", nil); err != nil {
+		return nil, err
+	}
+	return fakeTx{db: c.db}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, err := c.db.next(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(e.affected), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	e, err := c.db.next(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeRows{columns: e.columns, rows: e.rows}, nil
+}
+
+type fakeTx struct {
+	db *fakeDB
+}
+
+func (tx fakeTx) Commit() error {
+	_, err := tx.db.next("COMMIT", nil)
+	return err
+}
+
+func (tx fakeTx) Rollback() error {
+	_, err := tx.db.next("ROLLBACK", nil)
+	return err
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,389 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// the status of the friendship table
+const (
+	friendPending  = "pending"
+	friendAccepted = "accepted"
+)
+
+// maxFriends is the number of the friends and of the outgoing requests of
+// a player, the incoming requests are not counted.
+const maxFriends = 500
+
+// Friends keeps the friend requests and the friendships in the friendship
+// table, a row is requested by the requester and accepted by the addressee.
+type Friends struct {
+	db *sql.DB
+}
+
+// NewFriends ...
+func NewFriends(db *sql.DB) *Friends {
+	return &Friends{db: db}
+}
+
+// Request sends a friend request to the friend, the request is accepted at
+// once if the friend requested the player already. The request counts
+// against the limit of the player, and the friend must have room for one
+// more friend.
+func (fs *Friends) Request(ctx context.Context, uid, friend string) (fight.Friend_Status, error) {
+	if uid == friend {
+		return fight.Friend_PENDING, status.Error(codes.InvalidArgument, "players can not befriend themselves")
+	}
+
+	tx, err := fs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fight.Friend_PENDING, err
+	}
+	defer tx.Rollback()
+
+	if err = lockPlayers(ctx, tx, uid, friend); err != nil {
+		return fight.Friend_PENDING, err
+	}
+	if err = checkFriends(ctx, tx, uid, "at most %d friends and requests"); err != nil {
+		return fight.Friend_PENDING, err
+	}
+
+	accepted, err := accept(ctx, tx, uid, friend)
+	if err != nil {
+		return fight.Friend_PENDING, err
+	}
+	if accepted {
+		return fight.Friend_ACCEPTED, tx.Commit()
+	}
+
+	if err = checkFriends(ctx, tx, friend, "the friend has at most %d friends and requests"); err != nil {
+		return fight.Friend_PENDING, err
+	}
+	sqlStatement := `INSERT INTO friendship(requester, addressee, status, createdat) SELECT $1, $2, $3, now() WHERE NOT EXISTS (SELECT 1 FROM friendship WHERE requester = $2 AND addressee = $1) ON conflict (requester, addressee) DO NOTHING;`
+	result, err := tx.ExecContext(ctx, sqlStatement, uid, friend, friendPending)
+	if err != nil {
+		return fight.Friend_PENDING, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fight.Friend_PENDING, err
+	}
+	if n == 0 {
+		return fight.Friend_PENDING, status.Error(codes.AlreadyExists, "friend request already exists")
+	}
+	return fight.Friend_PENDING, tx.Commit()
+}
+
+// Accept accepts the pending friend request of the friend, if the player
+// has room for one more friend.
+func (fs *Friends) Accept(ctx context.Context, uid, friend string) error {
+	tx, err := fs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = lockPlayers(ctx, tx, uid, friend); err != nil {
+		return err
+	}
+	if err = checkFriends(ctx, tx, uid, "at most %d friends and requests"); err != nil {
+		return err
+	}
+	accepted, err := accept(ctx, tx, uid, friend)
+	if err != nil {
+		return err
+	}
+	if !accepted {
+		return status.Error(codes.NotFound, "no pending friend request")
+	}
+	return tx.Commit()
+}
+
+func accept(ctx context.Context, tx *sql.Tx, uid, friend string) (bool, error) {
+	sqlStatement := `UPDATE friendship SET status = $3, acceptedat = now() WHERE requester = $2 AND addressee = $1 AND status = $4;`
+	result, err := tx.ExecContext(ctx, sqlStatement, uid, friend, friendAccepted, friendPending)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// lockPlayers serializes the friend requests of the players until the end
+// of the transaction, the locks are taken in order against deadlocks.
+func lockPlayers(ctx context.Context, tx *sql.Tx, uids ...string) error {
+	sorted := append([]string(nil), uids...)
+	sort.Strings(sorted)
+	for _, uid := range sorted {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtext($1));", uid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkFriends fails with ResourceExhausted if the friends and the outgoing
+// requests of the player reach maxFriends.
+func checkFriends(ctx context.Context, tx *sql.Tx, uid, format string) error {
+	var count int
+	sqlStatement := `SELECT count(*) FROM friendship WHERE status = $2 AND (requester = $1 OR addressee = $1) OR status = $3 AND requester = $1;`
+	if err := tx.QueryRowContext(ctx, sqlStatement, uid, friendAccepted, friendPending).Scan(&count); err != nil {
+		return err
+	}
+	if count >= maxFriends {
+		return status.Errorf(codes.ResourceExhausted, format, maxFriends)
+	}
+	return nil
+}
+
+// Decline declines the pending friend request of the friend.
+func (fs *Friends) Decline(ctx context.Context, uid, friend string) error {
+	result, err := fs.db.ExecContext(ctx, "DELETE FROM friendship WHERE requester = $2 AND addressee = $1 AND status = $3;", uid, friend, friendPending)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return status.Error(codes.NotFound, "no pending friend request")
+	}
+	return nil
+}
+
+// List returns the friends and the pending requests of the player, the
+// players without a profile are left out.
+func (fs *Friends) List(ctx context.Context, uid string) ([]*fight.Friend, error) {
+	sqlStatement := `SELECT p.displayname, f.status, f.requester <> $1, coalesce(f.acceptedat, f.createdat) FROM friendship f JOIN player p ON p.uid = CASE WHEN f.requester = $1 THEN f.addressee ELSE f.requester END WHERE f.requester = $1 OR f.addressee = $1 ORDER BY p.displayname;`
+	rows, err := fs.db.QueryContext(ctx, sqlStatement, uid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var friends []*fight.Friend
+	for rows.Next() {
+		var (
+			friend     = &fight.Friend{}
+			friendship string
+			since      time.Time
+		)
+		if err = rows.Scan(&friend.DisplayName, &friendship, &friend.Incoming, &since); err != nil {
+			return nil, err
+		}
+		if friendship == friendAccepted {
+			friend.Status = fight.Friend_ACCEPTED
+		}
+		friend.Since = timestamppb.New(since)
+		friends = append(friends, friend)
+	}
+	return friends, rows.Err()
+}
+
+// ids returns the ids of the accepted friends of the player.
+func (fs *Friends) ids(ctx context.Context, uid string) ([]string, error) {
+	sqlStatement := `SELECT CASE WHEN requester = $1 THEN addressee ELSE requester END FROM friendship WHERE (requester = $1 OR addressee = $1) AND status = $2;`
+	rows, err := fs.db.QueryContext(ctx, sqlStatement, uid, friendAccepted)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// friendsBoard is a ranking small enough to be kept in a slice.
+type friendsBoard []Player
+
+// newFriendsBoard ranks the players.
+func newFriendsBoard(players []Player) friendsBoard {
+	sort.Slice(players, func(i, j int) bool {
+		return rankLess(players[i], players[j])
+	})
+	for i := range players {
+		players[i].Rank = i + 1
+	}
+	return friendsBoard(players)
+}
+
+func (fb friendsBoard) Page(ctx context.Context, offset, limit int) ([]Player, int, error) {
+	if offset >= len(fb) {
+		return nil, len(fb), nil
+	}
+	end := offset + limit
+	if end > len(fb) {
+		end = len(fb)
+	}
+	players := make([]Player, end-offset)
+	copy(players, fb[offset:end])
+	return players, len(fb), nil
+}
+
+func (fb friendsBoard) Rank(ctx context.Context, id string) (Player, error) {
+	for _, p := range fb {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return Player{}, ErrorNotFound
+}
+
+// friendsScores returns the scores of the players: the live ones for the
+// online sessions, the archived ones from the session table otherwise.
+func (s *Service) friendsScores(ctx context.Context, ids []string) ([]Player, error) {
+	var (
+		players []Player
+		offline []string
+	)
+	for _, id := range ids {
+		sv, err := s.sessions.Get(id)
+		if err == ErrorNotFound {
+			offline = append(offline, id)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		players = append(players, Player{
+			ID:             id,
+			Score:          sv.Score,
+			Level:          sv.CurrentLevel,
+			ScoreUpdatedAt: sv.ScoreUpdatedAt,
+		})
+	}
+	if len(offline) == 0 {
+		return players, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, "SELECT uid, coalesce(score, 0), coalesce(currentlevel, 1), coalesce(archivedate, now()) FROM session WHERE uid = ANY($1);", pq.Array(offline))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p Player
+		if err = rows.Scan(&p.ID, &p.Score, &p.Level, &p.ScoreUpdatedAt); err != nil {
+			return nil, err
+		}
+		players = append(players, p)
+	}
+	return players, rows.Err()
+}
+
+// RequestFriend ...
+func (s *Service) RequestFriend(ctx context.Context, req *fight.FriendRequest) (*fight.Friend, error) {
+	friend, err := s.friendOf(ctx, req)
+	if err != nil {
+		return &fight.Friend{}, err
+	}
+	st, err := s.friends.Request(ctx, req.GetId(), friend)
+	if err != nil {
+		return &fight.Friend{}, err
+	}
+	return &fight.Friend{
+		DisplayName: req.GetDisplayName(),
+		Status:      st,
+		Since:       timestamppb.Now(),
+	}, nil
+}
+
+// AcceptFriend ...
+func (s *Service) AcceptFriend(ctx context.Context, req *fight.FriendRequest) (*fight.Friend, error) {
+	friend, err := s.friendOf(ctx, req)
+	if err != nil {
+		return &fight.Friend{}, err
+	}
+	if err = s.friends.Accept(ctx, req.GetId(), friend); err != nil {
+		return &fight.Friend{}, err
+	}
+	return &fight.Friend{
+		DisplayName: req.GetDisplayName(),
+		Status:      fight.Friend_ACCEPTED,
+		Incoming:    true,
+		Since:       timestamppb.Now(),
+	}, nil
+}
+
+// DeclineFriend ...
+func (s *Service) DeclineFriend(ctx context.Context, req *fight.FriendRequest) (*fight.Friend, error) {
+	friend, err := s.friendOf(ctx, req)
+	if err != nil {
+		return &fight.Friend{}, err
+	}
+	if err = s.friends.Decline(ctx, req.GetId(), friend); err != nil {
+		return &fight.Friend{}, err
+	}
+	return &fight.Friend{
+		DisplayName: req.GetDisplayName(),
+		Status:      fight.Friend_DECLINED,
+		Incoming:    true,
+		Since:       timestamppb.Now(),
+	}, nil
+}
+
+// ListFriends ...
+func (s *Service) ListFriends(ctx context.Context, req *fight.ListFriendsRequest) (*fight.ListFriendsResponse, error) {
+	if _, err := s.authorize(ctx, req.GetId()); err != nil {
+		return &fight.ListFriendsResponse{}, err
+	}
+	friends, err := s.friends.List(ctx, req.GetId())
+	if err != nil {
+		return &fight.ListFriendsResponse{}, err
+	}
+	return &fight.ListFriendsResponse{Friends: friends}, nil
+}
+
+// FriendsLeaderboard ...
+func (s *Service) FriendsLeaderboard(ctx context.Context, req *fight.FriendsLeaderboardRequest) (*fight.LeaderboardResponse, error) {
+	var id = req.GetId()
+	if _, err := s.authorize(ctx, id); err != nil {
+		return &fight.LeaderboardResponse{}, err
+	}
+	page, err := newLeaderboardPage(req.GetLimit(), req.GetOffset(), req.GetNeighbours(), id)
+	if err != nil {
+		return &fight.LeaderboardResponse{}, err
+	}
+
+	ids, err := s.friends.ids(ctx, id)
+	if err != nil {
+		return &fight.LeaderboardResponse{}, err
+	}
+	players, err := s.friendsScores(ctx, append(ids, id))
+	if err != nil {
+		return &fight.LeaderboardResponse{}, err
+	}
+
+	resp, err := s.listBoard(ctx, newFriendsBoard(players), page)
+	if err != nil {
+		return &fight.LeaderboardResponse{}, err
+	}
+	return resp, nil
+}
+
+// friendOf authorizes the request, and returns the id of the friend.
+func (s *Service) friendOf(ctx context.Context, req *fight.FriendRequest) (string, error) {
+	if _, err := s.authorize(ctx, req.GetId()); err != nil {
+		return "", err
+	}
+	if req.GetId() == "" || req.GetDisplayName() == "" {
+		return "", status.Error(codes.InvalidArgument, "id and display name of the friend are required")
+	}
+	return s.profiles.lookup(ctx, req.GetDisplayName())
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFriendsBoard(t *testing.T) {
+	fb := newFriendsBoard([]Player{
+		{ID: "1", Score: 10, Level: 1},
+		{ID: "2", Score: 30, Level: 2},
+		{ID: "3", Score: 10, Level: 2},
+		{ID: "4", Score: 20, Level: 1},
+	})
+
+	players, total, err := fb.Page(context.Background(), 1, 2)
+	if err != nil || total != 4 || len(players) != 2 {
+		t.Fatalf("want 2 of 4 players, but get %d of %d: '%v'", len(players), total, err)
+	}
+	if players[0].ID != "4" || players[0].Rank != 2 || players[1].ID != "3" || players[1].Rank != 3 {
+		t.Errorf("want players '4' and '3', but get: '%v'", players)
+	}
+
+	if players, _, _ = fb.Page(context.Background(), 4, 2); len(players) != 0 {
+		t.Errorf("want no player after the last one, but get: '%v'", players)
+	}
+
+	p, err := fb.Rank(context.Background(), "1")
+	if err != nil || p.Rank != 4 {
+		t.Errorf("want player '1' ranked 4th, but get: '%v', '%v'", p, err)
+	}
+	if _, err = fb.Rank(context.Background(), "5"); err != ErrorNotFound {
+		t.Errorf("want ErrorNotFound, but get: '%v'", err)
+	}
+}
+
+func TestRequestFriend(t *testing.T) {
+	db, f := newFakeDB(t)
+	fs := NewFriends(db)
+	count := []string{"count"}
+
+	// the locks are taken in order
+	f.expect("BEGIN")
+	f.expect("pg_advisory_xact_lock", "1")
+	f.expect("pg_advisory_xact_lock", "2")
+	f.expect("SELECT count(*) FROM friendship", "2", friendAccepted, friendPending).returns(count, []interface{}{3})
+	f.expect("UPDATE friendship SET status", "2", "1", friendAccepted, friendPending).affects(0)
+	f.expect("SELECT count(*) FROM friendship", "1", friendAccepted, friendPending).returns(count, []interface{}{3})
+	f.expect("INSERT INTO friendship", "2", "1", friendPending).affects(1)
+	f.expect("COMMIT")
+	if got, err := fs.Request(context.Background(), "2", "1"); err != nil || got != fight.Friend_PENDING {
+		t.Errorf("want a pending request, but get %v, '%v'", got, err)
+	}
+
+	// the request of the friend is accepted at once
+	f.expect("BEGIN")
+	f.expect("pg_advisory_xact_lock", "1")
+	f.expect("pg_advisory_xact_lock", "2")
+	f.expect("SELECT count(*) FROM friendship", "1", friendAccepted, friendPending).returns(count, []interface{}{3})
+	f.expect("UPDATE friendship SET status", "1", "2", friendAccepted, friendPending).affects(1)
+	f.expect("COMMIT")
+	if got, err := fs.Request(context.Background(), "1", "2"); err != nil || got != fight.Friend_ACCEPTED {
+		t.Errorf("want the request accepted, but get %v, '%v'", got, err)
+	}
+
+	// the player is full
+	f.expect("BEGIN")
+	f.expect("pg_advisory_xact_lock", "1")
+	f.expect("pg_advisory_xact_lock", "2")
+	f.expect("SELECT count(*) FROM friendship", "1", friendAccepted, friendPending).returns(count, []interface{}{maxFriends})
+	f.expect("ROLLBACK")
+	if _, err := fs.Request(context.Background(), "1", "2"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("want ResourceExhausted for the player, but get '%v'", err)
+	}
+
+	// the friend is full
+	f.expect("BEGIN")
+	f.expect("pg_advisory_xact_lock", "1")
+	f.expect("pg_advisory_xact_lock", "2")
+	f.expect("SELECT count(*) FROM friendship", "1", friendAccepted, friendPending).returns(count, []interface{}{3})
+	f.expect("UPDATE friendship SET status", "1", "2", friendAccepted, friendPending).affects(0)
+	f.expect("SELECT count(*) FROM friendship", "2", friendAccepted, friendPending).returns(count, []interface{}{maxFriends})
+	f.expect("ROLLBACK")
+	if _, err := fs.Request(context.Background(), "1", "2"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("want ResourceExhausted for the friend, but get '%v'", err)
+	}
+}
+
+func TestAcceptFriend(t *testing.T) {
+	db, f := newFakeDB(t)
+	fs := NewFriends(db)
+	count := []string{"count"}
+
+	f.expect("BEGIN")
+	f.expect("pg_advisory_xact_lock", "1")
+	f.expect("pg_advisory_xact_lock", "2")
+	f.expect("SELECT count(*) FROM friendship", "1", friendAccepted, friendPending).returns(count, []interface{}{maxFriends})
+	f.expect("ROLLBACK")
+	if err := fs.Accept(context.Background(), "1", "2"); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("want ResourceExhausted, but get '%v'", err)
+	}
+
+	f.expect("BEGIN")
+	f.expect("pg_advisory_xact_lock", "1")
+	f.expect("pg_advisory_xact_lock", "2")
+	f.expect("SELECT count(*) FROM friendship", "1", friendAccepted, friendPending).returns(count, []interface{}{3})
+	f.expect("UPDATE friendship SET status", "1", "2", friendAccepted, friendPending).affects(0)
+	f.expect("ROLLBACK")
+	if err := fs.Accept(context.Background(), "1", "2"); status.Code(err) != codes.NotFound {
+		t.Errorf("want NotFound, but get '%v'", err)
+	}
+}
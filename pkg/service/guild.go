@@ -0,0 +1,541 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// the roles of the guild_member table
+const (
+	guildOwner   = "owner"
+	guildOfficer = "officer"
+	guildMember  = "member"
+)
+
+// maxGuildMembers is the number of the members of a guild.
+const maxGuildMembers = 50
+
+// recomputeGuildScore sets the score of the guild $1 to the sum of the best
+// scores of its members.
+const recomputeGuildScore = `UPDATE guild SET score = (SELECT coalesce(sum(h.score), 0) FROM guild_member m JOIN high_scores h ON h.uid = m.uid WHERE m.guildid = guild.id), scoreupdatedat = now() WHERE id = $1;`
+
+// ErrNotInGuild is returned for the guild requests of the players who are
+// not a member of a guild.
+var ErrNotInGuild = status.Error(codes.FailedPrecondition, "not a member of a guild")
+
+// Guilds keeps the guilds in the guild table and their members in the
+// guild_member table. The score of a guild is the sum of the best scores
+// of its members, it is recomputed on every change of the members and of
+// their best scores.
+type Guilds struct {
+	db  *sql.DB
+	hub *Hub
+}
+
+// NewGuilds ...
+func NewGuilds(db *sql.DB) *Guilds {
+	return &Guilds{
+		db:  db,
+		hub: NewHub(pushInterval),
+	}
+}
+
+// Create creates the guild owned by the player.
+func (gs *Guilds) Create(ctx context.Context, uid, name string) error {
+	if err := validateName("guild name", name); err != nil {
+		return err
+	}
+
+	tx, err := gs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	if err = tx.QueryRowContext(ctx, "INSERT INTO guild(name, score, scoreupdatedat, createdat) VALUES($1, 0, now(), now()) RETURNING id;", name).Scan(&id); err != nil {
+		return guildError(err)
+	}
+	if _, err = tx.ExecContext(ctx, "INSERT INTO guild_member(uid, guildid, role, joinedat) VALUES($1, $2, $3, now());", uid, id, guildOwner); err != nil {
+		return guildError(err)
+	}
+	if _, err = tx.ExecContext(ctx, recomputeGuildScore, id); err != nil {
+		return err
+	}
+	return gs.commit(tx)
+}
+
+// Join adds the player to the guild as a member.
+func (gs *Guilds) Join(ctx context.Context, uid, name string) error {
+	tx, err := gs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// the guild row serializes the changes of its members
+	var id int64
+	err = tx.QueryRowContext(ctx, "SELECT id FROM guild WHERE lower(name) = lower($1) FOR UPDATE;", name).Scan(&id)
+	if err == sql.ErrNoRows {
+		return status.Errorf(codes.NotFound, "no guild named '%s'", name)
+	}
+	if err != nil {
+		return err
+	}
+
+	var members int
+	if err = tx.QueryRowContext(ctx, "SELECT count(*) FROM guild_member WHERE guildid = $1;", id).Scan(&members); err != nil {
+		return err
+	}
+	if members >= maxGuildMembers {
+		return status.Errorf(codes.ResourceExhausted, "at most %d members in a guild", maxGuildMembers)
+	}
+
+	if _, err = tx.ExecContext(ctx, "INSERT INTO guild_member(uid, guildid, role, joinedat) VALUES($1, $2, $3, now());", uid, id, guildMember); err != nil {
+		return guildError(err)
+	}
+	if _, err = tx.ExecContext(ctx, recomputeGuildScore, id); err != nil {
+		return err
+	}
+	return gs.commit(tx)
+}
+
+// Leave removes the player from its guild. The guild of a leaving owner is
+// handed over to the earliest officer, or to the earliest member, and the
+// guild is deleted with its last member.
+func (gs *Guilds) Leave(ctx context.Context, uid string) (string, error) {
+	tx, err := gs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	id, name, role, err := memberOf(ctx, tx, uid)
+	if err != nil {
+		return "", err
+	}
+	if _, err = tx.ExecContext(ctx, "DELETE FROM guild_member WHERE uid = $1;", uid); err != nil {
+		return "", err
+	}
+
+	if role == guildOwner {
+		sqlStatement := `UPDATE guild_member SET role = $2 WHERE uid = (SELECT uid FROM guild_member WHERE guildid = $1 ORDER BY role = $3 DESC, joinedat, uid LIMIT 1);`
+		result, err := tx.ExecContext(ctx, sqlStatement, id, guildOwner, guildOfficer)
+		if err != nil {
+			return "", err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return "", err
+		} else if n == 0 {
+			if _, err = tx.ExecContext(ctx, "DELETE FROM guild WHERE id = $1;", id); err != nil {
+				return "", err
+			}
+			return name, gs.commit(tx)
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx, recomputeGuildScore, id); err != nil {
+		return "", err
+	}
+	return name, gs.commit(tx)
+}
+
+// Kick removes the member from the guild of the player: the owner kicks the
+// officers and the members, the officers kick the members.
+func (gs *Guilds) Kick(ctx context.Context, uid, member string) (string, error) {
+	tx, err := gs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	id, name, role, memberRole, err := membersOf(ctx, tx, uid, member)
+	if err != nil {
+		return "", err
+	}
+	if !canKick(role, memberRole) {
+		return "", status.Errorf(codes.PermissionDenied, "a guild %s can not kick a guild %s", role, memberRole)
+	}
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM guild_member WHERE uid = $1;", member); err != nil {
+		return "", err
+	}
+	if _, err = tx.ExecContext(ctx, recomputeGuildScore, id); err != nil {
+		return "", err
+	}
+	return name, gs.commit(tx)
+}
+
+// SetRole sets the role of the member of the guild owned by the player,
+// setting the owner role hands the guild over and the player becomes an
+// officer.
+func (gs *Guilds) SetRole(ctx context.Context, uid, member, role string) (string, error) {
+	tx, err := gs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	_, name, ownerRole, _, err := membersOf(ctx, tx, uid, member)
+	if err != nil {
+		return "", err
+	}
+	if ownerRole != guildOwner {
+		return "", status.Error(codes.PermissionDenied, "only the guild owner sets the roles")
+	}
+	if uid == member {
+		return "", status.Error(codes.InvalidArgument, "hand the guild over to set the role of the owner")
+	}
+
+	if role == guildOwner {
+		if _, err = tx.ExecContext(ctx, "UPDATE guild_member SET role = $2 WHERE uid = $1;", uid, guildOfficer); err != nil {
+			return "", err
+		}
+	}
+	if _, err = tx.ExecContext(ctx, "UPDATE guild_member SET role = $2 WHERE uid = $1;", member, role); err != nil {
+		return "", err
+	}
+	return name, gs.commit(tx)
+}
+
+// Credit recomputes the score of the guild of the player after its best
+// score rose in the turn.
+func (gs *Guilds) Credit(ctx context.Context, t *turnTx, uid string) error {
+	if gs == nil {
+		return nil
+	}
+
+	sqlStatement := `UPDATE guild SET score = (SELECT coalesce(sum(h.score), 0) FROM guild_member m JOIN high_scores h ON h.uid = m.uid WHERE m.guildid = guild.id), scoreupdatedat = now() WHERE id = (SELECT guildid FROM guild_member WHERE uid = $1);`
+	result, err := t.ExecContext(ctx, sqlStatement, uid)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return err
+	}
+	t.onCommit(gs.hub.Publish)
+	return nil
+}
+
+// Get returns the guild with its members.
+func (gs *Guilds) Get(ctx context.Context, name string) (module.Guild, []Player, error) {
+	var guild module.Guild
+	err := gs.db.QueryRowContext(ctx, "SELECT id, name, score, createdat FROM guild WHERE lower(name) = lower($1);", name).Scan(&guild.ID, &guild.Name, &guild.Score, &guild.CreatedAt)
+	if err == sql.ErrNoRows {
+		return guild, nil, status.Errorf(codes.NotFound, "no guild named '%s'", name)
+	}
+	if err != nil {
+		return guild, nil, err
+	}
+
+	sqlStatement := `SELECT m.uid, m.role, coalesce(h.score, 0) FROM guild_member m LEFT JOIN high_scores h ON h.uid = m.uid WHERE m.guildid = $1 ORDER BY m.joinedat, m.uid;`
+	rows, err := gs.db.QueryContext(ctx, sqlStatement, guild.ID)
+	if err != nil {
+		return guild, nil, err
+	}
+	defer rows.Close()
+
+	var members []Player
+	for rows.Next() {
+		var p Player
+		if err = rows.Scan(&p.ID, &p.Role, &p.Score); err != nil {
+			return guild, nil, err
+		}
+		members = append(members, p)
+	}
+	guild.Members = len(members)
+	return guild, members, rows.Err()
+}
+
+// Page returns at most limit guilds ranked after offset, and the number of
+// the guilds. The ties are broken by the guild which reached the score
+// first.
+func (gs *Guilds) Page(ctx context.Context, offset, limit int) ([]module.Guild, int, error) {
+	var total int
+	if err := gs.db.QueryRowContext(ctx, "SELECT count(*) FROM guild;").Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sqlStatement := `SELECT g.id, g.name, g.score, g.createdat, (SELECT count(*) FROM guild_member m WHERE m.guildid = g.id) FROM guild g ORDER BY g.score DESC, g.scoreupdatedat, g.id LIMIT $1 OFFSET $2;`
+	rows, err := gs.db.QueryContext(ctx, sqlStatement, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var guilds []module.Guild
+	for rows.Next() {
+		guild := module.Guild{Rank: offset + len(guilds) + 1}
+		if err = rows.Scan(&guild.ID, &guild.Name, &guild.Score, &guild.CreatedAt, &guild.Members); err != nil {
+			return nil, 0, err
+		}
+		guilds = append(guilds, guild)
+	}
+	return guilds, total, rows.Err()
+}
+
+// commit commits the change of the guilds, and signals the watchers of the
+// guild leaderboard.
+func (gs *Guilds) commit(tx *sql.Tx) error {
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	gs.hub.Publish()
+	return nil
+}
+
+// memberOf returns the guild and the role of the player, and locks the
+// guild row.
+func memberOf(ctx context.Context, tx *sql.Tx, uid string) (int64, string, string, error) {
+	var (
+		id         int64
+		name, role string
+	)
+	sqlStatement := `SELECT g.id, g.name, m.role FROM guild_member m JOIN guild g ON g.id = m.guildid WHERE m.uid = $1 FOR UPDATE OF g;`
+	err := tx.QueryRowContext(ctx, sqlStatement, uid).Scan(&id, &name, &role)
+	if err == sql.ErrNoRows {
+		return 0, "", "", ErrNotInGuild
+	}
+	return id, name, role, err
+}
+
+// membersOf returns the guild of the player with the roles of the player
+// and of the member of the same guild, and locks the guild row.
+func membersOf(ctx context.Context, tx *sql.Tx, uid, member string) (int64, string, string, string, error) {
+	id, name, role, err := memberOf(ctx, tx, uid)
+	if err != nil {
+		return 0, "", "", "", err
+	}
+
+	var memberRole string
+	err = tx.QueryRowContext(ctx, "SELECT role FROM guild_member WHERE uid = $1 AND guildid = $2;", member, id).Scan(&memberRole)
+	if err == sql.ErrNoRows {
+		return 0, "", "", "", status.Errorf(codes.NotFound, "not a member of the guild '%s'", name)
+	}
+	return id, name, role, memberRole, err
+}
+
+// canKick reports whether a member of the role kicks a member of the other
+// role.
+func canKick(role, other string) bool {
+	switch role {
+	case guildOwner:
+		return other != guildOwner
+	case guildOfficer:
+		return other == guildMember
+	default:
+		return false
+	}
+}
+
+// guildError turns the unique violations into the status errors.
+func guildError(err error) error {
+	if e, ok := err.(*pq.Error); ok && e.Code == uniqueViolation {
+		if e.Constraint == "guild_member_pkey" {
+			return status.Error(codes.FailedPrecondition, "already a member of a guild")
+		}
+		return status.Error(codes.AlreadyExists, "guild name is taken")
+	}
+	return err
+}
+
+func convertGuildRole(role fight.GuildRole) string {
+	switch role {
+	case fight.GuildRole_OWNER:
+		return guildOwner
+	case fight.GuildRole_OFFICER:
+		return guildOfficer
+	default:
+		return guildMember
+	}
+}
+
+func convertGuildRole2FightGuildRole(role string) fight.GuildRole {
+	switch role {
+	case guildOwner:
+		return fight.GuildRole_OWNER
+	case guildOfficer:
+		return fight.GuildRole_OFFICER
+	default:
+		return fight.GuildRole_MEMBER
+	}
+}
+
+// CreateGuild ...
+func (s *Service) CreateGuild(ctx context.Context, req *fight.CreateGuildRequest) (*fight.Guild, error) {
+	if err := s.authorizeGuild(ctx, req.GetId()); err != nil {
+		return &fight.Guild{}, err
+	}
+	if err := s.guilds.Create(ctx, req.GetId(), req.GetName()); err != nil {
+		return &fight.Guild{}, err
+	}
+	return s.getGuild(ctx, req.GetName())
+}
+
+// GetGuild ...
+func (s *Service) GetGuild(ctx context.Context, req *fight.GetGuildRequest) (*fight.Guild, error) {
+	return s.getGuild(ctx, req.GetName())
+}
+
+// JoinGuild ...
+func (s *Service) JoinGuild(ctx context.Context, req *fight.JoinGuildRequest) (*fight.Guild, error) {
+	if err := s.authorizeGuild(ctx, req.GetId()); err != nil {
+		return &fight.Guild{}, err
+	}
+	if err := s.guilds.Join(ctx, req.GetId(), req.GetName()); err != nil {
+		return &fight.Guild{}, err
+	}
+	return s.getGuild(ctx, req.GetName())
+}
+
+// LeaveGuild ...
+func (s *Service) LeaveGuild(ctx context.Context, req *fight.LeaveGuildRequest) (*fight.LeaveGuildResponse, error) {
+	if err := s.authorizeGuild(ctx, req.GetId()); err != nil {
+		return &fight.LeaveGuildResponse{}, err
+	}
+	name, err := s.guilds.Leave(ctx, req.GetId())
+	if err != nil {
+		return &fight.LeaveGuildResponse{}, err
+	}
+	return &fight.LeaveGuildResponse{
+		Msg: fmt.Sprintf("left the guild '%s'", name),
+	}, nil
+}
+
+// KickGuildMember ...
+func (s *Service) KickGuildMember(ctx context.Context, req *fight.GuildMemberRequest) (*fight.Guild, error) {
+	member, err := s.guildMember(ctx, req)
+	if err != nil {
+		return &fight.Guild{}, err
+	}
+	name, err := s.guilds.Kick(ctx, req.GetId(), member)
+	if err != nil {
+		return &fight.Guild{}, err
+	}
+	return s.getGuild(ctx, name)
+}
+
+// SetGuildRole ...
+func (s *Service) SetGuildRole(ctx context.Context, req *fight.GuildMemberRequest) (*fight.Guild, error) {
+	member, err := s.guildMember(ctx, req)
+	if err != nil {
+		return &fight.Guild{}, err
+	}
+	name, err := s.guilds.SetRole(ctx, req.GetId(), member, convertGuildRole(req.GetRole()))
+	if err != nil {
+		return &fight.Guild{}, err
+	}
+	return s.getGuild(ctx, name)
+}
+
+// GuildLeaderboard ...
+func (s *Service) GuildLeaderboard(ctx context.Context, req *fight.GuildLeaderboardRequest) (*fight.GuildLeaderboardResponse, error) {
+	page, err := newLeaderboardPage(req.GetLimit(), req.GetOffset(), 0, "")
+	if err != nil {
+		return &fight.GuildLeaderboardResponse{}, err
+	}
+	return s.listGuilds(ctx, page)
+}
+
+// WatchGuildLeaderboard ...
+func (s *Service) WatchGuildLeaderboard(req *fight.GuildLeaderboardRequest, stream fight.FightSvc_WatchGuildLeaderboardServer) error {
+	page, err := newLeaderboardPage(req.GetLimit(), req.GetOffset(), 0, "")
+	if err != nil {
+		return err
+	}
+
+	signal, unsubscribe := s.guilds.hub.Subscribe()
+	defer unsubscribe()
+
+	for {
+		resp, err := s.listGuilds(stream.Context(), page)
+		if err != nil {
+			return err
+		}
+		if err = stream.Send(resp); err != nil {
+			return err
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-signal:
+		}
+	}
+}
+
+func (s *Service) listGuilds(ctx context.Context, page leaderboardPage) (*fight.GuildLeaderboardResponse, error) {
+	guilds, total, err := s.guilds.Page(ctx, page.offset, page.limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &fight.GuildLeaderboardResponse{
+		Entries: make([]*fight.GuildLeaderboardResponse_Entry, len(guilds)),
+		Total:   int32(total),
+	}
+	for i, guild := range guilds {
+		resp.Entries[i] = &fight.GuildLeaderboardResponse_Entry{
+			Rank:    int32(guild.Rank),
+			Name:    guild.Name,
+			Score:   int32(guild.Score),
+			Members: int32(guild.Members),
+		}
+	}
+	return resp, nil
+}
+
+// getGuild returns the guild with the display names of its members.
+func (s *Service) getGuild(ctx context.Context, name string) (*fight.Guild, error) {
+	guild, members, err := s.guilds.Get(ctx, name)
+	if err != nil {
+		return &fight.Guild{}, err
+	}
+	if err = s.profiles.resolve(ctx, members); err != nil {
+		return &fight.Guild{}, err
+	}
+
+	resp := &fight.Guild{
+		Name:      guild.Name,
+		Score:     int32(guild.Score),
+		Members:   make([]*fight.GuildMember, len(members)),
+		CreatedAt: timestamppb.New(guild.CreatedAt),
+	}
+	for i, member := range members {
+		resp.Members[i] = &fight.GuildMember{
+			DisplayName: member.DisplayName,
+			Role:        convertGuildRole2FightGuildRole(member.Role),
+			BestScore:   int32(member.Score),
+		}
+	}
+	return resp, nil
+}
+
+func (s *Service) authorizeGuild(ctx context.Context, id string) error {
+	if _, err := s.authorize(ctx, id); err != nil {
+		return err
+	}
+	if id == "" {
+		return status.Error(codes.InvalidArgument, "id is required")
+	}
+	return nil
+}
+
+// guildMember authorizes the request, and returns the id of the member.
+func (s *Service) guildMember(ctx context.Context, req *fight.GuildMemberRequest) (string, error) {
+	if err := s.authorizeGuild(ctx, req.GetId()); err != nil {
+		return "", err
+	}
+	if req.GetDisplayName() == "" {
+		return "", status.Error(codes.InvalidArgument, "display name of the member is required")
+	}
+	return s.profiles.lookup(ctx, req.GetDisplayName())
+}
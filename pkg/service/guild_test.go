@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+)
+
+func TestCanKick(t *testing.T) {
+	tests := []struct {
+		role, other string
+		want        bool
+	}{
+		{guildOwner, guildOfficer, true},
+		{guildOwner, guildMember, true},
+		{guildOwner, guildOwner, false},
+		{guildOfficer, guildMember, true},
+		{guildOfficer, guildOfficer, false},
+		{guildOfficer, guildOwner, false},
+		{guildMember, guildMember, false},
+		{guildMember, guildOwner, false},
+	}
+	for _, tt := range tests {
+		if got := canKick(tt.role, tt.other); got != tt.want {
+			t.Errorf("canKick(%s, %s): want %v, but get %v", tt.role, tt.other, tt.want, got)
+		}
+	}
+}
+
+func TestConvertGuildRole(t *testing.T) {
+	for _, role := range []fight.GuildRole{fight.GuildRole_MEMBER, fight.GuildRole_OFFICER, fight.GuildRole_OWNER} {
+		if got := convertGuildRole2FightGuildRole(convertGuildRole(role)); got != role {
+			t.Errorf("want %v, but get %v", role, got)
+		}
+	}
+}
+
+func TestLeaveGuild(t *testing.T) {
+	db, f := newFakeDB(t)
+	gs := NewGuilds(db)
+	member := []string{"id", "name", "role"}
+
+	// the owner hands the guild over
+	f.expect("BEGIN")
+	f.expect("FROM guild_member m JOIN guild g", "1").returns(member, []interface{}{7, "aerolite", guildOwner})
+	f.expect("DELETE FROM guild_member WHERE uid", "1").affects(1)
+	f.expect("UPDATE guild_member SET role = $2", 7, guildOwner, guildOfficer).affects(1)
+	f.expect("UPDATE guild SET score", 7).affects(1)
+	f.expect("COMMIT")
+	if name, err := gs.Leave(context.Background(), "1"); err != nil || name != "aerolite" {
+		t.Errorf("want the guild 'aerolite' handed over, but get '%s', '%v'", name, err)
+	}
+
+	// the last member deletes the guild
+	f.expect("BEGIN")
+	f.expect("FROM guild_member m JOIN guild g", "2").returns(member, []interface{}{7, "aerolite", guildOwner})
+	f.expect("DELETE FROM guild_member WHERE uid", "2").affects(1)
+	f.expect("UPDATE guild_member SET role = $2", 7, guildOwner, guildOfficer).affects(0)
+	f.expect("DELETE FROM guild WHERE id", 7).affects(1)
+	f.expect("COMMIT")
+	if _, err := gs.Leave(context.Background(), "2"); err != nil {
+		t.Errorf("want the guild deleted, but get '%v'", err)
+	}
+
+	// a member leaves, the owner stays
+	f.expect("BEGIN")
+	f.expect("FROM guild_member m JOIN guild g", "3").returns(member, []interface{}{8, "sqlite", guildMember})
+	f.expect("DELETE FROM guild_member WHERE uid", "3").affects(1)
+	f.expect("UPDATE guild SET score", 8).affects(1)
+	f.expect("COMMIT")
+	if _, err := gs.Leave(context.Background(), "3"); err != nil {
+		t.Errorf("want the member removed, but get '%v'", err)
+	}
+
+	f.expect("BEGIN")
+	f.expect("FROM guild_member m JOIN guild g", "4").returns(member)
+	f.expect("ROLLBACK")
+	if _, err := gs.Leave(context.Background(), "4"); err != ErrNotInGuild {
+		t.Errorf("want ErrNotInGuild, but get '%v'", err)
+	}
+}
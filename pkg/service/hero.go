@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"unicode/utf8"
+
+	"github.com/lib/pq"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// dbtx is the part of *sql.DB and *sql.Tx used by the catalog changes, so
+// that they run alone or in a transaction.
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// maxHeroName is the length of the name column of the hero table.
+const maxHeroName = 50
+
+// validateHero mirrors the constraints of the hero table, so that a bad
+// hero fails with InvalidArgument instead of a db error.
+func validateHero(hero *fight.Hero) error {
+	if hero.GetName() == "" || utf8.RuneCountInString(hero.GetName()) > maxHeroName {
+		return status.Errorf(codes.InvalidArgument, "hero name must be 1 to %d characters: '%s'", maxHeroName, hero.GetName())
+	}
+	if utf8.RuneCountInString(hero.GetDetails()) <= 8 {
+		return status.Errorf(codes.InvalidArgument, "details of the hero '%s' must be longer than 8 characters", hero.GetName())
+	}
+	if hero.GetAttackPower() < 0 || hero.GetDefensePower() < 0 || hero.GetBlood() < 0 {
+		return status.Errorf(codes.InvalidArgument, "stats of the hero '%s' must not be negative", hero.GetName())
+	}
+	return nil
+}
+
+func createHero(ctx context.Context, q dbtx, hero *fight.Hero) error {
+	if err := validateHero(hero); err != nil {
+		return err
+	}
+	sqlStatement := `INSERT INTO hero(name, detail, attackpower, defensepower, blood) VALUES($1, $2, $3, $4, $5);`
+	_, err := q.ExecContext(ctx, sqlStatement, hero.Name, hero.Details, hero.AttackPower, hero.DefensePower, hero.Blood)
+	if e, ok := err.(*pq.Error); ok && e.Code == uniqueViolation {
+		return status.Errorf(codes.AlreadyExists, "hero '%s' already exists", hero.Name)
+	}
+	return err
+}
+
+func updateHero(ctx context.Context, q dbtx, hero *fight.Hero) error {
+	if err := validateHero(hero); err != nil {
+		return err
+	}
+	sqlStatement := `UPDATE hero SET detail = $2, attackpower = $3, defensepower = $4, blood = $5 WHERE name = $1;`
+	result, err := q.ExecContext(ctx, sqlStatement, hero.Name, hero.Details, hero.AttackPower, hero.DefensePower, hero.Blood)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return status.Errorf(codes.NotFound, "undefined hero: '%s'", hero.Name)
+	}
+	return nil
+}
+
+func upsertHero(ctx context.Context, q dbtx, hero *fight.Hero) error {
+	if err := validateHero(hero); err != nil {
+		return err
+	}
+	sqlStatement := `INSERT INTO hero(name, detail, attackpower, defensepower, blood) VALUES($1, $2, $3, $4, $5) ON conflict (name) DO UPDATE SET detail = $2, attackpower = $3, defensepower = $4, blood = $5;`
+	_, err := q.ExecContext(ctx, sqlStatement, hero.Name, hero.Details, hero.AttackPower, hero.DefensePower, hero.Blood)
+	return err
+}
+
+// deleteHero deletes the hero. The sessions playing it are moved to the
+// hero migrateTo, or the delete fails with FailedPrecondition if migrateTo
+// is empty. The live sessions of this replica are moved by the returned
+// function once the delete is committed. The leased sessions of the other
+// replicas can not be moved, so the delete fails with FailedPrecondition
+// while they play the hero. The best scores reached with the hero are
+// deleted, and the level clears keep no hero.
+func (s *Service) deleteHero(ctx context.Context, q dbtx, name, migrateTo string) (func() error, error) {
+	var live []*module.SessionView
+	sessionViews, err := s.sessions.List()
+	if err != nil {
+		return nil, err
+	}
+	uids := make([]string, 0, len(sessionViews))
+	for _, sv := range sessionViews {
+		uids = append(uids, sv.UID)
+		if sv.HeroName == name {
+			live = append(live, sv)
+		}
+	}
+
+	var leased int
+	sqlStatement := `SELECT count(*) FROM session JOIN session_lease USING (uid) WHERE session.heroname = $1 AND session_lease.expiresat >= now() AND NOT session.uid = ANY($2);`
+	if err = q.QueryRowContext(ctx, sqlStatement, name, pq.Array(uids)).Scan(&leased); err != nil {
+		return nil, err
+	}
+	if leased > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "%d sessions of other replicas play the hero '%s'", leased, name)
+	}
+
+	var archived int
+	if err = q.QueryRowContext(ctx, "SELECT count(*) FROM session WHERE heroname = $1;", name).Scan(&archived); err != nil {
+		return nil, err
+	}
+
+	var target module.Hero
+	switch {
+	case migrateTo == name:
+		return nil, status.Errorf(codes.InvalidArgument, "can not migrate the sessions of the hero '%s' to itself", name)
+	case migrateTo == "":
+		if n := len(live) + archived; n > 0 {
+			return nil, status.Errorf(codes.FailedPrecondition, "%d sessions play the hero '%s', set migrate_to to move them", n, name)
+		}
+	default:
+		err = q.QueryRowContext(ctx, "SELECT name, detail, attackpower, defensepower, blood FROM hero WHERE name = $1;", migrateTo).
+			Scan(&target.Name, &target.Detail, &target.AttackPower, &target.DefensePower, &target.Blood)
+		if err == sql.ErrNoRows {
+			return nil, status.Errorf(codes.NotFound, "undefined hero: '%s'", migrateTo)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, err = q.ExecContext(ctx, "UPDATE session SET heroname = $2 WHERE heroname = $1;", name, migrateTo); err != nil {
+			return nil, err
+		}
+	}
+
+	// the records refer to the hero
+	if _, err = q.ExecContext(ctx, "DELETE FROM hero_score WHERE heroname = $1;", name); err != nil {
+		return nil, err
+	}
+	if _, err = q.ExecContext(ctx, "UPDATE level_clear SET heroname = NULL WHERE heroname = $1;", name); err != nil {
+		return nil, err
+	}
+
+	result, err := q.ExecContext(ctx, "DELETE FROM hero WHERE name = $1;", name)
+	if err != nil {
+		return nil, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, status.Errorf(codes.NotFound, "undefined hero: '%s'", name)
+	}
+
+	return func() error {
+		s.heroLock.Lock()
+		delete(s.pendingHeroes, name)
+		s.heroLock.Unlock()
+		s.records.forgetHero(name)
+
+		for _, sv := range live {
+			if err := s.applyLive(sv.UID, SessionEvent{Kind: HeroChanged, Hero: &target}, func(sv *module.SessionView) bool {
+				return sv.HeroName == name
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidateHero(t *testing.T) {
+	valid := fight.Hero{Name: "PostgreSql", Details: "master of opensource", AttackPower: 50, DefensePower: 30, Blood: 100}
+
+	tests := []struct {
+		name   string
+		modify func(*fight.Hero)
+		valid  bool
+	}{
+		{"valid", func(*fight.Hero) {}, true},
+		{"zero stats", func(h *fight.Hero) { h.AttackPower, h.DefensePower, h.Blood = 0, 0, 0 }, true},
+		{"no name", func(h *fight.Hero) { h.Name = "" }, false},
+		{"long name", func(h *fight.Hero) { h.Name = "PostgreSqlPostgreSqlPostgreSqlPostgreSqlPostgreSqlX" }, false},
+		{"short details", func(h *fight.Hero) { h.Details = "12345678" }, false},
+		{"negative attack", func(h *fight.Hero) { h.AttackPower = -1 }, false},
+		{"negative defense", func(h *fight.Hero) { h.DefensePower = -1 }, false},
+		{"negative blood", func(h *fight.Hero) { h.Blood = -1 }, false},
+	}
+	for _, tt := range tests {
+		hero := valid
+		tt.modify(&hero)
+		err := validateHero(&hero)
+		if tt.valid && err != nil {
+			t.Errorf("%s: want valid, but get '%v'", tt.name, err)
+		}
+		if !tt.valid && status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: want InvalidArgument, but get '%v'", tt.name, err)
+		}
+	}
+}
+
+func TestDeleteHero(t *testing.T) {
+	db, f := newFakeDB(t)
+	s := &Service{db: db, sessions: NewMemoryStore(), pendingHeroes: map[string]module.Hero{}}
+	live := &module.SessionView{Session: module.Session{UID: "1", HeroName: "Oracle"}, Hero: module.Hero{Name: "Oracle"}}
+	if err := s.sessions.Add("1", live); err != nil {
+		t.Fatal(err)
+	}
+	req := &fight.AdminRequest{Type: fight.AdminRequest_DELETE_HERO, Heros: []*fight.Hero{{Name: "Oracle"}}}
+
+	// played by an archived session
+	f.expect("BEGIN")
+	f.expect("JOIN session_lease").returns([]string{"count"}, []interface{}{0})
+	f.expect("SELECT count(*) FROM session WHERE heroname", "Oracle").returns([]string{"count"}, []interface{}{1})
+	f.expect("ROLLBACK")
+	if _, err := s.admin(context.Background(), req, nil, &fight.AdminResult{}, nil); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("want FailedPrecondition without migrate_to, but get '%v'", err)
+	}
+
+	// played by a session of another replica, which can not be moved
+	req.MigrateTo = "PostgreSql"
+	f.expect("BEGIN")
+	f.expect("JOIN session_lease").returns([]string{"count"}, []interface{}{1})
+	f.expect("ROLLBACK")
+	if _, err := s.admin(context.Background(), req, nil, &fight.AdminResult{}, nil); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("want FailedPrecondition with a leased session, but get '%v'", err)
+	}
+
+	// the sessions are moved, the records of the hero go away in the same
+	// transaction
+	f.expect("BEGIN")
+	f.expect("JOIN session_lease").returns([]string{"count"}, []interface{}{0})
+	f.expect("SELECT count(*) FROM session WHERE heroname", "Oracle").returns([]string{"count"}, []interface{}{1})
+	f.expect("FROM hero WHERE name", "PostgreSql").returns([]string{"name", "detail", "attackpower", "defensepower", "blood"},
+		[]interface{}{"PostgreSql", "master of opensource", 50, 30, 100})
+	f.expect("UPDATE session SET heroname", "Oracle", "PostgreSql").affects(1)
+	f.expect("DELETE FROM hero_score", "Oracle").affects(3)
+	f.expect("UPDATE level_clear SET heroname = NULL", "Oracle").affects(2)
+	f.expect("DELETE FROM hero WHERE name", "Oracle").affects(1)
+	f.expect("COMMIT")
+	applied, err := s.admin(context.Background(), req, nil, &fight.AdminResult{}, nil)
+	if err != nil || applied != 1 {
+		t.Fatalf("want the hero deleted, but get %d, '%v'", applied, err)
+	}
+	sv, err := s.sessions.Get("1")
+	if err != nil || sv.HeroName != "PostgreSql" || sv.Hero.AttackPower != 50 {
+		t.Errorf("want the live session moved to the hero 'PostgreSql', but get '%v', '%v'", sv, err)
+	}
+
+	// undefined
+	req.MigrateTo = ""
+	f.expect("BEGIN")
+	f.expect("JOIN session_lease").returns([]string{"count"}, []interface{}{0})
+	f.expect("SELECT count(*) FROM session WHERE heroname", "Dragon").returns([]string{"count"}, []interface{}{0})
+	f.expect("DELETE FROM hero_score", "Dragon")
+	f.expect("UPDATE level_clear SET heroname = NULL", "Dragon")
+	f.expect("DELETE FROM hero WHERE name", "Dragon").affects(0)
+	f.expect("ROLLBACK")
+	req.Heros[0].Name = "Dragon"
+	if _, err = s.admin(context.Background(), req, nil, &fight.AdminResult{}, nil); status.Code(err) != codes.NotFound {
+		t.Errorf("want NotFound, but get '%v'", err)
+	}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// HighScores keeps the best score of every player in the high_scores
+// table, it serves the all-time leaderboard which outlives the sessions.
+// A nil *HighScores does not persist anything.
+type HighScores struct {
+	db *sql.DB
+
+	lock sync.Mutex
+	// the best scores known to this instance, so that a fight turn which
+	// does not beat the best score does not hit the db
+	best map[string]int
+}
+
+// NewHighScores ...
+func NewHighScores(db *sql.DB) *HighScores {
+	return &HighScores{
+		db:   db,
+		best: make(map[string]int),
+	}
+}
+
+// Raise saves the score of the player in the turn if it beats the best
+// one, and reports whether it did.
+func (hs *HighScores) Raise(ctx context.Context, t *turnTx, uid string, score, level int) (bool, error) {
+	if hs == nil {
+		return false, nil
+	}
+
+	hs.lock.Lock()
+	best, ok := hs.best[uid]
+	hs.lock.Unlock()
+	if ok && score <= best {
+		return false, nil
+	}
+
+	raised := true
+	sqlStatement := `INSERT INTO high_scores(uid, score, level, updatedat) VALUES($1, $2, $3, now()) ON conflict (uid) DO UPDATE SET score = $2, level = $3, updatedat = now() WHERE high_scores.score < $2 RETURNING score;`
+	err := t.QueryRowContext(ctx, sqlStatement, uid, score, level).Scan(&best)
+	if err == sql.ErrNoRows {
+		// beaten by another replica, or not cached yet
+		raised = false
+		err = t.QueryRowContext(ctx, "SELECT score FROM high_scores WHERE uid = $1;", uid).Scan(&best)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	t.onCommit(func() {
+		hs.lock.Lock()
+		defer hs.lock.Unlock()
+		if best > hs.best[uid] {
+			hs.best[uid] = best
+		}
+	})
+	return raised, nil
+}
+
+// Page returns at most limit players of the all-time ranking after offset,
+// and the number of ranked players.
+func (hs *HighScores) Page(ctx context.Context, offset, limit int) ([]Player, int, error) {
+	if hs == nil {
+		return nil, 0, nil
+	}
+
+	var total int
+	if err := hs.db.QueryRowContext(ctx, "SELECT count(*) FROM high_scores;").Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := hs.db.QueryContext(ctx, "SELECT uid, score, level FROM high_scores ORDER BY "+scoreOrder+" LIMIT $1 OFFSET $2;", limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var players []Player
+	for rows.Next() {
+		p := Player{Rank: offset + len(players) + 1}
+		if err = rows.Scan(&p.ID, &p.Score, &p.Level); err != nil {
+			return nil, 0, err
+		}
+		players = append(players, p)
+	}
+	return players, total, rows.Err()
+}
+
+// Rank returns the player with its all-time rank, or ErrorNotFound.
+func (hs *HighScores) Rank(ctx context.Context, uid string) (Player, error) {
+	if hs == nil {
+		return Player{}, ErrorNotFound
+	}
+
+	sqlStatement := `SELECT uid, score, level, rank FROM (SELECT uid, score, level, row_number() OVER (ORDER BY ` + scoreOrder + `) AS rank FROM high_scores) ranked WHERE uid = $1;`
+	var p Player
+	err := hs.db.QueryRowContext(ctx, sqlStatement, uid).Scan(&p.ID, &p.Score, &p.Level, &p.Rank)
+	if err == sql.ErrNoRows {
+		return Player{}, ErrorNotFound
+	}
+	return p, err
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHighScoresCached(t *testing.T) {
+	// no db: the scores not beating the cached best must not reach it
+	hs := NewHighScores(nil)
+	hs.best["1"] = 100
+
+	for _, score := range []int{0, 90, 100} {
+		raised, err := hs.Raise(context.Background(), nil, "1", score, 1)
+		if err != nil || raised {
+			t.Errorf("score %d: want not raised, but get: %v, '%v'", score, raised, err)
+		}
+	}
+}
+
+func TestNilHighScores(t *testing.T) {
+	var hs *HighScores
+	if raised, err := hs.Raise(context.Background(), nil, "1", 10, 1); err != nil || raised {
+		t.Errorf("want not raised, but get: %v, '%v'", raised, err)
+	}
+	if _, err := hs.Rank(context.Background(), "1"); err != ErrorNotFound {
+		t.Errorf("want ErrorNotFound, but get: '%v'", err)
+	}
+}
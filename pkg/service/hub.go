@@ -0,0 +1,75 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// Hub broadcasts the change signals to every subscriber. The bursts of
+// signals are coalesced into at most one push per interval, and a slow
+// subscriber gets a single pending signal instead of a backlog.
+type Hub struct {
+	interval time.Duration
+
+	lock        sync.Mutex
+	subscribers map[chan struct{}]struct{}
+	lastPush    time.Time
+	scheduled   bool
+}
+
+// NewHub creates a hub pushing at most once per interval.
+func NewHub(interval time.Duration) *Hub {
+	return &Hub{
+		interval:    interval,
+		subscribers: make(map[chan struct{}]struct{}),
+	}
+}
+
+// Subscribe registers a new subscriber, the returned function unsubscribes it.
+func (h *Hub) Subscribe() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	h.lock.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.lock.Unlock()
+
+	return ch, func() {
+		h.lock.Lock()
+		delete(h.subscribers, ch)
+		h.lock.Unlock()
+	}
+}
+
+// Publish signals the subscribers, right away if nothing was pushed during
+// the last interval, or at the end of the interval otherwise.
+func (h *Hub) Publish() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.scheduled {
+		return
+	}
+	if wait := h.interval - time.Since(h.lastPush); wait > 0 {
+		h.scheduled = true
+		time.AfterFunc(wait, func() {
+			h.lock.Lock()
+			defer h.lock.Unlock()
+			h.scheduled = false
+			h.push()
+		})
+		return
+	}
+	h.push()
+}
+
+// push must be called with the lock held.
+func (h *Hub) push() {
+	h.lastPush = time.Now()
+	for ch := range h.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+			// the subscriber has not consumed the previous signal yet
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHubBroadcast(t *testing.T) {
+	hub := NewHub(20 * time.Millisecond)
+	first, unsubscribeFirst := hub.Subscribe()
+	second, unsubscribeSecond := hub.Subscribe()
+	defer unsubscribeSecond()
+
+	hub.Publish()
+	for _, ch := range []<-chan struct{}{first, second} {
+		select {
+		case <-ch:
+		case <-time.After(time.Second):
+			t.Fatal("want every subscriber to receive the signal")
+		}
+	}
+
+	// the burst is coalesced into a single push at the end of the interval
+	for i := 0; i < 100; i++ {
+		hub.Publish()
+	}
+	select {
+	case <-first:
+	case <-time.After(time.Second):
+		t.Fatal("want the burst to be pushed")
+	}
+	select {
+	case <-first:
+		t.Error("want a single push for the burst")
+	case <-time.After(50 * time.Millisecond):
+	}
+	// drain the push of the burst, so that the next signal is a fresh one
+	select {
+	case <-second:
+	default:
+		t.Fatal("want the burst to be pushed to every subscriber")
+	}
+
+	unsubscribeFirst()
+	hub.Publish()
+	select {
+	case <-second:
+	case <-time.After(time.Second):
+		t.Fatal("want the remaining subscriber to receive the signal")
+	}
+	select {
+	case <-first:
+		t.Error("want no signal after unsubscribe")
+	default:
+	}
+}
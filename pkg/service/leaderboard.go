@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	defaultLeaderboardLimit      = 10
+	maxLeaderboardLimit          = 100
+	defaultLeaderboardNeighbours = 2
+)
+
+// board is a ranking served by the Leaderboard RPC.
+type board interface {
+	// Page returns at most limit players ranked after offset, and the
+	// number of ranked players.
+	Page(ctx context.Context, offset, limit int) ([]Player, int, error)
+	// Rank returns the ranked player, or ErrorNotFound.
+	Rank(ctx context.Context, id string) (Player, error)
+}
+
+// liveBoard ranks the online sessions.
+type liveBoard struct {
+	sessions SessionStore
+}
+
+func (lb liveBoard) Page(ctx context.Context, offset, limit int) ([]Player, int, error) {
+	return lb.sessions.ListRange(offset, limit)
+}
+
+func (lb liveBoard) Rank(ctx context.Context, id string) (Player, error) {
+	return lb.sessions.Rank(id)
+}
+
+// board returns the ranking of the window, and the period of the windowed
+// leaderboards.
+func (s *Service) board(ctx context.Context, req *fight.LeaderboardRequest) (board, string, error) {
+	switch window := req.GetWindow(); window {
+	case fight.LeaderboardRequest_PLAYING_NOW:
+		return liveBoard{sessions: s.sessions}, "", nil
+	case fight.LeaderboardRequest_ALL_TIME:
+		return s.highScores, "", nil
+	default:
+		kind, ok := windowKind(window)
+		if !ok {
+			return nil, "", status.Errorf(codes.InvalidArgument, "undefined leaderboard window: '%v'", window)
+		}
+		return s.windows.board(ctx, kind, req.GetPeriod())
+	}
+}
+
+// leaderboardPage is the part of a ranking requested by a leaderboard RPC.
+type leaderboardPage struct {
+	limit      int
+	offset     int
+	playerID   string
+	neighbours int
+}
+
+// newLeaderboardPage validates the paging of the request and sets the
+// defaults.
+func newLeaderboardPage(limit, offset, neighbours int32, playerID string) (leaderboardPage, error) {
+	page := leaderboardPage{
+		limit:      int(limit),
+		offset:     int(offset),
+		playerID:   playerID,
+		neighbours: int(neighbours),
+	}
+	switch {
+	case page.limit < 0 || page.offset < 0 || page.neighbours < 0:
+		return page, status.Error(codes.InvalidArgument, "limit, offset and neighbours must not be negative")
+	case page.limit == 0:
+		page.limit = defaultLeaderboardLimit
+	case page.limit > maxLeaderboardLimit:
+		page.limit = maxLeaderboardLimit
+	}
+	if page.neighbours == 0 {
+		page.neighbours = defaultLeaderboardNeighbours
+	} else if page.neighbours > maxLeaderboardLimit {
+		page.neighbours = maxLeaderboardLimit
+	}
+	return page, nil
+}
+
+// Leaderboard ...
+func (s *Service) Leaderboard(ctx context.Context, req *fight.LeaderboardRequest) (*fight.LeaderboardResponse, error) {
+	page, err := newLeaderboardPage(req.GetLimit(), req.GetOffset(), req.GetNeighbours(), req.GetPlayerId())
+	if err != nil {
+		return &fight.LeaderboardResponse{}, err
+	}
+
+	b, period, err := s.board(ctx, req)
+	if err != nil {
+		return &fight.LeaderboardResponse{}, err
+	}
+
+	resp, err := s.listBoard(ctx, b, page)
+	if err != nil {
+		return &fight.LeaderboardResponse{}, err
+	}
+	resp.Period = period
+	return resp, nil
+}
+
+// listBoard returns the page of the ranking, and the rank of the player
+// with its neighbours if it is requested. The players are shown by their
+// display names, the id is returned for the requested player only.
+func (s *Service) listBoard(ctx context.Context, b board, page leaderboardPage) (*fight.LeaderboardResponse, error) {
+	players, total, err := b.Page(ctx, page.offset, page.limit)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.profiles.resolve(ctx, players); err != nil {
+		return nil, err
+	}
+	resp := &fight.LeaderboardResponse{
+		Entries: convertPlayers2Entries(players),
+		Total:   int32(total),
+	}
+
+	if page.playerID == "" {
+		return resp, nil
+	}
+	player, err := b.Rank(ctx, page.playerID)
+	if err == ErrorNotFound {
+		return resp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = s.profiles.resolve(ctx, []Player{player}); err != nil {
+		return nil, err
+	}
+	resp.Player = convertPlayer2Entry(player)
+	resp.Player.Id = player.ID
+
+	from := player.Rank - page.neighbours
+	if from < 1 {
+		from = 1
+	}
+	around, _, err := b.Page(ctx, from-1, player.Rank+page.neighbours-from+1)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.profiles.resolve(ctx, around); err != nil {
+		return nil, err
+	}
+	for _, p := range around {
+		if p.ID != player.ID {
+			resp.Neighbours = append(resp.Neighbours, convertPlayer2Entry(p))
+		}
+	}
+	return resp, nil
+}
+
+func convertPlayer2Entry(p Player) *fight.LeaderboardEntry {
+	entry := &fight.LeaderboardEntry{
+		Rank:        int32(p.Rank),
+		Score:       int32(p.Score),
+		Level:       int32(p.Level),
+		HeroName:    p.HeroName,
+		DisplayName: p.DisplayName,
+	}
+	if p.ID != "" {
+		entry.PlayerKey = p.Key
+	}
+	if p.ClearTime > 0 {
+		entry.ClearTime = durationpb.New(p.ClearTime)
+	}
+	return entry
+}
+
+func convertPlayers2Entries(players []Player) []*fight.LeaderboardEntry {
+	entries := make([]*fight.LeaderboardEntry, len(players))
+	for i := range players {
+		entries[i] = convertPlayer2Entry(players[i])
+	}
+	return entries
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"io"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+)
+
+// WatchLeaderboard ...
+func (s *Service) WatchLeaderboard(stream fight.FightSvc_WatchLeaderboardServer) error {
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	page, err := newLeaderboardPage(req.GetLimit(), req.GetOffset(), 0, "")
+	if err != nil {
+		return err
+	}
+
+	signal, unsubscribe := s.sessions.Subscribe()
+	defer unsubscribe()
+
+	// the later requests only ask for a resync
+	var (
+		resync = make(chan struct{}, 1)
+		recv   = make(chan error, 1)
+	)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				recv <- err
+				return
+			}
+			if req.GetResync() {
+				select {
+				case resync <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	var (
+		ctx      = stream.Context()
+		seq      uint64
+		last     []Player
+		snapshot = true
+	)
+	for {
+		players, _, err := s.sessions.ListRange(page.offset, page.limit)
+		if err != nil {
+			return err
+		}
+		if err = s.profiles.resolve(ctx, players); err != nil {
+			return err
+		}
+
+		update := &fight.LeaderboardUpdate{Snapshot: snapshot}
+		if snapshot {
+			update.Entries = convertPlayers2Entries(players)
+		} else {
+			update.Deltas = diffLeaderboard(last, players)
+		}
+		if snapshot || len(update.Deltas) > 0 {
+			seq++
+			update.Seq = seq
+			if err = stream.Send(update); err != nil {
+				return err
+			}
+		}
+		last, snapshot = players, false
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err = <-recv:
+			if err != io.EOF {
+				return err
+			}
+			// the client is done with the resyncs, keep streaming
+			recv = nil
+		case <-resync:
+			snapshot = true
+		case <-signal:
+		}
+	}
+}
+
+// diffLeaderboard returns the deltas turning the entries from into the
+// entries to: the removals in the previous rank order, then the insertions
+// and the moves in the new rank order. The entries are matched by the id of
+// the player, carried as the player key, so a renamed player moves.
+func diffLeaderboard(from, to []Player) []*fight.LeaderboardDelta {
+	var (
+		deltas []*fight.LeaderboardDelta
+		before = make(map[string]Player, len(from))
+		after  = make(map[string]struct{}, len(to))
+	)
+	for _, p := range from {
+		before[p.ID] = p
+	}
+	for _, p := range to {
+		after[p.ID] = struct{}{}
+	}
+
+	for _, p := range from {
+		if _, ok := after[p.ID]; !ok {
+			deltas = append(deltas, &fight.LeaderboardDelta{
+				Type:     fight.LeaderboardDelta_REMOVE,
+				Entry:    &fight.LeaderboardEntry{PlayerKey: p.Key, DisplayName: p.DisplayName},
+				FromRank: int32(p.Rank),
+			})
+		}
+	}
+
+	for _, p := range to {
+		prev, ok := before[p.ID]
+		switch {
+		case !ok:
+			deltas = append(deltas, &fight.LeaderboardDelta{
+				Type:  fight.LeaderboardDelta_INSERT,
+				Entry: convertPlayer2Entry(p),
+			})
+		case prev.Rank != p.Rank || prev.Score != p.Score || prev.Level != p.Level || prev.DisplayName != p.DisplayName:
+			deltas = append(deltas, &fight.LeaderboardDelta{
+				Type:     fight.LeaderboardDelta_MOVE,
+				Entry:    convertPlayer2Entry(p),
+				FromRank: int32(prev.Rank),
+			})
+		}
+	}
+	return deltas
+}
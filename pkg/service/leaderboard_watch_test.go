@@ -0,0 +1,114 @@
+package service
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+	"testing/quick"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+)
+
+// applyDeltas applies the deltas to the entries the way a client does.
+func applyDeltas(entries []*fight.LeaderboardEntry, deltas []*fight.LeaderboardDelta) []*fight.LeaderboardEntry {
+	byKey := make(map[string]*fight.LeaderboardEntry, len(entries))
+	for _, e := range entries {
+		byKey[e.PlayerKey] = e
+	}
+	for _, d := range deltas {
+		switch d.Type {
+		case fight.LeaderboardDelta_REMOVE:
+			delete(byKey, d.Entry.PlayerKey)
+		default:
+			byKey[d.Entry.PlayerKey] = d.Entry
+		}
+	}
+
+	var applied []*fight.LeaderboardEntry
+	for _, e := range byKey {
+		applied = append(applied, e)
+	}
+	sort.Slice(applied, func(i, j int) bool {
+		return applied[i].Rank < applied[j].Rank
+	})
+	return applied
+}
+
+func TestDiffLeaderboard(t *testing.T) {
+	from := []Player{
+		{ID: "1", Key: "key-1", DisplayName: "alice", Score: 30, Rank: 1},
+		{ID: "2", Key: "key-2", DisplayName: "bob", Score: 20, Rank: 2},
+		{ID: "3", Key: "key-3", DisplayName: "carol", Score: 10, Rank: 3},
+	}
+	to := []Player{
+		{ID: "2", Key: "key-2", DisplayName: "bob", Score: 40, Rank: 1},
+		{ID: "1", Key: "key-1", DisplayName: "alice", Score: 30, Rank: 2},
+		{ID: "4", Key: "key-4", DisplayName: "dave", Score: 20, Rank: 3},
+	}
+
+	deltas := diffLeaderboard(from, to)
+	want := []*fight.LeaderboardDelta{
+		{Type: fight.LeaderboardDelta_REMOVE, Entry: &fight.LeaderboardEntry{PlayerKey: "key-3", DisplayName: "carol"}, FromRank: 3},
+		{Type: fight.LeaderboardDelta_MOVE, Entry: convertPlayer2Entry(to[0]), FromRank: 2},
+		{Type: fight.LeaderboardDelta_MOVE, Entry: convertPlayer2Entry(to[1]), FromRank: 1},
+		{Type: fight.LeaderboardDelta_INSERT, Entry: convertPlayer2Entry(to[2])},
+	}
+	if !reflect.DeepEqual(deltas, want) {
+		t.Errorf("want deltas '%v', but get: '%v'", want, deltas)
+	}
+
+	if deltas = diffLeaderboard(to, to); len(deltas) != 0 {
+		t.Errorf("want no delta, but get: '%v'", deltas)
+	}
+
+	// a renamed player moves, two players of the same name stay apart
+	renamed := []Player{
+		{ID: "2", DisplayName: "robert", Score: 40, Rank: 1},
+		{ID: "1", DisplayName: "dave", Score: 30, Rank: 2},
+		{ID: "4", Key: "key-4", DisplayName: "dave", Score: 20, Rank: 3},
+	}
+	deltas = diffLeaderboard(to, renamed)
+	want = []*fight.LeaderboardDelta{
+		{Type: fight.LeaderboardDelta_MOVE, Entry: convertPlayer2Entry(renamed[0]), FromRank: 1},
+		{Type: fight.LeaderboardDelta_MOVE, Entry: convertPlayer2Entry(renamed[1]), FromRank: 2},
+	}
+	if !reflect.DeepEqual(deltas, want) {
+		t.Errorf("want deltas '%v', but get: '%v'", want, deltas)
+	}
+}
+
+func TestDiffLeaderboardApplies(t *testing.T) {
+	property := func(seed int64) bool {
+		var (
+			rnd = rand.New(rand.NewSource(seed))
+			r   = newRanking()
+		)
+		for i := 0; i < 50; i++ {
+			id := strconv.Itoa(rnd.Intn(30))
+			r.set(Player{ID: id, Key: "key-" + id, DisplayName: "player-" + id, Score: rnd.Intn(5) * 10})
+		}
+		from, _ := r.page(5, 10)
+
+		for i := 0; i < rnd.Intn(20); i++ {
+			id := strconv.Itoa(rnd.Intn(30))
+			if rnd.Intn(4) == 0 {
+				r.remove(id)
+			} else {
+				r.set(Player{ID: id, Key: "key-" + id, DisplayName: "player-" + id, Score: rnd.Intn(5) * 10})
+			}
+		}
+		to, _ := r.page(5, 10)
+
+		applied := applyDeltas(convertPlayers2Entries(from), diffLeaderboard(from, to))
+		want := convertPlayers2Entries(to)
+		if len(want) == 0 {
+			return len(applied) == 0
+		}
+		return reflect.DeepEqual(applied, want)
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
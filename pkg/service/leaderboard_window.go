@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog"
+)
+
+// WindowKind is the period of a time-windowed leaderboard.
+type WindowKind string
+
+const (
+	// Daily windows start at midnight.
+	Daily WindowKind = "daily"
+	// Weekly windows start on Monday at midnight.
+	Weekly WindowKind = "weekly"
+	// Season windows are the calendar quarters.
+	Season WindowKind = "season"
+)
+
+var windowKinds = []WindowKind{Daily, Weekly, Season}
+
+// windowRetryInterval is the delay before retrying a failed rotation.
+const windowRetryInterval = time.Minute
+
+// windowPeriod returns the name and the bounds of the window of the kind
+// containing t, the bounds are computed in the location of t.
+func windowPeriod(kind WindowKind, t time.Time) (string, time.Time, time.Time) {
+	var (
+		year, month, day = t.Date()
+		midnight         = time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	)
+	switch kind {
+	case Weekly:
+		// the weeks of ISO 8601 start on Monday
+		start := midnight.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+		isoYear, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", isoYear, week), start, start.AddDate(0, 0, 7)
+	case Season:
+		quarter := (int(month) - 1) / 3
+		start := time.Date(year, time.Month(quarter*3+1), 1, 0, 0, 0, 0, t.Location())
+		return fmt.Sprintf("%04d-Q%d", year, quarter+1), start, start.AddDate(0, 3, 0)
+	default:
+		return midnight.Format("2006-01-02"), midnight, midnight.AddDate(0, 0, 1)
+	}
+}
+
+// Windows keeps the daily, weekly and season leaderboards in the
+// leaderboard_window and window_score tables. The scores are credited to
+// every open window, the finished windows are closed and their ranking is
+// archived in the window_result table. A nil *Windows disables them.
+type Windows struct {
+	db       *sql.DB
+	location *time.Location
+	stop     chan struct{}
+}
+
+// NewWindows creates the windowed leaderboards, the periods are computed in
+// the given location.
+func NewWindows(db *sql.DB, location *time.Location) *Windows {
+	return &Windows{
+		db:       db,
+		location: location,
+		stop:     make(chan struct{}),
+	}
+}
+
+// WithWindows enables the daily, weekly and season leaderboards.
+func WithWindows(w *Windows) Option {
+	return func(s *Service) {
+		s.windows = w
+	}
+}
+
+// Credit adds the score earned by the player in the turn to every open
+// window, the level is the highest one reached in the window.
+func (w *Windows) Credit(ctx context.Context, t *turnTx, uid string, score, level int) error {
+	if w == nil || score <= 0 {
+		return nil
+	}
+
+	sqlStatement := `INSERT INTO window_score(windowid, uid, score, level, updatedat) SELECT id, $1, $2, $3, now() FROM leaderboard_window WHERE closedat IS NULL AND startsat <= now() AND now() < endsat ON conflict (windowid, uid) DO UPDATE SET score = window_score.score + EXCLUDED.score, level = GREATEST(window_score.level, EXCLUDED.level), updatedat = now();`
+	_, err := t.ExecContext(ctx, sqlStatement, uid, score, level)
+	return err
+}
+
+// Run rotates the windows at every day boundary until Stop is called.
+func (w *Windows) Run() {
+	if w == nil {
+		return
+	}
+
+	for {
+		next := windowRetryInterval
+		if err := w.rotate(context.Background(), time.Now()); err != nil {
+			klog.Warning(err)
+		} else {
+			// every window boundary is a day boundary
+			_, _, end := windowPeriod(Daily, time.Now().In(w.location))
+			next = time.Until(end)
+		}
+
+		timer := time.NewTimer(next)
+		select {
+		case <-w.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// Stop stops rotating the windows.
+func (w *Windows) Stop() {
+	if w == nil {
+		return
+	}
+	close(w.stop)
+}
+
+// rotate closes and archives the finished windows, then opens the current
+// and the next windows of every kind, so that no score is lost at the
+// boundary. Every replica runs it, the closing is done once.
+func (w *Windows) rotate(ctx context.Context, now time.Time) error {
+	tx, err := w.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "UPDATE leaderboard_window SET closedat = now() WHERE closedat IS NULL AND endsat <= $1 RETURNING id, kind, period;", now)
+	if err != nil {
+		return err
+	}
+	var closed []int64
+	for rows.Next() {
+		var (
+			id           int64
+			kind, period string
+		)
+		if err = rows.Scan(&id, &kind, &period); err != nil {
+			rows.Close()
+			return err
+		}
+		klog.Infof("%s leaderboard '%s' is closed", kind, period)
+		closed = append(closed, id)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range closed {
+		sqlStatement := `INSERT INTO window_result(windowid, rank, uid, score, level) SELECT windowid, row_number() OVER (ORDER BY ` + scoreOrder + `), uid, score, level FROM window_score WHERE windowid = $1;`
+		if _, err = tx.ExecContext(ctx, sqlStatement, id); err != nil {
+			return err
+		}
+	}
+
+	local := now.In(w.location)
+	for _, kind := range windowKinds {
+		_, _, end := windowPeriod(kind, local)
+		for _, t := range []time.Time{local, end} {
+			period, start, end := windowPeriod(kind, t)
+			sqlStatement := `INSERT INTO leaderboard_window(kind, period, startsat, endsat) VALUES($1, $2, $3, $4) ON conflict (kind, period) DO NOTHING;`
+			if _, err = tx.ExecContext(ctx, sqlStatement, kind, period, start, end); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// board returns the ranking of the window of the kind, the current one if
+// the period is empty. The ranking of a closed window is the archived one.
+func (w *Windows) board(ctx context.Context, kind WindowKind, period string) (board, string, error) {
+	if w == nil {
+		return nil, "", status.Error(codes.Unavailable, "windowed leaderboards are not enabled")
+	}
+	if period == "" {
+		period, _, _ = windowPeriod(kind, time.Now().In(w.location))
+	}
+
+	var (
+		id     int64
+		closed bool
+	)
+	err := w.db.QueryRowContext(ctx, "SELECT id, closedat IS NOT NULL FROM leaderboard_window WHERE kind = $1 AND period = $2;", kind, period).Scan(&id, &closed)
+	if err == sql.ErrNoRows {
+		return nil, "", status.Errorf(codes.NotFound, "no %s leaderboard for the period '%s'", kind, period)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return windowBoard{db: w.db, id: id, closed: closed}, period, nil
+}
+
+// windowBoard ranks the scores of a window.
+type windowBoard struct {
+	db     *sql.DB
+	id     int64
+	closed bool
+}
+
+func (wb windowBoard) Page(ctx context.Context, offset, limit int) ([]Player, int, error) {
+	var total int
+	if err := wb.db.QueryRowContext(ctx, "SELECT count(*) FROM window_score WHERE windowid = $1;", wb.id).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sqlStatement := `SELECT uid, score, level FROM window_score WHERE windowid = $1 ORDER BY ` + scoreOrder + ` LIMIT $2 OFFSET $3;`
+	if wb.closed {
+		sqlStatement = `SELECT uid, score, level FROM window_result WHERE windowid = $1 ORDER BY rank LIMIT $2 OFFSET $3;`
+	}
+	rows, err := wb.db.QueryContext(ctx, sqlStatement, wb.id, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var players []Player
+	for rows.Next() {
+		p := Player{Rank: offset + len(players) + 1}
+		if err = rows.Scan(&p.ID, &p.Score, &p.Level); err != nil {
+			return nil, 0, err
+		}
+		players = append(players, p)
+	}
+	return players, total, rows.Err()
+}
+
+func (wb windowBoard) Rank(ctx context.Context, id string) (Player, error) {
+	sqlStatement := `SELECT uid, score, level, rank FROM (SELECT uid, score, level, row_number() OVER (ORDER BY ` + scoreOrder + `) AS rank FROM window_score WHERE windowid = $1) ranked WHERE uid = $2;`
+	if wb.closed {
+		sqlStatement = `SELECT uid, score, level, rank FROM window_result WHERE windowid = $1 AND uid = $2;`
+	}
+
+	var p Player
+	err := wb.db.QueryRowContext(ctx, sqlStatement, wb.id, id).Scan(&p.ID, &p.Score, &p.Level, &p.Rank)
+	if err == sql.ErrNoRows {
+		return Player{}, ErrorNotFound
+	}
+	return p, err
+}
+
+// windowKind returns the kind of the windowed leaderboard.
+func windowKind(window fight.LeaderboardRequest_Window) (WindowKind, bool) {
+	switch window {
+	case fight.LeaderboardRequest_DAILY:
+		return Daily, true
+	case fight.LeaderboardRequest_WEEKLY:
+		return Weekly, true
+	case fight.LeaderboardRequest_SEASON:
+		return Season, true
+	default:
+		return "", false
+	}
+}
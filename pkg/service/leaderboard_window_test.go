@@ -0,0 +1,44 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowPeriod(t *testing.T) {
+	// 8 hours ahead of UTC
+	location := time.FixedZone("UTC+8", 8*60*60)
+	at := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, location)
+	}
+
+	tests := []struct {
+		kind   WindowKind
+		t      time.Time
+		period string
+		start  time.Time
+		end    time.Time
+	}{
+		{Daily, at(2021, 3, 11, 18), "2021-03-11", at(2021, 3, 11, 0), at(2021, 3, 12, 0)},
+		// still the previous day in UTC
+		{Daily, time.Date(2021, 3, 10, 17, 0, 0, 0, time.UTC).In(location), "2021-03-11", at(2021, 3, 11, 0), at(2021, 3, 12, 0)},
+		{Daily, at(2021, 12, 31, 23), "2021-12-31", at(2021, 12, 31, 0), at(2022, 1, 1, 0)},
+		// Thursday
+		{Weekly, at(2021, 3, 11, 18), "2021-W10", at(2021, 3, 8, 0), at(2021, 3, 15, 0)},
+		// Sunday is the last day of the week
+		{Weekly, at(2021, 3, 14, 23), "2021-W10", at(2021, 3, 8, 0), at(2021, 3, 15, 0)},
+		{Weekly, at(2021, 3, 15, 0), "2021-W11", at(2021, 3, 15, 0), at(2021, 3, 22, 0)},
+		// the first days of 2021 belong to the last week of 2020
+		{Weekly, at(2021, 1, 1, 12), "2020-W53", at(2020, 12, 28, 0), at(2021, 1, 4, 0)},
+		{Season, at(2021, 3, 31, 23), "2021-Q1", at(2021, 1, 1, 0), at(2021, 4, 1, 0)},
+		{Season, at(2021, 4, 1, 0), "2021-Q2", at(2021, 4, 1, 0), at(2021, 7, 1, 0)},
+		{Season, at(2021, 12, 1, 0), "2021-Q4", at(2021, 10, 1, 0), at(2022, 1, 1, 0)},
+	}
+	for _, test := range tests {
+		period, start, end := windowPeriod(test.kind, test.t)
+		if period != test.period || !start.Equal(test.start) || !end.Equal(test.end) {
+			t.Errorf("%s window of %v: want '%s' [%v, %v), but get '%s' [%v, %v)",
+				test.kind, test.t, test.period, test.start, test.end, period, start, end)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog"
+)
+
+// ErrLeaseLost is returned when the lease of a cached session expired,
+// the session has to be loaded again.
+var ErrLeaseLost = GameError{
+	Msg:  "session lease expired, load the session again",
+	Code: 409,
+}
+
+// OwnedElsewhereError is returned for the sessions owned by another replica.
+type OwnedElsewhereError struct {
+	UID     string
+	Owner   string
+	Address string
+}
+
+func (err OwnedElsewhereError) Error() string {
+	return fmt.Sprintf("session '%s' is owned by '%s'", err.UID, err.Owner)
+}
+
+// GRPCStatus carries the owner of the session, so that the caller is able
+// to redirect the request.
+func (err OwnedElsewhereError) GRPCStatus() *status.Status {
+	st := status.New(codes.FailedPrecondition, err.Error())
+	detailed, e := st.WithDetails(&fight.SessionOwner{
+		Instance: err.Owner,
+		Address:  err.Address,
+	})
+	if e != nil {
+		return st
+	}
+	return detailed
+}
+
+// LeaseManager keeps the ownership leases of the sessions in the
+// session_lease table, so that a session is live in one replica only.
+// A nil *LeaseManager owns every session.
+type LeaseManager struct {
+	db       *sql.DB
+	instance string
+	address  string
+	ttl      time.Duration
+
+	lock sync.Mutex
+	// the held leases, and when they expire as of their last renewal
+	held map[string]time.Time
+	stop chan struct{}
+}
+
+// NewLeaseManager creates a lease manager for the given instance, the
+// address is handed to the other replicas to redirect the requests.
+func NewLeaseManager(db *sql.DB, instance, address string, ttl time.Duration) *LeaseManager {
+	return &LeaseManager{
+		db:       db,
+		instance: instance,
+		address:  address,
+		ttl:      ttl,
+		held:     make(map[string]time.Time),
+		stop:     make(chan struct{}),
+	}
+}
+
+// Acquire takes the lease of the session, or returns OwnedElsewhereError
+// if another replica holds an unexpired lease.
+func (lm *LeaseManager) Acquire(ctx context.Context, uid string) error {
+	if lm == nil {
+		return nil
+	}
+
+	// the lease expires in the db no sooner than a ttl after the request
+	expiresAt := time.Now().Add(lm.ttl)
+	sqlStatement := `INSERT INTO session_lease(uid, owner, address, expiresat, heartbeatat) VALUES($1, $2, $3, now() + make_interval(secs => $4), now()) ON conflict (uid) DO UPDATE SET owner = $2, address = $3, expiresat = EXCLUDED.expiresat, heartbeatat = now() WHERE session_lease.owner = $2 OR session_lease.expiresat < now() RETURNING owner;`
+	var owner string
+	err := lm.db.QueryRowContext(ctx, sqlStatement, uid, lm.instance, lm.address, lm.ttl.Seconds()).Scan(&owner)
+	switch {
+	case err == sql.ErrNoRows:
+		if err = lm.ownedElsewhere(ctx, uid); err != nil {
+			return err
+		}
+		// the lease of the other replica expired in the meantime
+		return ErrLeaseLost
+	case err != nil:
+		return err
+	}
+
+	lm.lock.Lock()
+	defer lm.lock.Unlock()
+	lm.held[uid] = expiresAt
+	return nil
+}
+
+// Holds reports whether the lease of the session is held by this instance,
+// and not expired since its last renewal: another replica may acquire it
+// once it expired.
+func (lm *LeaseManager) Holds(uid string) bool {
+	if lm == nil {
+		return true
+	}
+
+	lm.lock.Lock()
+	defer lm.lock.Unlock()
+	expiresAt, ok := lm.held[uid]
+	return ok && time.Now().Before(expiresAt)
+}
+
+// Owner returns the error describing the owner of the session if it is
+// owned by another replica, or nil.
+func (lm *LeaseManager) Owner(ctx context.Context, uid string) error {
+	if lm == nil {
+		return nil
+	}
+	return lm.ownedElsewhere(ctx, uid)
+}
+
+func (lm *LeaseManager) ownedElsewhere(ctx context.Context, uid string) error {
+	var owner, address sql.NullString
+	err := lm.db.QueryRowContext(ctx, "SELECT owner, address FROM session_lease WHERE uid = $1 AND owner <> $2 AND expiresat >= now();", uid, lm.instance).Scan(&owner, &address)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil
+	case err != nil:
+		return err
+	}
+	return OwnedElsewhereError{
+		UID:     uid,
+		Owner:   owner.String,
+		Address: address.String,
+	}
+}
+
+// Release gives up the lease of the session.
+func (lm *LeaseManager) Release(uid string) error {
+	if lm == nil {
+		return nil
+	}
+
+	lm.lock.Lock()
+	delete(lm.held, uid)
+	lm.lock.Unlock()
+
+	_, err := lm.db.Exec("DELETE FROM session_lease WHERE uid = $1 AND owner = $2;", uid, lm.instance)
+	return err
+}
+
+// ReleaseAll gives up every lease held by this instance.
+func (lm *LeaseManager) ReleaseAll() error {
+	if lm == nil {
+		return nil
+	}
+
+	lm.lock.Lock()
+	lm.held = make(map[string]time.Time)
+	lm.lock.Unlock()
+
+	_, err := lm.db.Exec("DELETE FROM session_lease WHERE owner = $1;", lm.instance)
+	return err
+}
+
+// Run renews the held leases until Stop is called, lost is called with the
+// sessions whose lease is lost or expired.
+func (lm *LeaseManager) Run(lost func(uid string)) {
+	if lm == nil {
+		return
+	}
+
+	ticker := time.NewTicker(lm.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-lm.stop:
+			return
+		case <-ticker.C:
+			if err := lm.heartbeat(); err != nil {
+				klog.Warning(err)
+			}
+			for _, uid := range lm.expire(time.Now()) {
+				lost(uid)
+			}
+		}
+	}
+}
+
+// Stop stops renewing the leases.
+func (lm *LeaseManager) Stop() {
+	if lm == nil {
+		return
+	}
+	close(lm.stop)
+}
+
+// heartbeat extends the unexpired leases of this instance, the leases which
+// expired in the meantime are dropped by the next expire.
+func (lm *LeaseManager) heartbeat() error {
+	// the leases acquired while renewing are not taken into account
+	lm.lock.Lock()
+	var held = make(map[string]time.Time, len(lm.held))
+	for uid, expiresAt := range lm.held {
+		held[uid] = expiresAt
+	}
+	lm.lock.Unlock()
+	if len(held) == 0 {
+		return nil
+	}
+
+	renewedUntil := time.Now().Add(lm.ttl)
+	sqlStatement := `UPDATE session_lease SET expiresat = now() + make_interval(secs => $2), heartbeatat = now() WHERE owner = $1 AND expiresat >= now() RETURNING uid;`
+	rows, err := lm.db.Query(sqlStatement, lm.instance, lm.ttl.Seconds())
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var renewed = make(map[string]struct{})
+	for rows.Next() {
+		var uid string
+		if err = rows.Scan(&uid); err != nil {
+			return err
+		}
+		renewed[uid] = struct{}{}
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	lm.lock.Lock()
+	defer lm.lock.Unlock()
+	for uid, expiresAt := range held {
+		if current, ok := lm.held[uid]; !ok || !current.Equal(expiresAt) {
+			// released or acquired again in the meantime
+			continue
+		}
+		if _, ok := renewed[uid]; ok {
+			lm.held[uid] = renewedUntil
+		} else {
+			// expired in the db, another replica may hold it already
+			lm.held[uid] = time.Time{}
+		}
+	}
+	return nil
+}
+
+// expire drops the held leases expired by now, including the ones not
+// renewed for a ttl because the heartbeat failed, and returns their uids.
+func (lm *LeaseManager) expire(now time.Time) []string {
+	lm.lock.Lock()
+	defer lm.lock.Unlock()
+
+	var expired []string
+	for uid, expiresAt := range lm.held {
+		if !now.Before(expiresAt) {
+			klog.Warningf("lease of session '%s' is lost", uid)
+			delete(lm.held, uid)
+			expired = append(expired, uid)
+		}
+	}
+	return expired
+}
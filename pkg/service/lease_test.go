@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOwnedElsewhereStatus(t *testing.T) {
+	var err error = OwnedElsewhereError{UID: "1", Owner: "fight-server-1", Address: "10.0.0.1:8001"}
+
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.FailedPrecondition {
+		t.Fatalf("want FailedPrecondition, but get: '%v'", st)
+	}
+	if len(st.Details()) != 1 {
+		t.Fatalf("want the owner in the details, but get: '%v'", st.Details())
+	}
+	owner, ok := st.Details()[0].(*fight.SessionOwner)
+	if !ok || owner.Instance != "fight-server-1" || owner.Address != "10.0.0.1:8001" {
+		t.Errorf("want owner 'fight-server-1', but get: '%v'", st.Details()[0])
+	}
+}
+
+func TestNilLeaseManager(t *testing.T) {
+	var lm *LeaseManager
+	if !lm.Holds("1") {
+		t.Errorf("want the nil lease manager to hold every session")
+	}
+	if err := lm.Acquire(context.Background(), "1"); err != nil {
+		t.Errorf("want no error, but get: '%v'", err)
+	}
+}
+
+func TestLeaseExpiry(t *testing.T) {
+	db, f := newFakeDB(t)
+	lm := NewLeaseManager(db, "fight-server-1", "10.0.0.1:8001", time.Minute)
+	owner := []string{"owner"}
+
+	f.expect("INSERT INTO session_lease").returns(owner, []interface{}{"fight-server-1"})
+	if err := lm.Acquire(context.Background(), "1"); err != nil || !lm.Holds("1") {
+		t.Fatalf("want the lease held, but get: '%v'", err)
+	}
+
+	// the lease stays held until its expiry while the heartbeat fails
+	f.expect("UPDATE session_lease SET expiresat").fails(errors.New("connection refused"))
+	if err := lm.heartbeat(); err == nil {
+		t.Error("want the heartbeat failed, but get nil")
+	}
+	if !lm.Holds("1") {
+		t.Error("want the lease held until it expires")
+	}
+	if expired := lm.expire(time.Now()); len(expired) != 0 {
+		t.Errorf("want no lease expired, but get %v", expired)
+	}
+	if expired := lm.expire(time.Now().Add(time.Minute)); !reflect.DeepEqual(expired, []string{"1"}) {
+		t.Errorf("want the lease of '1' expired, but get %v", expired)
+	}
+	if lm.Holds("1") {
+		t.Error("want the expired lease not held")
+	}
+
+	// a lease not renewed is expired at once
+	f.expect("INSERT INTO session_lease").returns(owner, []interface{}{"fight-server-1"})
+	if err := lm.Acquire(context.Background(), "2"); err != nil {
+		t.Fatal(err)
+	}
+	f.expect("UPDATE session_lease SET expiresat").returns([]string{"uid"})
+	if err := lm.heartbeat(); err != nil {
+		t.Fatal(err)
+	}
+	if lm.Holds("2") {
+		t.Error("want the lease not renewed not held")
+	}
+}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/profanity"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	namePattern      = regexp.MustCompile(`^[\pL\pN_\- ]{3,20}$`)
+	avatarKeyPattern = regexp.MustCompile(`^[A-Za-z0-9/._\-]{0,200}$`)
+)
+
+// anonymousPrefix starts the labels of the players without a profile, it
+// is reserved so that no player takes the label of another one.
+const anonymousPrefix = "player-"
+
+// displayNameTTL is how long the display names are cached, the changes made
+// by the other replicas show up in the leaderboards after it at the latest.
+const displayNameTTL = time.Minute
+
+// uniqueViolation is the postgres error code of the unique constraints.
+const uniqueViolation = "23505"
+
+// validateName checks a display name or a guild name.
+func validateName(kind, name string) error {
+	switch {
+	case !namePattern.MatchString(name):
+		return status.Errorf(codes.InvalidArgument, "%s must be 3 to 20 letters, digits, spaces, '_' or '-'", kind)
+	case profanity.Contains(name):
+		return status.Errorf(codes.InvalidArgument, "%s is not allowed", kind)
+	case strings.HasPrefix(strings.ToLower(name), anonymousPrefix):
+		return status.Errorf(codes.InvalidArgument, "%s must not start with '%s'", kind, anonymousPrefix)
+	}
+	return nil
+}
+
+// validateProfile checks the display name and the avatar key.
+func validateProfile(profile module.Profile) error {
+	if err := validateName("display name", profile.DisplayName); err != nil {
+		return err
+	}
+	switch {
+	case profile.UID == "":
+		return status.Error(codes.InvalidArgument, "profile id is required")
+	case !avatarKeyPattern.MatchString(profile.AvatarKey):
+		return status.Error(codes.InvalidArgument, "avatar key must be at most 200 letters, digits, '/', '.', '_' or '-'")
+	}
+	return nil
+}
+
+// WithAnonymizeSecret sets the secret of the anonymized labels and the
+// player keys, shared by the replicas so that they show the same labels.
+func WithAnonymizeSecret(secret []byte) Option {
+	return func(s *Service) {
+		s.profiles.secret = secret
+	}
+}
+
+// anonymize returns the label shown in the leaderboards for the players
+// without a profile. It is keyed by the server secret, so that the id of the
+// player is not found by hashing the candidate ids. The 64 bits of the hash
+// keep the collisions unlikely up to billions of players.
+func (ps *Profiles) anonymize(id string) string {
+	return anonymousPrefix + hex.EncodeToString(ps.sum("label:" + id)[:8])
+}
+
+// playerKey returns the opaque id of the player in the leaderboard entries,
+// which is stable across the renames and does not reveal the id either.
+func (ps *Profiles) playerKey(id string) string {
+	return hex.EncodeToString(ps.sum("player-key:" + id)[:16])
+}
+
+func (ps *Profiles) sum(message string) []byte {
+	mac := hmac.New(sha256.New, ps.secret)
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
+
+// Profiles keeps the profiles of the players in the player table, and
+// caches their display names for the leaderboards.
+type Profiles struct {
+	db *sql.DB
+	// the key of the anonymized labels and the player keys
+	secret []byte
+
+	lock  sync.Mutex
+	names map[string]cachedName
+}
+
+type cachedName struct {
+	name    string
+	expires time.Time
+}
+
+// NewProfiles ...
+func NewProfiles(db *sql.DB) *Profiles {
+	return &Profiles{
+		db:    db,
+		names: make(map[string]cachedName),
+	}
+}
+
+// Create creates the profile, it fails with AlreadyExists if the player
+// already has a profile or the display name is taken.
+func (ps *Profiles) Create(ctx context.Context, profile module.Profile) (module.Profile, error) {
+	if err := validateProfile(profile); err != nil {
+		return profile, err
+	}
+
+	sqlStatement := `INSERT INTO player(uid, displayname, avatarkey, createdat) VALUES($1, $2, $3, now()) RETURNING createdat;`
+	err := ps.db.QueryRowContext(ctx, sqlStatement, profile.UID, profile.DisplayName, profile.AvatarKey).Scan(&profile.CreatedAt)
+	if err != nil {
+		return profile, profileError(err)
+	}
+	ps.cache(profile.UID, profile.DisplayName)
+	return profile, nil
+}
+
+// Get returns the profile, or ErrorNotFound.
+func (ps *Profiles) Get(ctx context.Context, uid string) (module.Profile, error) {
+	profile := module.Profile{UID: uid}
+	var avatarKey sql.NullString
+	err := ps.db.QueryRowContext(ctx, "SELECT displayname, avatarkey, createdat FROM player WHERE uid = $1;", uid).Scan(&profile.DisplayName, &avatarKey, &profile.CreatedAt)
+	if err == sql.ErrNoRows {
+		return profile, ErrorNotFound
+	}
+	profile.AvatarKey = avatarKey.String
+	return profile, err
+}
+
+// Update changes the display name and the avatar key of the profile.
+func (ps *Profiles) Update(ctx context.Context, profile module.Profile) (module.Profile, error) {
+	if err := validateProfile(profile); err != nil {
+		return profile, err
+	}
+
+	sqlStatement := `UPDATE player SET displayname = $2, avatarkey = $3 WHERE uid = $1 RETURNING createdat;`
+	err := ps.db.QueryRowContext(ctx, sqlStatement, profile.UID, profile.DisplayName, profile.AvatarKey).Scan(&profile.CreatedAt)
+	if err == sql.ErrNoRows {
+		return profile, ErrorNotFound
+	}
+	if err != nil {
+		return profile, profileError(err)
+	}
+	ps.cache(profile.UID, profile.DisplayName)
+	return profile, nil
+}
+
+// Delete deletes the profile, the player is anonymized in the leaderboards.
+func (ps *Profiles) Delete(ctx context.Context, uid string) error {
+	result, err := ps.db.ExecContext(ctx, "DELETE FROM player WHERE uid = $1;", uid)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrorNotFound
+	}
+	ps.cache(uid, ps.anonymize(uid))
+	return nil
+}
+
+// lookup returns the id of the player of the display name.
+func (ps *Profiles) lookup(ctx context.Context, displayName string) (string, error) {
+	var uid string
+	err := ps.db.QueryRowContext(ctx, "SELECT uid FROM player WHERE lower(displayname) = lower($1);", displayName).Scan(&uid)
+	if err == sql.ErrNoRows {
+		return "", status.Errorf(codes.NotFound, "no player named '%s'", displayName)
+	}
+	return uid, err
+}
+
+func (ps *Profiles) cache(uid, name string) {
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+	ps.names[uid] = cachedName{name: name, expires: time.Now().Add(displayNameTTL)}
+}
+
+// resolve sets the display names and the keys of the players, the
+// anonymized label for the players without a profile.
+func (ps *Profiles) resolve(ctx context.Context, players []Player) error {
+	var (
+		now     = time.Now()
+		missing []string
+	)
+	ps.lock.Lock()
+	for i := range players {
+		players[i].Key = ps.playerKey(players[i].ID)
+		if cached, ok := ps.names[players[i].ID]; ok && now.Before(cached.expires) {
+			players[i].DisplayName = cached.name
+		} else {
+			missing = append(missing, players[i].ID)
+		}
+	}
+	ps.lock.Unlock()
+	if len(missing) == 0 {
+		return nil
+	}
+
+	var names = make(map[string]string, len(missing))
+	for _, id := range missing {
+		names[id] = ps.anonymize(id)
+	}
+	rows, err := ps.db.QueryContext(ctx, "SELECT uid, displayname FROM player WHERE uid = ANY($1);", pq.Array(missing))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, name string
+		if err = rows.Scan(&id, &name); err != nil {
+			return err
+		}
+		names[id] = name
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for id, name := range names {
+		ps.cache(id, name)
+	}
+	for i := range players {
+		if name, ok := names[players[i].ID]; ok {
+			players[i].DisplayName = name
+		}
+	}
+	return nil
+}
+
+// profileError turns the unique violations into AlreadyExists.
+func profileError(err error) error {
+	if e, ok := err.(*pq.Error); ok && e.Code == uniqueViolation {
+		if e.Constraint == "player_pkey" {
+			return status.Error(codes.AlreadyExists, "profile already exists")
+		}
+		return status.Error(codes.AlreadyExists, "display name is taken")
+	}
+	return err
+}
+
+// CreateProfile ...
+func (s *Service) CreateProfile(ctx context.Context, req *fight.Profile) (*fight.Profile, error) {
+	if _, err := s.authorize(ctx, req.GetId()); err != nil {
+		return &fight.Profile{}, err
+	}
+
+	profile, err := s.profiles.Create(ctx, convertFightProfile2ModuleProfile(req))
+	if err != nil {
+		return &fight.Profile{}, err
+	}
+	return convertModuleProfile2FightProfile(profile), nil
+}
+
+// GetProfile ...
+func (s *Service) GetProfile(ctx context.Context, req *fight.GetProfileRequest) (*fight.Profile, error) {
+	profile, err := s.profiles.Get(ctx, req.GetId())
+	if err == ErrorNotFound {
+		return &fight.Profile{}, status.Errorf(codes.NotFound, "no profile for '%s'", req.GetId())
+	}
+	if err != nil {
+		return &fight.Profile{}, err
+	}
+	return convertModuleProfile2FightProfile(profile), nil
+}
+
+// UpdateProfile ...
+func (s *Service) UpdateProfile(ctx context.Context, req *fight.Profile) (*fight.Profile, error) {
+	if _, err := s.authorize(ctx, req.GetId()); err != nil {
+		return &fight.Profile{}, err
+	}
+
+	profile, err := s.profiles.Update(ctx, convertFightProfile2ModuleProfile(req))
+	if err == ErrorNotFound {
+		return &fight.Profile{}, status.Errorf(codes.NotFound, "no profile for '%s'", req.GetId())
+	}
+	if err != nil {
+		return &fight.Profile{}, err
+	}
+	return convertModuleProfile2FightProfile(profile), nil
+}
+
+// DeleteProfile ...
+func (s *Service) DeleteProfile(ctx context.Context, req *fight.DeleteProfileRequest) (*fight.DeleteProfileResponse, error) {
+	if _, err := s.authorize(ctx, req.GetId()); err != nil {
+		return &fight.DeleteProfileResponse{}, err
+	}
+
+	err := s.profiles.Delete(ctx, req.GetId())
+	if err == ErrorNotFound {
+		return &fight.DeleteProfileResponse{}, status.Errorf(codes.NotFound, "no profile for '%s'", req.GetId())
+	}
+	if err != nil {
+		return &fight.DeleteProfileResponse{}, err
+	}
+	return &fight.DeleteProfileResponse{
+		Msg: fmt.Sprintf("profile of '%s' is deleted", req.GetId()),
+	}, nil
+}
+
+func convertFightProfile2ModuleProfile(profile *fight.Profile) module.Profile {
+	return module.Profile{
+		UID:         profile.GetId(),
+		DisplayName: profile.GetDisplayName(),
+		AvatarKey:   profile.GetAvatarKey(),
+	}
+}
+
+func convertModuleProfile2FightProfile(profile module.Profile) *fight.Profile {
+	return &fight.Profile{
+		Id:          profile.UID,
+		DisplayName: profile.DisplayName,
+		AvatarKey:   profile.AvatarKey,
+		CreatedAt:   timestamppb.New(profile.CreatedAt),
+	}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidateProfile(t *testing.T) {
+	tests := []struct {
+		profile module.Profile
+		want    codes.Code
+	}{
+		{module.Profile{UID: "1", DisplayName: "Charlie"}, codes.OK},
+		{module.Profile{UID: "1", DisplayName: "小明 the_3rd", AvatarKey: "avatars/1.png"}, codes.OK},
+		{module.Profile{DisplayName: "Charlie"}, codes.InvalidArgument},
+		{module.Profile{UID: "1", DisplayName: "Al"}, codes.InvalidArgument},
+		{module.Profile{UID: "1", DisplayName: "a name longer than twenty"}, codes.InvalidArgument},
+		{module.Profile{UID: "1", DisplayName: "<script>"}, codes.InvalidArgument},
+		{module.Profile{UID: "1", DisplayName: "sh1t_happens"}, codes.InvalidArgument},
+		{module.Profile{UID: "1", DisplayName: "Charlie", AvatarKey: "../../etc passwd"}, codes.InvalidArgument},
+		{module.Profile{UID: "1", DisplayName: "player-1a2b3c4d"}, codes.InvalidArgument},
+		{module.Profile{UID: "1", DisplayName: "Player-x"}, codes.InvalidArgument},
+	}
+	for _, test := range tests {
+		if got := status.Code(validateProfile(test.profile)); got != test.want {
+			t.Errorf("'%s' '%s': want %v, but get %v", test.profile.DisplayName, test.profile.AvatarKey, test.want, got)
+		}
+	}
+}
+
+func TestAnonymize(t *testing.T) {
+	ps := &Profiles{secret: []byte("secret")}
+	if ps.anonymize("1") != ps.anonymize("1") {
+		t.Errorf("want the label stable")
+	}
+	if ps.anonymize("1") == ps.anonymize("2") {
+		t.Errorf("want different labels for different players")
+	}
+	if label := ps.anonymize("1"); len(label) != len(anonymousPrefix)+16 {
+		t.Errorf("want a label of 64 bits, but get '%s'", label)
+	}
+	if ps.playerKey("1") == ps.playerKey("2") || ps.playerKey("1") == ps.anonymize("1")[len(anonymousPrefix):] {
+		t.Errorf("want different keys for different players and labels")
+	}
+
+	// the labels are not found without the secret
+	other := &Profiles{secret: []byte("other")}
+	if other.anonymize("1") == ps.anonymize("1") || other.playerKey("1") == ps.playerKey("1") {
+		t.Errorf("want the labels and the keys keyed by the secret")
+	}
+}
+
+func TestResolveCached(t *testing.T) {
+	// no db: the cached names must not reach it
+	ps := NewProfiles(nil)
+	ps.cache("1", "Charlie")
+	ps.cache("2", ps.anonymize("2"))
+
+	players := []Player{{ID: "1"}, {ID: "2"}}
+	if err := ps.resolve(context.Background(), players); err != nil {
+		t.Fatal(err)
+	}
+	if players[0].DisplayName != "Charlie" || players[1].DisplayName != ps.anonymize("2") || players[1].Key != ps.playerKey("2") {
+		t.Errorf("want the cached names, but get: '%v'", players)
+	}
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// rankLess reports whether a is ranked before b: the higher score first,
+// then the higher level, then the player who reached the score first, then
+// the lower id. The order is total, so that the players of equal scores
+// keep their places between the pushes and across the replicas. The order
+// of the score tables, scoreOrder, is the same.
+func rankLess(a, b Player) bool {
+	switch {
+	case a.Score != b.Score:
+		return a.Score > b.Score
+	case a.Level != b.Level:
+		return a.Level > b.Level
+	case !a.ScoreUpdatedAt.Equal(b.ScoreUpdatedAt):
+		return a.ScoreUpdatedAt.Before(b.ScoreUpdatedAt)
+	default:
+		return a.ID < b.ID
+	}
+}
+
+// scoreOrder is the ORDER BY clause of the score tables matching rankLess,
+// their updatedat column is the time the score was reached.
+const scoreOrder = "score DESC, level DESC, updatedat, uid"
+
+// ranking is the ranking index of the sessions, kept apart from the shards
+// of the store. It is updated incrementally on every change of a session,
+// and answers the rank queries in O(log n).
+type ranking struct {
+	lock    sync.RWMutex
+	players map[string]Player
+	list    *skiplist
+}
+
+func newRanking() *ranking {
+	return &ranking{
+		players: make(map[string]Player),
+		list:    newSkiplist(),
+	}
+}
+
+func (r *ranking) set(p Player) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if old, ok := r.players[p.ID]; ok {
+		if old == p {
+			return
+		}
+		r.list.delete(old)
+	}
+	r.players[p.ID] = p
+	r.list.insert(p)
+}
+
+func (r *ranking) remove(id string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if old, ok := r.players[id]; ok {
+		r.list.delete(old)
+		delete(r.players, id)
+	}
+}
+
+// page returns at most limit players ranked after offset, and the number of
+// ranked players.
+func (r *ranking) page(offset, limit int) ([]Player, int) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	var players []Player
+	for x := r.list.byRank(offset + 1); x != nil && len(players) < limit; x = x.next[0] {
+		p := x.player
+		p.Rank = offset + len(players) + 1
+		players = append(players, p)
+	}
+	return players, r.list.length
+}
+
+// rank returns the player with its rank.
+func (r *ranking) rank(id string) (Player, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	p, ok := r.players[id]
+	if !ok {
+		return Player{}, false
+	}
+	p.Rank = r.list.rank(p)
+	return p, true
+}
+
+const (
+	skiplistMaxLevel = 32
+	// the probability of a node to be promoted to the next level is 1/skiplistP
+	skiplistP = 4
+)
+
+// skiplist keeps the players in the ranking order. Each link carries its
+// span, the number of nodes it skips, so that the rank of a node is the sum
+// of the spans on the path to it.
+type skiplist struct {
+	head   *skiplistNode
+	level  int
+	length int
+	rand   *rand.Rand
+}
+
+type skiplistNode struct {
+	player Player
+	next   []*skiplistNode
+	span   []int
+}
+
+func newSkiplist() *skiplist {
+	return &skiplist{
+		head: &skiplistNode{
+			next: make([]*skiplistNode, skiplistMaxLevel),
+			span: make([]int, skiplistMaxLevel),
+		},
+		level: 1,
+		rand:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (sl *skiplist) randomLevel() int {
+	level := 1
+	for level < skiplistMaxLevel && sl.rand.Intn(skiplistP) == 0 {
+		level++
+	}
+	return level
+}
+
+func (sl *skiplist) insert(p Player) {
+	var (
+		update [skiplistMaxLevel]*skiplistNode
+		rank   [skiplistMaxLevel]int
+		x      = sl.head
+	)
+	for i := sl.level - 1; i >= 0; i-- {
+		if i < sl.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.next[i] != nil && rankLess(x.next[i].player, p) {
+			rank[i] += x.span[i]
+			x = x.next[i]
+		}
+		update[i] = x
+	}
+
+	level := sl.randomLevel()
+	if level > sl.level {
+		for i := sl.level; i < level; i++ {
+			rank[i] = 0
+			update[i] = sl.head
+			update[i].span[i] = sl.length
+		}
+		sl.level = level
+	}
+
+	n := &skiplistNode{
+		player: p,
+		next:   make([]*skiplistNode, level),
+		span:   make([]int, level),
+	}
+	for i := 0; i < level; i++ {
+		n.next[i] = update[i].next[i]
+		update[i].next[i] = n
+		n.span[i] = update[i].span[i] - (rank[0] - rank[i])
+		update[i].span[i] = rank[0] - rank[i] + 1
+	}
+	for i := level; i < sl.level; i++ {
+		update[i].span[i]++
+	}
+	sl.length++
+}
+
+func (sl *skiplist) delete(p Player) bool {
+	var (
+		update [skiplistMaxLevel]*skiplistNode
+		x      = sl.head
+	)
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && rankLess(x.next[i].player, p) {
+			x = x.next[i]
+		}
+		update[i] = x
+	}
+
+	x = x.next[0]
+	if x == nil || x.player != p {
+		return false
+	}
+	for i := 0; i < sl.level; i++ {
+		if update[i].next[i] == x {
+			update[i].span[i] += x.span[i] - 1
+			update[i].next[i] = x.next[i]
+		} else {
+			update[i].span[i]--
+		}
+	}
+	for sl.level > 1 && sl.head.next[sl.level-1] == nil {
+		sl.level--
+	}
+	sl.length--
+	return true
+}
+
+// rank returns the 1-based rank of the player, or 0 if it is not found.
+func (sl *skiplist) rank(p Player) int {
+	var (
+		rank = 0
+		x    = sl.head
+	)
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && !rankLess(p, x.next[i].player) {
+			rank += x.span[i]
+			x = x.next[i]
+		}
+		if x != sl.head && x.player == p {
+			return rank
+		}
+	}
+	return 0
+}
+
+// byRank returns the node of the 1-based rank, or nil if it is out of range.
+func (sl *skiplist) byRank(rank int) *skiplistNode {
+	var (
+		traversed = 0
+		x         = sl.head
+	)
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && traversed+x.span[i] <= rank {
+			traversed += x.span[i]
+			x = x.next[i]
+		}
+		if traversed == rank && x != sl.head {
+			return x
+		}
+	}
+	return nil
+}
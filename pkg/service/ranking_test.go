@@ -0,0 +1,166 @@
+package service
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+	"testing/quick"
+	"time"
+)
+
+func TestRanking(t *testing.T) {
+	r := newRanking()
+	want := make(map[string]Player)
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		id := strconv.Itoa(rnd.Intn(500))
+		if rnd.Intn(5) == 0 {
+			r.remove(id)
+			delete(want, id)
+			continue
+		}
+		p := Player{ID: id, Score: rnd.Intn(50) * 10, Level: rnd.Intn(5) + 1}
+		r.set(p)
+		want[id] = p
+	}
+
+	sorted := make([]Player, 0, len(want))
+	for _, p := range want {
+		sorted = append(sorted, p)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return rankLess(sorted[i], sorted[j])
+	})
+	for i := range sorted {
+		sorted[i].Rank = i + 1
+	}
+
+	players, total := r.page(0, len(sorted)+10)
+	if total != len(sorted) || len(players) != len(sorted) {
+		t.Fatalf("want %d players, but get %d of total %d", len(sorted), len(players), total)
+	}
+	for i := range sorted {
+		if players[i] != sorted[i] {
+			t.Fatalf("rank %d: want %+v, but get %+v", i+1, sorted[i], players[i])
+		}
+		p, ok := r.rank(sorted[i].ID)
+		if !ok || p != sorted[i] {
+			t.Fatalf("want %+v, but get %+v", sorted[i], p)
+		}
+	}
+
+	for _, offset := range []int{0, 1, 7, len(sorted) - 3, len(sorted), len(sorted) + 1} {
+		players, _ := r.page(offset, 5)
+		for i, p := range players {
+			if p != sorted[offset+i] {
+				t.Fatalf("offset %d: want %+v, but get %+v", offset, sorted[offset+i], p)
+			}
+		}
+		wantLen := len(sorted) - offset
+		if wantLen > 5 {
+			wantLen = 5
+		} else if wantLen < 0 {
+			wantLen = 0
+		}
+		if len(players) != wantLen {
+			t.Fatalf("offset %d: want %d players, but get %d", offset, wantLen, len(players))
+		}
+	}
+
+	if _, ok := r.rank("unknown"); ok {
+		t.Errorf("want unknown player not ranked")
+	}
+}
+
+// tiedPlayers have a few distinct scores, levels and score times, so that
+// most of them tie on the score and many on everything but the id.
+type tiedPlayers []Player
+
+func (tiedPlayers) Generate(rnd *rand.Rand, size int) reflect.Value {
+	base := time.Date(2021, 3, 11, 18, 25, 6, 0, time.UTC)
+	players := make(tiedPlayers, 1+rnd.Intn(10*size))
+	for i := range players {
+		players[i] = Player{
+			ID:             strconv.Itoa(rnd.Intn(1 << 30)),
+			Score:          rnd.Intn(3) * 10,
+			Level:          1 + rnd.Intn(2),
+			ScoreUpdatedAt: base.Add(time.Duration(rnd.Intn(3)) * time.Millisecond),
+		}
+	}
+	return reflect.ValueOf(players)
+}
+
+func TestRankLessIsStrictTotalOrder(t *testing.T) {
+	property := func(players tiedPlayers) bool {
+		if len(players) > 30 {
+			players = players[:30]
+		}
+		for _, a := range players {
+			if rankLess(a, a) {
+				return false
+			}
+			for _, b := range players {
+				// exactly one of two different players is ranked first
+				if a.ID != b.ID && rankLess(a, b) == rankLess(b, a) {
+					return false
+				}
+				for _, c := range players {
+					if rankLess(a, b) && rankLess(b, c) && !rankLess(a, c) {
+						return false
+					}
+				}
+			}
+		}
+		return true
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRankingIgnoresUpdateOrder(t *testing.T) {
+	property := func(players tiedPlayers, seed int64) bool {
+		// the last update of a player wins
+		var latest = make(map[string]Player)
+		for _, p := range players {
+			latest[p.ID] = p
+		}
+
+		var (
+			forward  = newRanking()
+			shuffled = newRanking()
+			order    = rand.New(rand.NewSource(seed)).Perm(len(players))
+		)
+		for _, p := range players {
+			forward.set(p)
+		}
+		for _, i := range order {
+			shuffled.set(players[i])
+		}
+		// bring the shuffled ranking to the same final state
+		for _, p := range latest {
+			shuffled.set(p)
+		}
+
+		want := make([]Player, 0, len(latest))
+		for _, p := range latest {
+			want = append(want, p)
+		}
+		sort.Slice(want, func(i, j int) bool {
+			return rankLess(want[i], want[j])
+		})
+		for i := range want {
+			want[i].Rank = i + 1
+		}
+
+		got, _ := forward.page(0, len(want))
+		again, _ := shuffled.page(0, len(want))
+		return reflect.DeepEqual(got, want) && reflect.DeepEqual(again, want)
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,384 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Records keeps the best score reached with each hero in the hero_score
+// table, and the fastest clear of each boss level in the level_clear table.
+// The changes made by this instance are pushed to the watchers of the
+// leaderboard. A nil *Records does not persist anything.
+type Records struct {
+	db *sql.DB
+
+	lock sync.Mutex
+	// the best scores with each hero known to this instance
+	best map[heroPlayer]int
+	// the hub of each leaderboard, created by the first watcher and deleted
+	// with the last one
+	hubs map[string]*recordHub
+}
+
+type recordHub struct {
+	*Hub
+	watchers int
+}
+
+type heroPlayer struct {
+	hero string
+	uid  string
+}
+
+// NewRecords ...
+func NewRecords(db *sql.DB) *Records {
+	return &Records{
+		db:   db,
+		best: make(map[heroPlayer]int),
+		hubs: make(map[string]*recordHub),
+	}
+}
+
+func heroBoardKey(hero string) string {
+	return "hero/" + hero
+}
+
+// levelBoardPrefix prefixes the keys of the level leaderboards.
+const levelBoardPrefix = "level/"
+
+func levelBoardKey(level int) string {
+	return levelBoardPrefix + strconv.Itoa(level)
+}
+
+// RaiseHero saves the score of the player with the hero in the turn if it
+// beats the best one, and reports whether it did.
+func (r *Records) RaiseHero(ctx context.Context, t *turnTx, uid, hero string, score, level int) (bool, error) {
+	if r == nil || hero == "" {
+		return false, nil
+	}
+
+	key := heroPlayer{hero: hero, uid: uid}
+	r.lock.Lock()
+	best, ok := r.best[key]
+	r.lock.Unlock()
+	if ok && score <= best {
+		return false, nil
+	}
+
+	raised := true
+	sqlStatement := `INSERT INTO hero_score(heroname, uid, score, level, updatedat) VALUES($1, $2, $3, $4, now()) ON conflict (heroname, uid) DO UPDATE SET score = $3, level = $4, updatedat = now() WHERE hero_score.score < $3 RETURNING score;`
+	err := t.QueryRowContext(ctx, sqlStatement, hero, uid, score, level).Scan(&best)
+	if err == sql.ErrNoRows {
+		raised = false
+		err = t.QueryRowContext(ctx, "SELECT score FROM hero_score WHERE heroname = $1 AND uid = $2;", hero, uid).Scan(&best)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	t.onCommit(func() {
+		r.lock.Lock()
+		if best > r.best[key] {
+			r.best[key] = best
+		}
+		r.lock.Unlock()
+
+		if raised {
+			r.publish(heroBoardKey(hero))
+		}
+	})
+	return raised, nil
+}
+
+// forgetHero forgets the cached best scores with the deleted hero.
+func (r *Records) forgetHero(hero string) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for key := range r.best {
+		if key.hero == hero {
+			delete(r.best, key)
+		}
+	}
+}
+
+// RecordClear saves the time the player took to clear the level in the
+// turn if it is the fastest one, and reports whether it is.
+func (r *Records) RecordClear(ctx context.Context, t *turnTx, uid, hero string, level int, clearTime time.Duration) (bool, error) {
+	if r == nil {
+		return false, nil
+	}
+
+	sqlStatement := `INSERT INTO level_clear(level, uid, heroname, cleartime, clearedat) VALUES($1, $2, $3, $4, now()) ON conflict (level, uid) DO UPDATE SET heroname = $3, cleartime = $4, clearedat = now() WHERE level_clear.cleartime > $4;`
+	result, err := t.ExecContext(ctx, sqlStatement, level, uid, hero, clearTime.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil || n == 0 {
+		return false, err
+	}
+
+	t.onCommit(func() { r.publish(levelBoardKey(level)) })
+	return true, nil
+}
+
+// Subscribe returns a channel signaled when the leaderboard of the key
+// changes, and the function to unsubscribe. The hub of the leaderboard is
+// deleted when its last watcher unsubscribes.
+func (r *Records) Subscribe(key string) (<-chan struct{}, func()) {
+	r.lock.Lock()
+	hub, ok := r.hubs[key]
+	if !ok {
+		hub = &recordHub{Hub: NewHub(pushInterval)}
+		r.hubs[key] = hub
+	}
+	hub.watchers++
+	r.lock.Unlock()
+
+	signal, unsubscribe := hub.Subscribe()
+	var once sync.Once
+	return signal, func() {
+		once.Do(func() {
+			unsubscribe()
+			r.lock.Lock()
+			defer r.lock.Unlock()
+			if hub.watchers--; hub.watchers == 0 {
+				delete(r.hubs, key)
+			}
+		})
+	}
+}
+
+// publish signals the watchers of the leaderboard, if there are any.
+func (r *Records) publish(key string) {
+	r.lock.Lock()
+	hub, ok := r.hubs[key]
+	r.lock.Unlock()
+	if ok {
+		hub.Publish()
+	}
+}
+
+// publishLevels signals the watchers of every level leaderboard, once the
+// bosses moved to other levels with their clears.
+func (r *Records) publishLevels() error {
+	if r == nil {
+		return nil
+	}
+	r.lock.Lock()
+	var hubs []*recordHub
+	for key, hub := range r.hubs {
+		if strings.HasPrefix(key, levelBoardPrefix) {
+			hubs = append(hubs, hub)
+		}
+	}
+	r.lock.Unlock()
+	for _, hub := range hubs {
+		hub.Publish()
+	}
+	return nil
+}
+
+// heroBoard ranks the best scores reached with a hero.
+type heroBoard struct {
+	db   *sql.DB
+	hero string
+}
+
+func (hb heroBoard) Page(ctx context.Context, offset, limit int) ([]Player, int, error) {
+	var total int
+	if err := hb.db.QueryRowContext(ctx, "SELECT count(*) FROM hero_score WHERE heroname = $1;", hb.hero).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := hb.db.QueryContext(ctx, "SELECT uid, score, level FROM hero_score WHERE heroname = $1 ORDER BY "+scoreOrder+" LIMIT $2 OFFSET $3;", hb.hero, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var players []Player
+	for rows.Next() {
+		p := Player{HeroName: hb.hero, Rank: offset + len(players) + 1}
+		if err = rows.Scan(&p.ID, &p.Score, &p.Level); err != nil {
+			return nil, 0, err
+		}
+		players = append(players, p)
+	}
+	return players, total, rows.Err()
+}
+
+func (hb heroBoard) Rank(ctx context.Context, id string) (Player, error) {
+	sqlStatement := `SELECT uid, score, level, rank FROM (SELECT uid, score, level, row_number() OVER (ORDER BY ` + scoreOrder + `) AS rank FROM hero_score WHERE heroname = $1) ranked WHERE uid = $2;`
+	p := Player{HeroName: hb.hero}
+	err := hb.db.QueryRowContext(ctx, sqlStatement, hb.hero, id).Scan(&p.ID, &p.Score, &p.Level, &p.Rank)
+	if err == sql.ErrNoRows {
+		return Player{}, ErrorNotFound
+	}
+	return p, err
+}
+
+// clearOrder is the ORDER BY clause of the level_clear table: the fastest
+// clear first, then the earliest one, then the lower id.
+const clearOrder = "cleartime, clearedat, uid"
+
+// levelBoard ranks the fastest clears of a boss level.
+type levelBoard struct {
+	db    *sql.DB
+	level int
+}
+
+func (lb levelBoard) Page(ctx context.Context, offset, limit int) ([]Player, int, error) {
+	var total int
+	if err := lb.db.QueryRowContext(ctx, "SELECT count(*) FROM level_clear WHERE level = $1;", lb.level).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := lb.db.QueryContext(ctx, "SELECT uid, coalesce(heroname, ''), cleartime FROM level_clear WHERE level = $1 ORDER BY "+clearOrder+" LIMIT $2 OFFSET $3;", lb.level, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var players []Player
+	for rows.Next() {
+		var (
+			p         = Player{Level: lb.level, Rank: offset + len(players) + 1}
+			clearTime int64
+		)
+		if err = rows.Scan(&p.ID, &p.HeroName, &clearTime); err != nil {
+			return nil, 0, err
+		}
+		p.ClearTime = time.Duration(clearTime) * time.Millisecond
+		players = append(players, p)
+	}
+	return players, total, rows.Err()
+}
+
+func (lb levelBoard) Rank(ctx context.Context, id string) (Player, error) {
+	sqlStatement := `SELECT uid, coalesce(heroname, ''), cleartime, rank FROM (SELECT uid, heroname, cleartime, row_number() OVER (ORDER BY ` + clearOrder + `) AS rank FROM level_clear WHERE level = $1) ranked WHERE uid = $2;`
+	var (
+		p         = Player{Level: lb.level}
+		clearTime int64
+	)
+	err := lb.db.QueryRowContext(ctx, sqlStatement, lb.level, id).Scan(&p.ID, &p.HeroName, &clearTime, &p.Rank)
+	if err == sql.ErrNoRows {
+		return Player{}, ErrorNotFound
+	}
+	p.ClearTime = time.Duration(clearTime) * time.Millisecond
+	return p, err
+}
+
+// HeroLeaderboard ...
+func (s *Service) HeroLeaderboard(ctx context.Context, req *fight.HeroLeaderboardRequest) (*fight.LeaderboardResponse, error) {
+	b, page, err := s.heroBoard(req)
+	if err != nil {
+		return &fight.LeaderboardResponse{}, err
+	}
+	resp, err := s.listBoard(ctx, b, page)
+	if err != nil {
+		return &fight.LeaderboardResponse{}, err
+	}
+	return resp, nil
+}
+
+// WatchHeroLeaderboard ...
+func (s *Service) WatchHeroLeaderboard(req *fight.HeroLeaderboardRequest, stream fight.FightSvc_WatchHeroLeaderboardServer) error {
+	b, page, err := s.heroBoard(req)
+	if err != nil {
+		return err
+	}
+	err = s.db.QueryRowContext(stream.Context(), "SELECT 1 FROM hero WHERE name = $1;", req.GetHeroName()).Scan(new(int))
+	if err == sql.ErrNoRows {
+		return status.Errorf(codes.NotFound, "undefined hero: '%s'", req.GetHeroName())
+	}
+	if err != nil {
+		return err
+	}
+	return s.watchBoard(stream.Context(), heroBoardKey(req.GetHeroName()), b, page, stream.Send)
+}
+
+// LevelLeaderboard ...
+func (s *Service) LevelLeaderboard(ctx context.Context, req *fight.LevelLeaderboardRequest) (*fight.LeaderboardResponse, error) {
+	b, page, err := s.levelBoard(req)
+	if err != nil {
+		return &fight.LeaderboardResponse{}, err
+	}
+	resp, err := s.listBoard(ctx, b, page)
+	if err != nil {
+		return &fight.LeaderboardResponse{}, err
+	}
+	return resp, nil
+}
+
+// WatchLevelLeaderboard ...
+func (s *Service) WatchLevelLeaderboard(req *fight.LevelLeaderboardRequest, stream fight.FightSvc_WatchLevelLeaderboardServer) error {
+	b, page, err := s.levelBoard(req)
+	if err != nil {
+		return err
+	}
+	err = s.db.QueryRowContext(stream.Context(), "SELECT 1 FROM boss WHERE level = $1;", req.GetLevel()).Scan(new(int))
+	if err == sql.ErrNoRows {
+		return status.Errorf(codes.NotFound, "undefined level: '%d'", req.GetLevel())
+	}
+	if err != nil {
+		return err
+	}
+	return s.watchBoard(stream.Context(), levelBoardKey(int(req.GetLevel())), b, page, stream.Send)
+}
+
+func (s *Service) heroBoard(req *fight.HeroLeaderboardRequest) (board, leaderboardPage, error) {
+	page, err := newLeaderboardPage(req.GetLimit(), req.GetOffset(), req.GetNeighbours(), req.GetPlayerId())
+	if err != nil {
+		return nil, page, err
+	}
+	if req.GetHeroName() == "" {
+		return nil, page, status.Error(codes.InvalidArgument, "hero name is required")
+	}
+	return heroBoard{db: s.db, hero: req.GetHeroName()}, page, nil
+}
+
+func (s *Service) levelBoard(req *fight.LevelLeaderboardRequest) (board, leaderboardPage, error) {
+	page, err := newLeaderboardPage(req.GetLimit(), req.GetOffset(), req.GetNeighbours(), req.GetPlayerId())
+	if err != nil {
+		return nil, page, err
+	}
+	if req.GetLevel() <= 0 {
+		return nil, page, status.Errorf(codes.InvalidArgument, "undefined level: '%d'", req.GetLevel())
+	}
+	return levelBoard{db: s.db, level: int(req.GetLevel())}, page, nil
+}
+
+// watchBoard sends the page of the leaderboard, then sends it again on
+// every change until the stream ends.
+func (s *Service) watchBoard(ctx context.Context, key string, b board, page leaderboardPage, send func(*fight.LeaderboardResponse) error) error {
+	signal, unsubscribe := s.records.Subscribe(key)
+	defer unsubscribe()
+
+	for {
+		resp, err := s.listBoard(ctx, b, page)
+		if err != nil {
+			return err
+		}
+		if err = send(resp); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-signal:
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecordsPublish(t *testing.T) {
+	r := NewRecords(nil)
+
+	// no hub is created for the leaderboards nobody watches
+	r.publish(heroBoardKey("PostgreSql"))
+	if len(r.hubs) != 0 {
+		t.Fatalf("want no hub, but get %d", len(r.hubs))
+	}
+
+	hero, unsubscribe := r.Subscribe(heroBoardKey("PostgreSql"))
+	defer unsubscribe()
+	level, unsubscribeLevel := r.Subscribe(levelBoardKey(1))
+	defer unsubscribeLevel()
+
+	r.publish(heroBoardKey("PostgreSql"))
+	select {
+	case <-hero:
+	case <-time.After(time.Second):
+		t.Fatal("want the hero leaderboard signaled")
+	}
+	select {
+	case <-level:
+		t.Fatal("want the level leaderboard not signaled")
+	case <-time.After(2 * pushInterval):
+	}
+
+	// the bosses moved, every level leaderboard changed
+	r.publishLevels()
+	select {
+	case <-level:
+	case <-time.After(time.Second):
+		t.Fatal("want the level leaderboard signaled")
+	}
+}
+
+func TestRecordsUnsubscribe(t *testing.T) {
+	r := NewRecords(nil)
+	_, first := r.Subscribe(heroBoardKey("PostgreSql"))
+	_, second := r.Subscribe(heroBoardKey("PostgreSql"))
+
+	first()
+	first()
+	if len(r.hubs) != 1 {
+		t.Fatalf("want the hub kept for the second watcher, but get %d hubs", len(r.hubs))
+	}
+	second()
+	if len(r.hubs) != 0 {
+		t.Errorf("want the hub deleted with its last watcher, but get %d hubs", len(r.hubs))
+	}
+}
+
+type leaderboardStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s leaderboardStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s leaderboardStream) Send(*fight.LeaderboardResponse) error {
+	return nil
+}
+
+func TestWatchUndefinedBoard(t *testing.T) {
+	db, f := newFakeDB(t)
+	s := &Service{db: db, records: NewRecords(db)}
+	stream := leaderboardStream{ctx: context.Background()}
+
+	f.expect("SELECT 1 FROM hero WHERE name", "Dragon").returns([]string{"exists"})
+	if err := s.WatchHeroLeaderboard(&fight.HeroLeaderboardRequest{HeroName: "Dragon"}, stream); status.Code(err) != codes.NotFound {
+		t.Errorf("want NotFound for an undefined hero, but get '%v'", err)
+	}
+	f.expect("SELECT 1 FROM boss WHERE level", 9).returns([]string{"exists"})
+	if err := s.WatchLevelLeaderboard(&fight.LevelLeaderboardRequest{Level: 9}, stream); status.Code(err) != codes.NotFound {
+		t.Errorf("want NotFound for an undefined level, but get '%v'", err)
+	}
+	if len(s.records.hubs) != 0 {
+		t.Errorf("want no hub for the undefined leaderboards, but get %d", len(s.records.hubs))
+	}
+}
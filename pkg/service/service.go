@@ -3,15 +3,16 @@ package service
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
-	"time"
-
+	"hash/fnv"
 	"sync"
+	"time"
 
 	"github.com/lib/pq"
 	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/authz"
 	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/token"
 	"github.com/opentracing/opentracing-go"
 	tags "github.com/opentracing/opentracing-go/ext"
 	"github.com/opentracing/opentracing-go/log"
@@ -33,118 +34,234 @@ var ErrNoBossExistsForLevel = GameError{
 	Code: 404,
 }
 
-var once = sync.Once{}
-
 // Service implements FightSvcServer interface.
 type Service struct {
-	db       *sql.DB
-	listener *pq.Listener
-	tracer   opentracing.Tracer
+	db *sql.DB
+	// dispatches the changes of the catalog
+	dispatcher *Dispatcher
+	tracer     opentracing.Tracer
+	sessions   SessionStore
+	events     *EventLog
+	// the all-time best scores
+	highScores *HighScores
+	windows    *Windows
+	// the per-hero and the per-level records
+	records *Records
+	// the player profiles
+	profiles *Profiles
+	friends  *Friends
+	guilds   *Guilds
+	auditLog *AuditLog
+	leases   *LeaseManager
+	snapshot string
+	tokens   *token.Issuer
+
+	heroPolicy    HeroUpdatePolicy
+	heroLock      sync.Mutex
+	pendingHeroes map[string]module.Hero
+
+	// serialize the changes of each live session, see lockSession
+	sessionLocks [shardCount]sync.Mutex
 }
 
-// New creates a new service.
-func New(db *sql.DB, ls *pq.Listener, tracer opentracing.Tracer) *Service {
-	return &Service{
-		db:       db,
-		listener: ls,
-		tracer:   tracer,
-	}
-}
+// Option configures the optional parts of the service.
+type Option func(*Service)
 
-// Event ...
-type Event struct {
-	Table  string      `json:"table"`
-	Action string      `json:"action"`
-	Data   module.Hero `json:"data"`
+// WithLeases makes the service own its sessions through the lease manager,
+// which is required to run more than one replica.
+func WithLeases(lm *LeaseManager) Option {
+	return func(s *Service) {
+		s.leases = lm
+	}
 }
 
-func (s *Service) ClearSession(ctx context.Context, req *fight.ClearSessionRequest) (*fight.ClearSessionResponse, error) {
-	id := req.GetId()
-	sessionStore.Remove(id)
-	if err := s.removeSessionFromDB(id, ctx); err != nil {
-		return &fight.ClearSessionResponse{}, err
+// New creates a new service.
+func New(db *sql.DB, ls *pq.Listener, tracer opentracing.Tracer, store SessionStore, opts ...Option) *Service {
+	s := &Service{
+		db:            db,
+		dispatcher:    NewDispatcher(ls),
+		tracer:        tracer,
+		sessions:      store,
+		events:        NewEventLog(db),
+		highScores:    NewHighScores(db),
+		records:       NewRecords(db),
+		profiles:      NewProfiles(db),
+		friends:       NewFriends(db),
+		guilds:        NewGuilds(db),
+		auditLog:      NewAuditLog(db),
+		heroPolicy:    ApplyImmediately,
+		pendingHeroes: make(map[string]module.Hero),
 	}
-	return &fight.ClearSessionResponse{
-		Msg: "data cleared",
-	}, nil
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	go s.leases.Run(s.evictLost)
+	go s.windows.Run()
+	go s.dispatcher.Run()
+	go s.watchEvents()
+	return s
 }
 
-// Admin ...
-func (s *Service) Admin(stream fight.FightSvc_AdminServer) error {
-	var f = func() error {
-		once.Do(func() {
-			if err := s.listener.Listen("events"); err != nil {
-				klog.Warning(err)
-			}
-		})
-		for event := range s.listener.Notify {
-			var e Event
-			if err := json.Unmarshal([]byte(event.Extra), &e); err != nil {
-				return err
-			}
+// Close archives the live sessions if the leases are enabled, dumps them to
+// the snapshot file if it is configured, then hands them over to the other
+// replicas. The snapshot is taken after the archive, so that it matches the
+// event log unless another replica serves the session in the meantime.
+func (s *Service) Close() error {
+	s.windows.Stop()
+	s.dispatcher.Stop()
 
-			stream.Send(&fight.AdminResponse{
-				Heros: []*fight.Hero{
-					convertModuleHero2FightHero(e.Data),
-				},
-			})
+	if s.leases != nil {
+		s.leases.Stop()
+
+		sessionViews, err := s.sessions.List()
+		if err != nil {
+			return err
+		}
+		for _, sv := range sessionViews {
+			unlock := s.lockSession(sv.UID)
+			if err = s.archive(context.Background(), sv.UID, sv); err != nil {
+				klog.Warningf("failed to archive session '%s': %v", sv.UID, err)
+			}
+			unlock()
 		}
-		return nil
 	}
 
-	go func() {
-		if err := f(); err != nil {
+	if s.snapshot != "" {
+		if err := s.Snapshot(); err != nil {
 			klog.Warning(err)
 		}
-	}()
+	}
+	return s.leases.ReleaseAll()
+}
 
-	for {
-		req, err := stream.Recv()
-		if err != nil {
-			return err
+// getSession returns the cached session, if the session is owned by
+// another replica the returned error carries the owner.
+func (s *Service) getSession(ctx context.Context, id string) (*module.SessionView, error) {
+	sv, err := s.sessions.Get(id)
+	if err == ErrorNotFound {
+		if e := s.leases.Owner(ctx, id); e != nil {
+			return nil, e
 		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
 
-		switch req.GetType() {
-		case fight.AdminRequest_CREATE_HERO:
-			for _, hero := range req.GetHeros() {
-				if _, err = s.insertHero(hero); err != nil {
-					return err
-				}
-			}
+	if !s.leases.Holds(id) {
+		if err = s.sessions.Evict(id); err != nil {
+			return nil, err
+		}
+		if err = s.leases.Owner(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrLeaseLost
+	}
+	return sv, nil
+}
 
-		case fight.AdminRequest_ADJUST_HERO:
-			sqlStatement := `UPDATE hero SET attackpower = attackpower*1.2, defensepower = defensepower*1.2;`
-			if _, err = s.db.Exec(sqlStatement); err != nil {
-				return err
-			}
+// evictLost drops the session whose lease is lost from the memory, unless
+// its lease was acquired again in the meantime.
+func (s *Service) evictLost(id string) {
+	defer s.lockSession(id)()
+	if s.leases.Holds(id) {
+		return
+	}
+	if err := s.sessions.Evict(id); err != nil {
+		klog.Warning(err)
+	}
+}
 
-		default:
-			return fmt.Errorf("undefined admin request type: '%v'", req.GetType())
+// lockSession serializes the changes of the session, by the requests of
+// its player and by the changes of the catalog, until the returned function
+// is called. The sessions share the locks of their shard.
+func (s *Service) lockSession(id string) func() {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	lock := &s.sessionLocks[h.Sum32()%shardCount]
+	lock.Lock()
+	return lock.Unlock
+}
+
+// removeSession removes the session from the cache and gives up its lease.
+func (s *Service) removeSession(id string) error {
+	if err := s.sessions.Remove(id); err != nil {
+		return err
+	}
+	return s.leases.Release(id)
+}
+
+// ClearSession ...
+func (s *Service) ClearSession(ctx context.Context, req *fight.ClearSessionRequest) (resp *fight.ClearSessionResponse, err error) {
+	var (
+		id    = req.GetId()
+		entry = newAuditEntry(ctx, "ClearSession", marshalAudit(req))
+	)
+	defer func() { err = s.audit(entry, err) }()
+
+	// the moderators clear any session without its session token
+	if pr, ok := authz.FromContext(ctx); !ok || !pr.AnySession() {
+		if _, err = s.authorize(ctx, id); err != nil {
+			return &fight.ClearSessionResponse{}, err
 		}
 	}
+	defer s.lockSession(id)()
+	if err = s.leases.Owner(ctx, id); err != nil {
+		return &fight.ClearSessionResponse{}, err
+	}
+	if entry.Before, err = s.auditSession(ctx, id); err != nil {
+		return &fight.ClearSessionResponse{}, err
+	}
+	if err = s.record(ctx, id, &module.SessionView{}, SessionEvent{Kind: SessionEnded}); err != nil {
+		return &fight.ClearSessionResponse{}, err
+	}
+	if err = s.removeSession(id); err != nil {
+		return &fight.ClearSessionResponse{}, err
+	}
+	if err = s.removeSessionFromDB(s.db, id, ctx); err != nil {
+		return &fight.ClearSessionResponse{}, err
+	}
+	if _, err = s.revokeTokens(ctx, id); err != nil {
+		return &fight.ClearSessionResponse{}, err
+	}
+	return &fight.ClearSessionResponse{
+		Msg: "data cleared",
+	}, nil
 }
 
 // Top10 ...
 func (s *Service) Top10(req *fight.Top10Request, stream fight.FightSvc_Top10Server) error {
-	for range sessionStore.signal {
-		players, err := sessionStore.ListTop(10)
+	signal, unsubscribe := s.sessions.Subscribe()
+	defer unsubscribe()
+
+	for {
+		players, err := s.sessions.ListTop(10)
 		if err != nil {
 			return err
 		}
+		if err = s.profiles.resolve(stream.Context(), players); err != nil {
+			return err
+		}
 		resp := &fight.Top10Response{}
 		resp.Players = make([]*fight.Top10Response_Player, len(players))
 		for i := 0; i < len(players); i++ {
 			resp.Players[i] = &fight.Top10Response_Player{
-				Id:    players[i].id,
-				Score: int32(players[i].score),
-				Level: int32(players[i].level),
+				Score:       int32(players[i].Score),
+				Level:       int32(players[i].Level),
+				DisplayName: players[i].DisplayName,
 			}
 		}
 		if err = stream.Send(resp); err != nil {
 			return err
 		}
+
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-signal:
+		}
 	}
-	return nil
 }
 
 // Game ...
@@ -154,15 +271,26 @@ func (s *Service) Game(ctx context.Context, req *fight.GameRequest) (*fight.Game
 		eventType = req.GetType()
 	)
 
-	sv, err := sessionStore.Get(id)
+	if _, err := s.authorize(ctx, id); err != nil {
+		return &fight.GameResponse{}, err
+	}
+	defer s.lockSession(id)()
+
+	stored, err := s.getSession(ctx, id)
 	if err != nil {
 		return &fight.GameResponse{}, err
 	}
+	// the request changes a copy of the session, which is swapped in once
+	// it succeeded
+	updated := *stored
+	sv := &updated
 
 	switch eventType {
 	case fight.Type_ARCHIVE:
-		session := sv.Session
-		if err = s.archive(session, ctx); err != nil {
+		if err = s.archive(ctx, id, sv); err != nil {
+			return &fight.GameResponse{}, err
+		}
+		if err = s.sessions.Update(id, sv); err != nil {
 			return &fight.GameResponse{}, err
 		}
 		return &fight.GameResponse{
@@ -179,76 +307,80 @@ func (s *Service) Game(ctx context.Context, req *fight.GameRequest) (*fight.Game
 		if sv.LiveBossBlood <= 0 || sv.LiveHeroBlood <= 0 {
 			return &fight.GameResponse{}, fmt.Errorf("GameOver or NextLevel")
 		}
-		if sv.Hero.AttackPower >= sv.Boss.DefensePower {
-			sv.Session.LiveBossBlood -= (sv.Hero.AttackPower - sv.Boss.DefensePower)
-		}
-		if sv.Boss.AttackPower >= sv.Hero.DefensePower {
-			sv.Session.LiveHeroBlood -= (sv.Boss.AttackPower - sv.Hero.DefensePower)
-		}
-		// sv.Session.LiveHeroBlood -= sv.Boss.AttackPower
-		// sv.Session.LiveBossBlood -= sv.Hero.AttackPower
-		sv.Score += 10
-
-		var resp *fight.GameResponse
-
-		if sv.Session.LiveHeroBlood <= 0 {
-			sv.Session.LiveHeroBlood = 0
-			resp = &fight.GameResponse{
-				Type: eventType,
-				Value: &fight.GameResponse_Fight{
-					Fight: &fight.Fight{
-						GameOver:  true,
-						NextLevel: false,
-						Score:     int32(sv.Score),
-						HeroBlood: int32(sv.LiveHeroBlood),
-						BossBlood: int32(sv.LiveBossBlood),
-					},
-				},
+		// the writes of the turn commit together, or the turn did not happen
+		result := &fight.Fight{}
+		err = s.turn(ctx, func(t *turnTx) error {
+			score := sv.Score
+			if err := s.recordIn(ctx, t, id, sv, SessionEvent{Kind: FightTurn}); err != nil {
+				return err
 			}
-			sessionStore.Remove(id)
-			if err = s.removeSessionFromDB(id, ctx); err != nil {
-				return &fight.GameResponse{}, err
+			result.Score = int32(sv.Score)
+			result.HeroBlood = int32(sv.LiveHeroBlood)
+			result.BossBlood = int32(sv.LiveBossBlood)
+
+			raised, err := s.highScores.Raise(ctx, t, id, sv.Score, sv.CurrentLevel)
+			if err != nil {
+				return err
 			}
-		} else if sv.Session.LiveBossBlood <= 0 {
-			sv.Session.LiveBossBlood = 0
-			resp = &fight.GameResponse{
-				Type: eventType,
-				Value: &fight.GameResponse_Fight{
-					Fight: &fight.Fight{
-						GameOver:  false,
-						NextLevel: true,
-						Score:     int32(sv.Score),
-						HeroBlood: int32(sv.LiveHeroBlood),
-						BossBlood: int32(sv.LiveBossBlood),
-					},
-				},
+			if raised {
+				if err = s.guilds.Credit(ctx, t, id); err != nil {
+					return err
+				}
 			}
-		} else {
-			resp = &fight.GameResponse{
-				Type: eventType,
-				Value: &fight.GameResponse_Fight{
-					Fight: &fight.Fight{
-						GameOver:  false,
-						NextLevel: false,
-						Score:     int32(sv.Score),
-						HeroBlood: int32(sv.LiveHeroBlood),
-						BossBlood: int32(sv.LiveBossBlood),
-					},
-				},
+			if err = s.windows.Credit(ctx, t, id, sv.Score-score, sv.CurrentLevel); err != nil {
+				return err
 			}
+			if _, err = s.records.RaiseHero(ctx, t, id, sv.HeroName, sv.Score, sv.CurrentLevel); err != nil {
+				return err
+			}
+
+			if sv.Session.LiveHeroBlood <= 0 {
+				result.GameOver = true
+				if err = s.recordIn(ctx, t, id, sv, SessionEvent{Kind: SessionEnded}); err != nil {
+					return err
+				}
+				return s.removeSessionFromDB(t, id, ctx)
+			}
+			if sv.Session.LiveBossBlood <= 0 {
+				result.NextLevel = true
+				// unknown for the sessions started before the level start was recorded
+				if !sv.LevelStartedAt.IsZero() {
+					_, err = s.records.RecordClear(ctx, t, id, sv.HeroName, sv.CurrentLevel, time.Since(sv.LevelStartedAt))
+				}
+			}
+			return err
+		})
+		if err != nil {
+			return &fight.GameResponse{}, err
 		}
-		sessionStore.Update(id, sv)
-		return resp, nil
+
+		// the session is removed once the game is over
+		if result.GameOver {
+			err = s.removeSession(id)
+		} else {
+			err = s.sessions.Update(id, sv)
+		}
+		if err != nil {
+			return &fight.GameResponse{}, err
+		}
+		return &fight.GameResponse{
+			Type:  eventType,
+			Value: &fight.GameResponse_Fight{Fight: result},
+		}, nil
 
 	case fight.Type_LEVEL:
 		boss, err := s.loadBossFromDB(sv.CurrentLevel+1, ctx)
 		if err != nil {
 			return &fight.GameResponse{}, err
 		}
-		sv.Boss = boss
-		sv.LiveBossBlood = boss.Blood
-		sv.CurrentLevel++
-		if err = sessionStore.Update(id, sv); err != nil {
+		event := SessionEvent{Kind: LevelUp, Boss: &boss}
+		if hero, ok := s.pendingHero(sv.HeroName); ok {
+			event.Hero = &hero
+		}
+		if err = s.record(ctx, id, sv, event); err != nil {
+			return &fight.GameResponse{}, err
+		}
+		if err = s.sessions.Update(id, sv); err != nil {
 			return &fight.GameResponse{}, err
 		}
 		return &fight.GameResponse{
@@ -262,11 +394,12 @@ func (s *Service) Game(ctx context.Context, req *fight.GameRequest) (*fight.Game
 		}, nil
 
 	case fight.Type_QUIT:
-		session := sv.Session
-		if err = s.archive(session, ctx); err != nil {
+		if err = s.archive(ctx, id, sv); err != nil {
+			return &fight.GameResponse{}, err
+		}
+		if err = s.removeSession(id); err != nil {
 			return &fight.GameResponse{}, err
 		}
-		sessionStore.Remove(id)
 		return &fight.GameResponse{
 			Type: eventType,
 			Value: &fight.GameResponse_Quit{
@@ -288,17 +421,27 @@ func (s *Service) SelectHero(ctx context.Context, req *fight.SelectHeroRequest)
 		heroName = req.GetHeroName()
 	)
 
+	if _, err := s.authorize(ctx, id); err != nil {
+		return &fight.SessionView{}, err
+	}
+	defer s.lockSession(id)()
+
 	hero, err := s.loadHeroFromDB(heroName, ctx)
 	if err != nil {
 		return &fight.SessionView{}, err
 	}
 
-	if err = sessionStore.UpdateHero(id, hero); err != nil {
+	stored, err := s.getSession(ctx, id)
+	if err != nil {
 		return &fight.SessionView{}, err
 	}
 
-	sv, err := sessionStore.Get(id)
-	if err != nil {
+	updated := *stored
+	sv := &updated
+	if err = s.record(ctx, id, sv, SessionEvent{Kind: HeroSelected, Hero: &hero}); err != nil {
+		return &fight.SessionView{}, err
+	}
+	if err = s.sessions.Update(id, sv); err != nil {
 		return &fight.SessionView{}, err
 	}
 
@@ -309,10 +452,11 @@ func (s *Service) SelectHero(ctx context.Context, req *fight.SelectHeroRequest)
 func (s *Service) LoadSession(ctx context.Context, req *fight.LoadSessionRequest) (*fight.SessionView, error) {
 	var id = req.GetId()
 	fmt.Printf("get request: 'LoadSession', id: '%s'\n", id)
+	defer s.lockSession(id)()
 
-	sessionView, err := sessionStore.Get(id)
+	sessionView, err := s.getSession(ctx, id)
 	switch {
-	case err == ErrorNotFound:
+	case err == ErrorNotFound || err == ErrLeaseLost:
 		fmt.Printf("session view is not found in the cache: id: '%s'\n", id)
 		break
 
@@ -320,43 +464,68 @@ func (s *Service) LoadSession(ctx context.Context, req *fight.LoadSessionRequest
 		return &fight.SessionView{}, err
 
 	default:
-		return convertSV2FightSV(*sessionView), nil
+		if err = s.authorizeResume(ctx, id); err != nil {
+			return &fight.SessionView{}, err
+		}
+		return s.withToken(ctx, *sessionView, true)
 	}
 
-	var ssView = module.SessionView{}
-
-	ssView, err = loadSessionViewFromdb(s.db, ctx, s.tracer, id)
+	if err = s.leases.Acquire(ctx, id); err != nil {
+		return &fight.SessionView{}, err
+	}
 
+	ssView, ok, err := s.loadSession(ctx, id)
 	if err != nil {
 		return &fight.SessionView{}, err
 	}
 
-	if (ssView != module.SessionView{}) {
-		ssView.Hero.Name = ssView.Session.HeroName
-		ssView.Boss.Level = ssView.Session.CurrentLevel
+	if ok {
+		if err = s.authorizeResume(ctx, id); err != nil {
+			if e := s.leases.Release(id); e != nil {
+				klog.Warning(e)
+			}
+			return &fight.SessionView{}, err
+		}
 	} else {
 		fmt.Printf("session view is not found in the db: id: '%s'\n", id)
 		bossLevel1, err := s.loadBossFromDB(1, ctx)
 		if err != nil {
 			return &fight.SessionView{}, err
 		}
-		ssView = module.SessionView{
-			Hero: module.Hero{},
-			Boss: bossLevel1,
-			Session: module.Session{
-				UID:           id,
-				LiveBossBlood: bossLevel1.Blood,
-				CurrentLevel:  bossLevel1.Level,
-				ArchiveDate:   time.Now(),
-			},
+		if err = s.record(ctx, id, &ssView, SessionEvent{Kind: SessionStarted, Boss: &bossLevel1}); err != nil {
+			return &fight.SessionView{}, err
 		}
 	}
 
-	if err = sessionStore.Add(id, &ssView); err != nil {
+	if err = s.sessions.Add(id, &ssView); err != nil {
 		return &fight.SessionView{}, err
 	}
 
-	return convertSV2FightSV(ssView), nil
+	return s.withToken(ctx, ssView, ok)
+}
+
+// loadSession rebuilds the session from its event log, the sessions
+// archived before the event log existed are imported from the session table.
+func (s *Service) loadSession(ctx context.Context, id string) (module.SessionView, bool, error) {
+	ssView, ok, err := s.events.Load(ctx, id)
+	if err != nil || ok {
+		return ssView, ok, err
+	}
+
+	ssView, err = loadSessionViewFromdb(s.db, ctx, s.tracer, id)
+	if err != nil {
+		return ssView, false, err
+	}
+	if (ssView == module.SessionView{}) {
+		return ssView, false, nil
+	}
+
+	ssView.Hero.Name = ssView.Session.HeroName
+	ssView.Boss.Level = ssView.Session.CurrentLevel
+	if err = s.record(ctx, id, &ssView, SessionEvent{Kind: SessionImported, State: newSessionState(ssView)}); err != nil {
+		return ssView, false, err
+	}
+	return ssView, true, nil
 }
 
 // ListHeros ...
@@ -381,20 +550,7 @@ func (s *Service) ListHeros(req *fight.ListHerosRequest, stream fight.FightSvc_L
 	return nil
 }
 
-func (s *Service) insertHero(hero *fight.Hero) (string, error) {
-	sqlStatement := `INSERT INTO hero VALUES ($1, $2, $3, $4, $5) RETURNING name;`
-	var name string
-	err := s.db.QueryRow(sqlStatement,
-		hero.Name,
-		hero.Details,
-		hero.AttackPower,
-		hero.DefensePower,
-		hero.Blood,
-	).Scan(&name)
-	return name, err
-}
-
-func (s *Service) removeSessionFromDB(id string, ctx context.Context) error {
+func (s *Service) removeSessionFromDB(q dbtx, id string, ctx context.Context) error {
 	if span := opentracing.SpanFromContext(ctx); span != nil {
 		childSpan := s.tracer.StartSpan("SQL DELETE FROM session", opentracing.ChildOf(span.Context()))
 		tags.SpanKindRPCServer.Set(childSpan)
@@ -404,11 +560,17 @@ func (s *Service) removeSessionFromDB(id string, ctx context.Context) error {
 	}
 
 	sqlStatement := "DELETE FROM session where uid = $1;"
-	_, err := s.db.Exec(sqlStatement, id)
+	_, err := q.ExecContext(ctx, sqlStatement, id)
 	return err
 }
 
-func (s *Service) archive(session module.Session, ctx context.Context) error {
+// archive records the session archived, and saves its state to the session
+// table which is the latest projection of the event log.
+func (s *Service) archive(ctx context.Context, id string, sv *module.SessionView) error {
+	if err := s.record(ctx, id, sv, SessionEvent{Kind: SessionArchived}); err != nil {
+		return err
+	}
+
 	if span := opentracing.SpanFromContext(ctx); span != nil {
 		childSpan := s.tracer.StartSpan("SQL INSERT TO session", opentracing.ChildOf(span.Context()))
 		tags.SpanKindRPCServer.Set(childSpan)
@@ -417,22 +579,7 @@ func (s *Service) archive(session module.Session, ctx context.Context) error {
 		defer childSpan.Finish()
 	}
 
-	sqlStatement := `INSERT INTO session(uid, heroname, heroblood, bossblood, currentlevel, score, archivedate) VALUES($1, $2, $3, $4, $5, $6, $7) ON conflict (uid) DO UPDATE SET heroblood = $8, bossblood = $9, currentlevel = $10, score = $11, archivedate = $12;`
-	_, err := s.db.Exec(sqlStatement,
-		session.UID,
-		session.HeroName,
-		session.LiveHeroBlood,
-		session.LiveBossBlood,
-		session.CurrentLevel,
-		session.Score,
-		time.Now(),
-		session.LiveHeroBlood,
-		session.LiveBossBlood,
-		session.CurrentLevel,
-		session.Score,
-		time.Now(),
-	)
-	return err
+	return saveSession(s.db, sv.Session)
 }
 
 func (s *Service) loadHeroFromDB(heroName string, ctx context.Context) (module.Hero, error) {
@@ -486,15 +633,60 @@ func convertSV2FightSV(sessionView module.SessionView) *fight.SessionView {
 	}
 }
 
+func convertFightSV2SV(sessionView *fight.SessionView) module.SessionView {
+	return module.SessionView{
+		Hero:    convertFightHero2ModuleHero(sessionView.GetHero()),
+		Boss:    convertFightBoss2ModuleBoss(sessionView.GetBoss()),
+		Session: convertFightSession2ModuleSession(sessionView.GetSession()),
+	}
+}
+
+func convertFightSession2ModuleSession(session *fight.Session) module.Session {
+	return module.Session{
+		UID:            session.GetUID(),
+		HeroName:       session.GetHeroName(),
+		LiveHeroBlood:  int(session.GetLiveHeroBlood()),
+		LiveBossBlood:  int(session.GetLiveBossBlood()),
+		CurrentLevel:   int(session.GetCurrentLevel()),
+		Score:          int(session.GetScore()),
+		ArchiveDate:    session.GetArchiveDate().AsTime(),
+		LevelStartedAt: session.GetLevelStartedAt().AsTime(),
+		ScoreUpdatedAt: session.GetScoreUpdatedAt().AsTime(),
+	}
+}
+
+func convertFightBoss2ModuleBoss(boss *fight.Boss) module.Boss {
+	return module.Boss{
+		Name:         boss.GetName(),
+		Detail:       boss.GetDetails(),
+		AttackPower:  int(boss.GetAttackPower()),
+		DefensePower: int(boss.GetDefensePower()),
+		Blood:        int(boss.GetBlood()),
+		Level:        int(boss.GetLevel()),
+	}
+}
+
+func convertFightHero2ModuleHero(hero *fight.Hero) module.Hero {
+	return module.Hero{
+		Name:         hero.GetName(),
+		Detail:       hero.GetDetails(),
+		AttackPower:  int(hero.GetAttackPower()),
+		DefensePower: int(hero.GetDefensePower()),
+		Blood:        int(hero.GetBlood()),
+	}
+}
+
 func convertModuleSession2FightSession(session module.Session) *fight.Session {
 	return &fight.Session{
-		UID:           session.UID,
-		HeroName:      session.HeroName,
-		LiveHeroBlood: int32(session.LiveHeroBlood),
-		LiveBossBlood: int32(session.LiveBossBlood),
-		CurrentLevel:  int32(session.CurrentLevel),
-		Score:         int32(session.Score),
-		ArchiveDate:   timestamppb.New(session.ArchiveDate),
+		UID:            session.UID,
+		HeroName:       session.HeroName,
+		LiveHeroBlood:  int32(session.LiveHeroBlood),
+		LiveBossBlood:  int32(session.LiveBossBlood),
+		CurrentLevel:   int32(session.CurrentLevel),
+		Score:          int32(session.Score),
+		ArchiveDate:    timestamppb.New(session.ArchiveDate),
+		LevelStartedAt: timestamppb.New(session.LevelStartedAt),
+		ScoreUpdatedAt: timestamppb.New(session.ScoreUpdatedAt),
 	}
 }
 
@@ -1,10 +1,12 @@
 package service
 
 import (
+	"database/sql"
 	"errors"
-	"sort"
-
+	"fmt"
+	"hash/fnv"
 	"sync"
+	"time"
 
 	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
 )
@@ -12,110 +14,167 @@ import (
 // ErrorNotFound ...
 var ErrorNotFound = errors.New("session not found")
 
-type sessions struct {
-	signal chan struct{}
-	lock   sync.Mutex
-	maps   map[string]*module.SessionView
-}
+const (
+	// MemoryStore keeps the sessions in the memory of the process only.
+	MemoryStore = "memory"
+	// PostgresStore keeps the sessions in memory and writes every change through to postgres.
+	PostgresStore = "postgres"
+)
 
-var sessionStore = &sessions{
-	lock:   sync.Mutex{},
-	signal: make(chan struct{}, 1024),
-	maps:   make(map[string]*module.SessionView),
+// SessionStore caches the online sessions.
+type SessionStore interface {
+	Get(id string) (*module.SessionView, error)
+	Add(id string, sessionView *module.SessionView) error
+	Update(id string, sessionView *module.SessionView) error
+	// Remove drops the session once it is archived or ended, the service
+	// deletes the row of an ended session itself.
+	Remove(id string) error
+	// Evict drops the session from the cache, but keeps it in the backend.
+	Evict(id string) error
+	List() ([]*module.SessionView, error)
+	ListTop(num int) ([]Player, error)
+	// ListRange returns at most limit players ranked after offset, and the
+	// number of ranked players.
+	ListRange(offset, limit int) ([]Player, int, error)
+	// Rank returns the ranked player of the session.
+	Rank(id string) (Player, error)
+	// Subscribe returns a channel which receives a signal after the session
+	// changes, and the function to unsubscribe.
+	Subscribe() (<-chan struct{}, func())
 }
 
-func (ss *sessions) Remove(id string) {
-	ss.lock.Lock()
-	defer ss.lock.Unlock()
-	// ss.cache.Delete(id)
-	delete(ss.maps, id)
-	if len(ss.signal) < 1024 {
-		ss.signal <- struct{}{}
+// NewSessionStore creates the session store of the given kind.
+func NewSessionStore(kind string, db *sql.DB) (SessionStore, error) {
+	switch kind {
+	case MemoryStore:
+		return NewMemoryStore(), nil
+	case PostgresStore:
+		return NewPostgresStore(db), nil
+	default:
+		return nil, fmt.Errorf("undefined session store: '%s'", kind)
 	}
 }
 
-type player struct {
-	id    string
-	score int
-	level int
+// Player is an entry of the ranking.
+type Player struct {
+	ID    string
+	Score int
+	Level int
+	// the time the score was reached
+	ScoreUpdatedAt time.Time
+	// the display name of the profile, or an anonymized label, set by the
+	// leaderboards only
+	DisplayName string
+	// the opaque id of the player in the leaderboards, set with the display
+	// name
+	Key string
+	// set by the per-hero and the per-level leaderboards only
+	HeroName string
+	// the time of the fastest clear of the level, set by the per-level
+	// leaderboard only
+	ClearTime time.Duration
+	// the role in the guild, set by the guild members only
+	Role string
+	// 1-based, set by the ranking queries only
+	Rank int
+}
+
+// pushInterval is the minimal interval between two signals to the subscribers.
+const pushInterval = 100 * time.Millisecond
+
+// shardCount is the number of shards of the memory store, each shard is
+// guarded by its own lock.
+const shardCount = 64
+
+type shard struct {
+	lock sync.RWMutex
+	maps map[string]*module.SessionView
 }
 
-type collections []player
+type sessions struct {
+	hub     *Hub
+	shards  [shardCount]*shard
+	ranking *ranking
+}
 
-func (c collections) Len() int {
-	return len(c)
+// NewMemoryStore creates a session store living in memory.
+func NewMemoryStore() SessionStore {
+	ss := &sessions{
+		hub:     NewHub(pushInterval),
+		ranking: newRanking(),
+	}
+	for i := range ss.shards {
+		ss.shards[i] = &shard{
+			maps: make(map[string]*module.SessionView),
+		}
+	}
+	return ss
 }
 
-func (c collections) Swap(i, j int) {
-	c[i], c[j] = c[j], c[i]
+func (ss *sessions) shard(id string) *shard {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return ss.shards[h.Sum32()%shardCount]
 }
 
-func (c collections) Less(i, j int) bool {
-	return c[i].score >= c[j].score
+func (ss *sessions) Subscribe() (<-chan struct{}, func()) {
+	return ss.hub.Subscribe()
 }
 
-func (ss *sessions) ListTop(num int) ([]player, error) {
-	ss.lock.Lock()
-	defer ss.lock.Unlock()
+func (ss *sessions) Remove(id string) error {
+	sh := ss.shard(id)
+	sh.lock.Lock()
+	delete(sh.maps, id)
+	sh.lock.Unlock()
+
+	ss.ranking.remove(id)
+	ss.hub.Publish()
+	return nil
+}
 
-	var (
-		players = make([]player, len(ss.maps))
-		i       = 0
-	)
+func (ss *sessions) Evict(id string) error {
+	return ss.Remove(id)
+}
 
-	for id := range ss.maps {
-		sessionView := ss.maps[id]
-		players[i] = player{
-			id:    id,
-			score: sessionView.Score,
-			level: sessionView.CurrentLevel,
+func (ss *sessions) List() ([]*module.SessionView, error) {
+	var sessionViews []*module.SessionView
+	for _, sh := range ss.shards {
+		sh.lock.RLock()
+		for _, sessionView := range sh.maps {
+			sessionViews = append(sessionViews, sessionView)
 		}
-		i++
-	}
-
-	sort.Sort(collections(players))
-	if len(players) >= num {
-		return players[:num], nil
+		sh.lock.RUnlock()
 	}
+	return sessionViews, nil
+}
 
+func (ss *sessions) ListTop(num int) ([]Player, error) {
+	players, _ := ss.ranking.page(0, num)
 	return players, nil
 }
 
-func (ss *sessions) GetCurrentLevel(id string) (int, error) {
-	ss.lock.Lock()
-	defer ss.lock.Unlock()
-	sessionView, ok := ss.maps[id]
-	if !ok {
-		return 0, ErrorNotFound
-	}
-	return sessionView.Session.CurrentLevel, nil
+func (ss *sessions) ListRange(offset, limit int) ([]Player, int, error) {
+	players, total := ss.ranking.page(offset, limit)
+	return players, total, nil
 }
 
-func (ss *sessions) GetSession(id string) (*module.Session, error) {
-	ss.lock.Lock()
-	defer ss.lock.Unlock()
-	sessionView, ok := ss.maps[id]
+func (ss *sessions) Rank(id string) (Player, error) {
+	p, ok := ss.ranking.rank(id)
 	if !ok {
-		return nil, ErrorNotFound
+		return Player{}, ErrorNotFound
 	}
-	return &sessionView.Session, nil
+	return p, nil
 }
 
 func (ss *sessions) Add(id string, sessionView *module.SessionView) error {
-	ss.lock.Lock()
-	defer ss.lock.Unlock()
-	ss.maps[id] = sessionView
-	if len(ss.signal) < 1024 {
-		ss.signal <- struct{}{}
-	}
-
-	return nil
+	return ss.Update(id, sessionView)
 }
 
 func (ss *sessions) Get(id string) (*module.SessionView, error) {
-	ss.lock.Lock()
-	defer ss.lock.Unlock()
-	value, ok := ss.maps[id]
+	sh := ss.shard(id)
+	sh.lock.RLock()
+	defer sh.lock.RUnlock()
+	value, ok := sh.maps[id]
 	if !ok {
 		return nil, ErrorNotFound
 	}
@@ -123,34 +182,17 @@ func (ss *sessions) Get(id string) (*module.SessionView, error) {
 }
 
 func (ss *sessions) Update(id string, s *module.SessionView) error {
-	ss.lock.Lock()
-	defer ss.lock.Unlock()
-	ss.maps[id] = s
-
-	if len(ss.signal) < 1024 {
-		ss.signal <- struct{}{}
-	}
-
-	return nil
-}
-
-func (ss *sessions) UpdateHero(id string, hero module.Hero) error {
-	sessionView, ok := ss.maps[id]
-	if !ok {
-		return ErrorNotFound
-	}
-
-	sessionView.Hero = hero
-	sessionView.Session.LiveHeroBlood = hero.Blood
-	sessionView.Session.HeroName = hero.Name
-
-	if err := ss.Update(id, sessionView); err != nil {
-		return err
-	}
-
-	if len(ss.signal) < 1024 {
-		ss.signal <- struct{}{}
-	}
-
+	sh := ss.shard(id)
+	sh.lock.Lock()
+	sh.maps[id] = s
+	sh.lock.Unlock()
+
+	ss.ranking.set(Player{
+		ID:             id,
+		Score:          s.Score,
+		Level:          s.CurrentLevel,
+		ScoreUpdatedAt: s.ScoreUpdatedAt,
+	})
+	ss.hub.Publish()
 	return nil
 }
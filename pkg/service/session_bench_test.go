@@ -0,0 +1,125 @@
+package service
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+)
+
+const benchSessions = 100000
+
+func newBenchStore(b *testing.B) SessionStore {
+	store := NewMemoryStore()
+	for i := 0; i < benchSessions; i++ {
+		id := strconv.Itoa(i)
+		if err := store.Add(id, &module.SessionView{Session: module.Session{UID: id, Score: i % 1000, CurrentLevel: 1}}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return store
+}
+
+// benchmarkGame plays fight turns on random live sessions in parallel,
+// while the subscribers keep listing the top 10.
+func benchmarkGame(b *testing.B, subscribers int) {
+	store := newBenchStore(b)
+
+	var (
+		stop = make(chan struct{})
+		wg   sync.WaitGroup
+		tops int64
+	)
+	for i := 0; i < subscribers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				if _, err := store.ListTop(10); err != nil {
+					b.Error(err)
+					return
+				}
+				atomic.AddInt64(&tops, 1)
+			}
+		}()
+	}
+
+	var next uint64
+	start := time.Now()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			id := strconv.Itoa(int(atomic.AddUint64(&next, 7919) % benchSessions))
+			sv, err := store.Get(id)
+			if err != nil {
+				b.Error(err)
+				return
+			}
+			// the stored session is shared with the other goroutines
+			updated := *sv
+			updated.Score += 10
+			if err = store.Update(id, &updated); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+	b.StopTimer()
+	elapsed := time.Since(start)
+
+	close(stop)
+	wg.Wait()
+	b.ReportMetric(float64(atomic.LoadInt64(&tops))/elapsed.Seconds(), "top10/s")
+}
+
+func BenchmarkGame(b *testing.B) {
+	benchmarkGame(b, 0)
+}
+
+func BenchmarkGameWithTop10Subscribers(b *testing.B) {
+	benchmarkGame(b, 8)
+}
+
+func BenchmarkListTop(b *testing.B) {
+	store := newBenchStore(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// every listing follows a change of the scores
+		sv, err := store.Get("42")
+		if err != nil {
+			b.Fatal(err)
+		}
+		updated := *sv
+		updated.Score++
+		if err = store.Update("42", &updated); err != nil {
+			b.Fatal(err)
+		}
+		if _, err = store.ListTop(10); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkListRange(b *testing.B) {
+	store := newBenchStore(b)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		player, err := store.Rank(strconv.Itoa(i % benchSessions))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, _, err = store.ListRange(player.Rank-1, 10); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
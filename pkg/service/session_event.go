@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SessionEventKind is the kind of a state transition of a session.
+type SessionEventKind string
+
+const (
+	// SessionStarted starts a new session fighting the boss.
+	SessionStarted SessionEventKind = "started"
+	// SessionImported imports the state of a session from the session table or a snapshot.
+	SessionImported SessionEventKind = "imported"
+	// HeroSelected selects the hero of the session.
+	HeroSelected SessionEventKind = "hero_selected"
+	// HeroChanged applies a change of the hero catalog to the session, or
+	// moves the session to another hero when its hero is deleted.
+	HeroChanged SessionEventKind = "hero_changed"
+	// BossChanged applies a change of the boss of the session, which may
+	// have moved to another level.
+	BossChanged SessionEventKind = "boss_changed"
+	// FightTurn is one turn of the fight between the hero and the boss.
+	FightTurn SessionEventKind = "fight_turn"
+	// LevelUp moves the session to the boss of the next level.
+	LevelUp SessionEventKind = "level_up"
+	// SessionArchived archives the session.
+	SessionArchived SessionEventKind = "archived"
+	// SessionEnded ends the session, on game over or when it is cleared.
+	SessionEnded SessionEventKind = "ended"
+)
+
+// snapshotEvery is the number of events after which the state of a
+// session is snapshotted, so that loading it folds a few events only.
+const snapshotEvery = 50
+
+// SessionEvent is a state transition of a session, the state of a session
+// is rebuilt by folding its events with applySessionEvent.
+type SessionEvent struct {
+	Seq  int64            `json:"-"`
+	UID  string           `json:"-"`
+	Kind SessionEventKind `json:"-"`
+	At   time.Time        `json:"-"`
+
+	Hero  *module.Hero  `json:"hero,omitempty"`
+	Boss  *module.Boss  `json:"boss,omitempty"`
+	State *sessionState `json:"state,omitempty"`
+}
+
+// sessionState is the json form of module.SessionView, whose embedded
+// structs have conflicting field names.
+type sessionState struct {
+	Session module.Session `json:"session"`
+	Hero    module.Hero    `json:"hero"`
+	Boss    module.Boss    `json:"boss"`
+}
+
+func newSessionState(sv module.SessionView) *sessionState {
+	return &sessionState{
+		Session: sv.Session,
+		Hero:    sv.Hero,
+		Boss:    sv.Boss,
+	}
+}
+
+func (state *sessionState) sessionView() module.SessionView {
+	return module.SessionView{
+		Session: state.Session,
+		Hero:    state.Hero,
+		Boss:    state.Boss,
+	}
+}
+
+// applySessionEvent applies the event to the state of the session, all the
+// game rules changing the state live here so that replaying the events
+// re-derives the state, including the score.
+func applySessionEvent(sv *module.SessionView, e SessionEvent) {
+	switch e.Kind {
+	case SessionStarted:
+		*sv = module.SessionView{
+			Boss: *e.Boss,
+			Session: module.Session{
+				UID:            e.UID,
+				LiveBossBlood:  e.Boss.Blood,
+				CurrentLevel:   e.Boss.Level,
+				ArchiveDate:    e.At,
+				LevelStartedAt: e.At,
+				ScoreUpdatedAt: e.At,
+			},
+		}
+
+	case SessionImported:
+		*sv = e.State.sessionView()
+		// unknown for the sessions imported from the session table
+		if sv.ScoreUpdatedAt.IsZero() {
+			sv.ScoreUpdatedAt = e.At
+		}
+
+	case HeroSelected:
+		sv.Hero = *e.Hero
+		sv.Session.LiveHeroBlood = e.Hero.Blood
+		sv.Session.HeroName = e.Hero.Name
+
+	case HeroChanged:
+		setHero(sv, *e.Hero)
+		sv.Session.HeroName = e.Hero.Name
+
+	case BossChanged:
+		setBoss(sv, *e.Boss)
+
+	case FightTurn:
+		if sv.Hero.AttackPower >= sv.Boss.DefensePower {
+			sv.Session.LiveBossBlood -= (sv.Hero.AttackPower - sv.Boss.DefensePower)
+		}
+		if sv.Boss.AttackPower >= sv.Hero.DefensePower {
+			sv.Session.LiveHeroBlood -= (sv.Boss.AttackPower - sv.Hero.DefensePower)
+		}
+		sv.Score += 10
+		sv.ScoreUpdatedAt = e.At
+
+		if sv.Session.LiveHeroBlood <= 0 {
+			sv.Session.LiveHeroBlood = 0
+		} else if sv.Session.LiveBossBlood <= 0 {
+			sv.Session.LiveBossBlood = 0
+		}
+
+	case LevelUp:
+		sv.Boss = *e.Boss
+		sv.LiveBossBlood = e.Boss.Blood
+		sv.CurrentLevel = e.Boss.Level
+		sv.LevelStartedAt = e.At
+		// the hero change waiting for the next level
+		if e.Hero != nil {
+			setHero(sv, *e.Hero)
+		}
+
+	case SessionArchived:
+		sv.ArchiveDate = e.At
+
+	case SessionEnded:
+		*sv = module.SessionView{}
+	}
+}
+
+// EventLog keeps the events of the sessions in the append-only
+// session_event table, and snapshots of their state in the
+// session_snapshot table. A nil *EventLog does not persist anything.
+type EventLog struct {
+	db *sql.DB
+
+	lock sync.Mutex
+	// number of events of each session since its last snapshot
+	pending map[string]int
+}
+
+// NewEventLog ...
+func NewEventLog(db *sql.DB) *EventLog {
+	return &EventLog{
+		db:      db,
+		pending: make(map[string]int),
+	}
+}
+
+// Append appends the event to the log with q, and sets its sequence number.
+func (el *EventLog) Append(ctx context.Context, q dbtx, e *SessionEvent) error {
+	if el == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	sqlStatement := `INSERT INTO session_event(uid, kind, payload, createdat) VALUES($1, $2, $3, $4) RETURNING seq;`
+	if err = q.QueryRowContext(ctx, sqlStatement, e.UID, e.Kind, payload, e.At).Scan(&e.Seq); err != nil {
+		return err
+	}
+
+	el.lock.Lock()
+	defer el.lock.Unlock()
+	el.pending[e.UID]++
+	return nil
+}
+
+// Snapshot saves the state of the session reached by the event seq with q,
+// if enough events were appended since the last snapshot or force is set.
+func (el *EventLog) Snapshot(ctx context.Context, q dbtx, uid string, seq int64, sv module.SessionView, force bool) error {
+	if el == nil {
+		return nil
+	}
+
+	el.lock.Lock()
+	if !force && el.pending[uid] < snapshotEvery {
+		el.lock.Unlock()
+		return nil
+	}
+	delete(el.pending, uid)
+	el.lock.Unlock()
+
+	state, err := json.Marshal(newSessionState(sv))
+	if err != nil {
+		return err
+	}
+
+	sqlStatement := `INSERT INTO session_snapshot(uid, seq, state, createdat) VALUES($1, $2, $3, now()) ON conflict (uid) DO UPDATE SET seq = $2, state = $3, createdat = now() WHERE session_snapshot.seq < $2;`
+	_, err = q.ExecContext(ctx, sqlStatement, uid, seq, state)
+	return err
+}
+
+// Load rebuilds the state of the session from its latest snapshot and the
+// events appended after it. It returns false if the session has no events,
+// or if it has ended.
+func (el *EventLog) Load(ctx context.Context, uid string) (module.SessionView, bool, error) {
+	if el == nil {
+		return module.SessionView{}, false, nil
+	}
+
+	var (
+		sv    module.SessionView
+		seq   int64
+		state []byte
+	)
+	err := el.db.QueryRowContext(ctx, "SELECT seq, state FROM session_snapshot WHERE uid = $1;", uid).Scan(&seq, &state)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return sv, false, err
+	default:
+		var st sessionState
+		if err = json.Unmarshal(state, &st); err != nil {
+			return sv, false, err
+		}
+		sv = st.sessionView()
+		sv.Seq = seq
+	}
+
+	if sv, err = el.fold(ctx, uid, sv, seq, -1); err != nil {
+		return sv, false, err
+	}
+	return sv, sv.UID != "", nil
+}
+
+// Head returns the sequence number of the last event of the session, or 0
+// if it has none.
+func (el *EventLog) Head(ctx context.Context, uid string) (int64, error) {
+	if el == nil {
+		return 0, nil
+	}
+
+	var seq int64
+	err := el.db.QueryRowContext(ctx, "SELECT coalesce(max(seq), 0) FROM session_event WHERE uid = $1;", uid).Scan(&seq)
+	return seq, err
+}
+
+// Replay rebuilds the state of the session reached by the event until,
+// ignoring the snapshots: it is the state as of that event according to
+// the current game rules. until < 0 replays all the events.
+func (el *EventLog) Replay(ctx context.Context, uid string, until int64) (module.SessionView, error) {
+	if el == nil {
+		return module.SessionView{}, nil
+	}
+	return el.fold(ctx, uid, module.SessionView{}, 0, until)
+}
+
+// fold applies the events of the session in (after, until] to the state.
+func (el *EventLog) fold(ctx context.Context, uid string, sv module.SessionView, after, until int64) (module.SessionView, error) {
+	sqlStatement := `SELECT seq, kind, payload, createdat FROM session_event WHERE uid = $1 AND seq > $2 AND ($3::bigint < 0 OR seq <= $3::bigint) ORDER BY seq;`
+	rows, err := el.db.QueryContext(ctx, sqlStatement, uid, after, until)
+	if err != nil {
+		return sv, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			e       = SessionEvent{UID: uid}
+			payload []byte
+		)
+		if err = rows.Scan(&e.Seq, &e.Kind, &payload, &e.At); err != nil {
+			return sv, err
+		}
+		if err = json.Unmarshal(payload, &e); err != nil {
+			return sv, err
+		}
+		applySessionEvent(&sv, e)
+		sv.Seq = e.Seq
+	}
+	return sv, rows.Err()
+}
+
+// replaySession rebuilds the session as of the event until, or the last one
+// if until is 0, under the current game rules. The session is not changed.
+func (s *Service) replaySession(ctx context.Context, id string, until int64) (module.SessionView, error) {
+	if s.events == nil {
+		return module.SessionView{}, status.Error(codes.FailedPrecondition, "the session event log is not enabled")
+	}
+	if until < 0 {
+		return module.SessionView{}, status.Errorf(codes.InvalidArgument, "invalid event sequence number: %d", until)
+	}
+	if until == 0 {
+		until = -1
+	}
+
+	sv, err := s.events.Replay(ctx, id, until)
+	if err != nil {
+		return module.SessionView{}, err
+	}
+	if sv.Seq == 0 {
+		return module.SessionView{}, status.Errorf(codes.NotFound, "no events of session '%s'", id)
+	}
+	return sv, nil
+}
+
+// record appends the event to the log of the session, then applies it to
+// the state of the session.
+func (s *Service) record(ctx context.Context, id string, sv *module.SessionView, e SessionEvent) error {
+	return s.recordIn(ctx, s.db, id, sv, e)
+}
+
+// recordIn records the event with q, which is the transaction of a turn.
+func (s *Service) recordIn(ctx context.Context, q dbtx, id string, sv *module.SessionView, e SessionEvent) error {
+	e.UID = id
+	e.At = time.Now()
+	if err := s.events.Append(ctx, q, &e); err != nil {
+		return err
+	}
+
+	applySessionEvent(sv, e)
+	sv.Seq = e.Seq
+	return s.events.Snapshot(ctx, q, id, e.Seq, *sv, e.Kind == SessionArchived || e.Kind == SessionEnded)
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestApplySessionEvent(t *testing.T) {
+	var (
+		hero  = module.Hero{Name: "PostgreSql", Detail: "Undisputed master", AttackPower: 50, DefensePower: 30, Blood: 100}
+		boss1 = module.Boss{Name: "SQLServer", Detail: "A son of great evil", AttackPower: 40, DefensePower: 30, Blood: 100, Level: 1}
+		boss2 = module.Boss{Name: "MySQL", Detail: "Was once the leader", AttackPower: 40, DefensePower: 40, Blood: 100, Level: 2}
+	)
+
+	events := []SessionEvent{
+		{Kind: SessionStarted, Boss: &boss1},
+		{Kind: HeroSelected, Hero: &hero},
+	}
+	for i := 0; i < 5; i++ {
+		events = append(events, SessionEvent{Kind: FightTurn})
+	}
+	events = append(events,
+		SessionEvent{Kind: LevelUp, Boss: &boss2},
+		SessionEvent{Kind: FightTurn},
+		SessionEvent{Kind: SessionArchived},
+	)
+
+	var live module.SessionView
+	for i := range events {
+		events[i].UID = "1"
+		events[i].Seq = int64(i + 1)
+		events[i].At = time.Date(2021, 3, 11, 18, 25, i, 0, time.UTC)
+		applySessionEvent(&live, events[i])
+	}
+
+	want := module.Session{
+		UID:            "1",
+		HeroName:       "PostgreSql",
+		LiveHeroBlood:  40,
+		LiveBossBlood:  90,
+		CurrentLevel:   2,
+		Score:          60,
+		ArchiveDate:    events[len(events)-1].At,
+		LevelStartedAt: events[len(events)-3].At,
+		ScoreUpdatedAt: events[len(events)-2].At,
+	}
+	if live.Session != want || live.Hero != hero || live.Boss != boss2 {
+		t.Errorf("want session '%v', but get: '%v'", want, live.Session)
+	}
+
+	// replaying the stored events gets the same state
+	var replayed module.SessionView
+	for _, e := range events {
+		payload, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stored := SessionEvent{Seq: e.Seq, UID: e.UID, Kind: e.Kind, At: e.At}
+		if err = json.Unmarshal(payload, &stored); err != nil {
+			t.Fatal(err)
+		}
+		applySessionEvent(&replayed, stored)
+	}
+	if replayed != live {
+		t.Errorf("want replayed state '%v', but get: '%v'", live, replayed)
+	}
+
+	applySessionEvent(&live, SessionEvent{Kind: SessionEnded})
+	if (live != module.SessionView{}) {
+		t.Errorf("want the ended session to be empty, but get: '%v'", live)
+	}
+}
+
+func TestReplaySession(t *testing.T) {
+	db, f := newFakeDB(t)
+	s := &Service{db: db, events: NewEventLog(db)}
+	var (
+		hero    = module.Hero{Name: "PostgreSql", AttackPower: 50, DefensePower: 30, Blood: 100}
+		boss    = module.Boss{Name: "SQLServer", AttackPower: 40, DefensePower: 20, Blood: 100, Level: 1}
+		columns = []string{"seq", "kind", "payload", "createdat"}
+	)
+	payload := func(e SessionEvent) []byte {
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	started := []interface{}{1, string(SessionStarted), payload(SessionEvent{Boss: &boss}), time.Now()}
+	selected := []interface{}{2, string(HeroSelected), payload(SessionEvent{Hero: &hero}), time.Now()}
+	turn := []interface{}{3, string(FightTurn), payload(SessionEvent{}), time.Now()}
+
+	// the fight turn is derived under the current rules
+	f.expect("FROM session_event WHERE uid = $1 AND seq", "1", 0, -1).returns(columns, started, selected, turn)
+	sv, err := s.replaySession(context.Background(), "1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sv.Seq != 3 || sv.Score != 10 || sv.LiveBossBlood != 70 || sv.LiveHeroBlood != 90 {
+		t.Errorf("want the state after the fight turn, but get '%v'", sv)
+	}
+
+	// time travel to the hero selected
+	f.expect("FROM session_event WHERE uid = $1 AND seq", "1", 0, 2).returns(columns, started, selected)
+	if sv, err = s.replaySession(context.Background(), "1", 2); err != nil || sv.Seq != 2 || sv.Score != 0 || sv.HeroName != hero.Name {
+		t.Errorf("want the state after the hero selected, but get '%v', '%v'", sv, err)
+	}
+
+	f.expect("FROM session_event WHERE uid = $1 AND seq", "2", 0, -1).returns(columns)
+	if _, err = s.replaySession(context.Background(), "2", 0); status.Code(err) != codes.NotFound {
+		t.Errorf("want NotFound, but get '%v'", err)
+	}
+	if _, err = s.replaySession(context.Background(), "1", -1); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("want InvalidArgument, but get '%v'", err)
+	}
+}
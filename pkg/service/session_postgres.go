@@ -0,0 +1,59 @@
+package service
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+)
+
+// postgresStore is a write-through session store: the sessions are served
+// from memory, and every change is written to the session table as well.
+type postgresStore struct {
+	SessionStore
+	db *sql.DB
+}
+
+// NewPostgresStore creates a session store backed by the session table.
+func NewPostgresStore(db *sql.DB) SessionStore {
+	return &postgresStore{
+		SessionStore: NewMemoryStore(),
+		db:           db,
+	}
+}
+
+func (ps *postgresStore) Add(id string, sessionView *module.SessionView) error {
+	if err := saveSession(ps.db, sessionView.Session); err != nil {
+		return err
+	}
+	return ps.SessionStore.Add(id, sessionView)
+}
+
+func (ps *postgresStore) Update(id string, sessionView *module.SessionView) error {
+	if err := saveSession(ps.db, sessionView.Session); err != nil {
+		return err
+	}
+	return ps.SessionStore.Update(id, sessionView)
+}
+
+// saveSession inserts the session into the session table, or updates the
+// existing row of the same uid.
+func saveSession(db *sql.DB, session module.Session) error {
+	// the hero is not selected yet for a new session
+	heroName := sql.NullString{
+		String: session.HeroName,
+		Valid:  session.HeroName != "",
+	}
+
+	sqlStatement := `INSERT INTO session(uid, heroname, heroblood, bossblood, currentlevel, score, archivedate) VALUES($1, $2, $3, $4, $5, $6, $7) ON conflict (uid) DO UPDATE SET heroname = $2, heroblood = $3, bossblood = $4, currentlevel = $5, score = $6, archivedate = $7;`
+	_, err := db.Exec(sqlStatement,
+		session.UID,
+		heroName,
+		session.LiveHeroBlood,
+		session.LiveBossBlood,
+		session.CurrentLevel,
+		session.Score,
+		time.Now(),
+	)
+	return err
+}
@@ -7,6 +7,7 @@ import (
 )
 
 func TestDelete(t *testing.T) {
+	sessionStore := NewMemoryStore()
 	err := sessionStore.Add("1", &module.SessionView{})
 	if err != nil {
 		t.Errorf(err.Error())
@@ -17,3 +18,20 @@ func TestDelete(t *testing.T) {
 		t.Errorf("want ErrorNotFound, but get: '%v'", err)
 	}
 }
+
+func TestPostgresStoreRemove(t *testing.T) {
+	db, f := newFakeDB(t)
+	sessionStore := NewPostgresStore(db)
+	f.expect("INSERT INTO session").affects(1)
+	if err := sessionStore.Add("1", &module.SessionView{Session: module.Session{UID: "1"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the archived row of a quit session is kept
+	if err := sessionStore.Remove("1"); err != nil {
+		t.Errorf("want the session removed, but get: '%v'", err)
+	}
+	if _, err := sessionStore.Get("1"); err != ErrorNotFound {
+		t.Errorf("want ErrorNotFound, but get: '%v'", err)
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/authz"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/token"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TokenMetadataKey is the metadata key carrying the session token.
+const TokenMetadataKey = "x-session-token"
+
+// WithTokens makes the service issue a session token on LoadSession, and
+// require it for the other requests of the session. The generations of the
+// tokens are kept in the session_token table, so that a revocation holds in
+// every replica and across the restarts.
+func WithTokens(issuer *token.Issuer) Option {
+	return func(s *Service) {
+		s.tokens = issuer
+	}
+}
+
+// withToken converts the session view for LoadSession, carrying a new
+// session token. The token of an existing session is rotated: the former
+// tokens of the session are revoked.
+func (s *Service) withToken(ctx context.Context, sessionView module.SessionView, rotate bool) (*fight.SessionView, error) {
+	sv := convertSV2FightSV(sessionView)
+	if s.tokens == nil {
+		return sv, nil
+	}
+
+	var (
+		generation int64
+		err        error
+	)
+	if rotate {
+		generation, err = s.revokeTokens(ctx, sessionView.UID)
+	} else {
+		generation, err = s.tokenGeneration(ctx, sessionView.UID)
+	}
+	if err != nil {
+		return &fight.SessionView{}, err
+	}
+
+	if sv.Token, _, err = s.tokens.Issue(sessionView.UID, generation); err != nil {
+		return &fight.SessionView{}, err
+	}
+	return sv, nil
+}
+
+// authorizeResume verifies the caller may get a new token of an existing
+// session: it presents a token of the session, or it is a moderator of any
+// session. Only a new session is issued a token without a credential. The
+// token resumes the session after it expired, until it is revoked.
+func (s *Service) authorizeResume(ctx context.Context, id string) error {
+	if pr, ok := authz.FromContext(ctx); ok && pr.AnySession() {
+		return nil
+	}
+	_, err := s.verifyToken(ctx, id, s.tokens.Parse)
+	return err
+}
+
+// authorize verifies the session token of the request is issued for the uid,
+// and neither expired nor revoked.
+func (s *Service) authorize(ctx context.Context, id string) (token.Claims, error) {
+	return s.verifyToken(ctx, id, s.tokens.Verify)
+}
+
+func (s *Service) verifyToken(ctx context.Context, id string, verify func(string) (token.Claims, error)) (token.Claims, error) {
+	if s.tokens == nil {
+		return token.Claims{}, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(TokenMetadataKey)
+	if len(values) == 0 {
+		return token.Claims{}, status.Errorf(codes.Unauthenticated, "missing '%s' metadata", TokenMetadataKey)
+	}
+
+	claims, err := verify(values[0])
+	if err != nil {
+		return token.Claims{}, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if claims.UID != id {
+		return token.Claims{}, status.Errorf(codes.PermissionDenied, "session token is not issued for session '%s'", id)
+	}
+
+	generation, err := s.tokenGeneration(ctx, id)
+	if err != nil {
+		return token.Claims{}, err
+	}
+	if claims.Generation != generation {
+		return token.Claims{}, status.Error(codes.Unauthenticated, token.ErrRevokedToken.Error())
+	}
+	return claims, nil
+}
+
+// tokenGeneration returns the current generation of the tokens of the uid.
+func (s *Service) tokenGeneration(ctx context.Context, id string) (int64, error) {
+	var generation int64
+	err := s.db.QueryRowContext(ctx, "SELECT generation FROM session_token WHERE uid = $1;", id).Scan(&generation)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return generation, err
+}
+
+// revokeTokens revokes every session token of the uid, including the ones
+// of the player when a moderator clears the session, and returns the
+// generation of the tokens issued from now on.
+func (s *Service) revokeTokens(ctx context.Context, id string) (int64, error) {
+	if s.tokens == nil {
+		return 0, nil
+	}
+
+	var generation int64
+	sqlStatement := `INSERT INTO session_token(uid, generation) VALUES($1, 1) ON conflict (uid) DO UPDATE SET generation = session_token.generation + 1 RETURNING generation;`
+	err := s.db.QueryRowContext(ctx, sqlStatement, id).Scan(&generation)
+	return generation, err
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/authz"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/token"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// withSessionToken returns the context of a request presenting a token of
+// the generation.
+func withSessionToken(t *testing.T, tokens *token.Issuer, id string, generation int64) context.Context {
+	tok, _, err := tokens.Issue(id, generation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(TokenMetadataKey, tok))
+}
+
+// withModerator returns the context of a request of a moderator.
+func withModerator(t *testing.T) context.Context {
+	f, err := ioutil.TempFile("", "policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	hash := sha256.Sum256([]byte("secret"))
+	policy := `{"default_role": "player", "roles": {"player": {"rpcs": ["LoadSession"]}, "moderator": {"inherits": ["player"], "any_session": true}}, "keys": [{"name": "mod", "role": "moderator", "sha256": "` + hex.EncodeToString(hash[:]) + `"}]}`
+	if _, err = f.WriteString(policy); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	p, err := authz.LoadPolicy(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pr, err := p.Authenticate("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return authz.NewContext(context.Background(), pr)
+}
+
+func TestLoadSessionToken(t *testing.T) {
+	db, f := newFakeDB(t)
+	tokens := token.NewIssuer([]byte("secret"), time.Hour)
+	expired := token.NewIssuer([]byte("secret"), -time.Second)
+	s := &Service{db: db, sessions: NewMemoryStore(), tokens: tokens}
+	s.sessions.Add("1", &module.SessionView{Session: module.Session{UID: "1", CurrentLevel: 1}})
+	generation := []string{"generation"}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		expect func()
+		want   codes.Code
+	}{
+		{"no credential", context.Background(), func() {}, codes.Unauthenticated},
+		{"token of another session", withSessionToken(t, tokens, "2", 0), func() {}, codes.PermissionDenied},
+		{"token of the session", withSessionToken(t, tokens, "1", 0), func() {
+			f.expect("SELECT generation FROM session_token", "1").returns(generation)
+			f.expect("INSERT INTO session_token", "1").returns(generation, []interface{}{1})
+		}, codes.OK},
+		{"token rotated", withSessionToken(t, tokens, "1", 0), func() {
+			f.expect("SELECT generation FROM session_token", "1").returns(generation, []interface{}{1})
+		}, codes.Unauthenticated},
+		{"moderator", withModerator(t), func() {
+			f.expect("INSERT INTO session_token", "1").returns(generation, []interface{}{2})
+		}, codes.OK},
+		{"expired token", withSessionToken(t, expired, "1", 2), func() {
+			f.expect("SELECT generation FROM session_token", "1").returns(generation, []interface{}{2})
+			f.expect("INSERT INTO session_token", "1").returns(generation, []interface{}{3})
+		}, codes.OK},
+	}
+	for _, tt := range tests {
+		tt.expect()
+		sv, err := s.LoadSession(tt.ctx, &fight.LoadSessionRequest{Id: "1"})
+		if status.Code(err) != tt.want {
+			t.Errorf("%s: want %v, but get '%v'", tt.name, tt.want, err)
+		}
+		if err == nil && sv.GetToken() == "" {
+			t.Errorf("%s: want a token, but get none", tt.name)
+		}
+	}
+
+	// the other requests refuse an expired token
+	if _, err := s.authorize(withSessionToken(t, expired, "1", 3), "1"); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("want Unauthenticated, but get '%v'", err)
+	}
+
+	// an archived session, e.g. after QUIT, is resumed with its token only
+	session := []string{"uid", "heroname", "herodetails", "heroattack", "herodefense", "heroblood", "liveheroblood", "livebossblood", "currentlevel", "score", "archivedate", "bossname", "bossdetails", "bossattack", "bossdefense", "bossblood"}
+	archived := []interface{}{"3", "PostgreSql", "the elephant", 50, 30, 100, 80, 60, 1, 40, time.Now(), "SQLServer", "the server", 40, 20, 100}
+	f.expect("FROM session_view WHERE sessionid = '3'").returns(session, archived)
+	if _, err := s.LoadSession(context.Background(), &fight.LoadSessionRequest{Id: "3"}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("want Unauthenticated, but get '%v'", err)
+	}
+	if _, err := s.sessions.Get("3"); err != ErrorNotFound {
+		t.Errorf("want the archived session not loaded, but get '%v'", err)
+	}
+	f.expect("FROM session_view WHERE sessionid = '3'").returns(session, archived)
+	f.expect("SELECT generation FROM session_token", "3").returns(generation)
+	f.expect("INSERT INTO session_token", "3").returns(generation, []interface{}{1})
+	if sv, err := s.LoadSession(withSessionToken(t, tokens, "3", 0), &fight.LoadSessionRequest{Id: "3"}); err != nil || sv.GetToken() == "" {
+		t.Errorf("want the archived session resumed with a token, but get '%v'", err)
+	}
+
+	// a new session is issued a token without a credential
+	f.expect("FROM session_view WHERE sessionid = '4'").returns(session)
+	f.expect("FROM boss WHERE level = 1").returns([]string{"name", "details", "attackpower", "defensepower", "blood", "level"}, []interface{}{"SQLServer", "the server", 40, 20, 100, 1})
+	f.expect("SELECT generation FROM session_token", "4").returns(generation)
+	if sv, err := s.LoadSession(context.Background(), &fight.LoadSessionRequest{Id: "4"}); err != nil || sv.GetToken() == "" {
+		t.Errorf("want a new session with a token, but get '%v'", err)
+	}
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+	"k8s.io/klog"
+)
+
+// The snapshot file starts with the magic and the format version, followed
+// by the sessions, each one is the sequence number of its last event and the
+// length of its fight.SessionView message as uvarint, then the message. The
+// sessions of the version 1 files carry no sequence number.
+const (
+	snapshotMagic   = "FSNP"
+	snapshotVersion = uint16(2)
+)
+
+// ErrSnapshotDisabled is returned when no snapshot file is configured.
+var ErrSnapshotDisabled = errors.New("session snapshot is not configured")
+
+// WithSnapshot makes the service dump the online sessions to the file on
+// shutdown, and reload them with Restore.
+func WithSnapshot(path string) Option {
+	return func(s *Service) {
+		s.snapshot = path
+	}
+}
+
+// Snapshot dumps the online sessions to the snapshot file.
+func (s *Service) Snapshot() error {
+	if s.snapshot == "" {
+		return ErrSnapshotDisabled
+	}
+
+	sessionViews, err := s.sessions.List()
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first, a crash never leaves a partial snapshot
+	tmp, err := ioutil.TempFile(filepath.Dir(s.snapshot), filepath.Base(s.snapshot)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	if err = writeSnapshot(w, sessionViews); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	klog.Infof("%d sessions are dumped to the snapshot '%s'", len(sessionViews), s.snapshot)
+	return os.Rename(tmp.Name(), s.snapshot)
+}
+
+// Restore reloads the sessions from the snapshot file, the sessions which
+// do not match the current hero and boss catalog, or whose event log moved
+// on since the snapshot, are loaded from the db instead. The snapshot file is
+// removed afterwards.
+func (s *Service) Restore(ctx context.Context) error {
+	if s.snapshot == "" {
+		return ErrSnapshotDisabled
+	}
+
+	f, err := os.Open(s.snapshot)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sessionViews, err := readSnapshot(bufio.NewReader(f))
+	if err != nil {
+		return err
+	}
+
+	var restored, reloaded int
+	for i := range sessionViews {
+		sv := sessionViews[i]
+		id := sv.UID
+
+		if err = s.leases.Acquire(ctx, id); err != nil {
+			klog.Warningf("session '%s' is not restored: %v", id, err)
+			continue
+		}
+
+		if !s.matchesLog(ctx, sv) || !s.matchesCatalog(ctx, sv) {
+			var ok bool
+			if sv, ok, err = s.loadSession(ctx, id); err != nil {
+				klog.Warningf("session '%s' is not restored: %v", id, err)
+				continue
+			}
+			if !ok {
+				klog.Warningf("session '%s' is not restored: not found in the db", id)
+				continue
+			}
+			reloaded++
+		} else {
+			restored++
+		}
+
+		if err = s.sessions.Add(id, &sv); err != nil {
+			return err
+		}
+	}
+
+	klog.Infof("%d sessions are restored from the snapshot '%s', %d are reloaded from the db", restored, s.snapshot, reloaded)
+	return os.Remove(s.snapshot)
+}
+
+// matchesLog checks the session is the state as of the last event in the
+// log, which moves on if another replica served the session after the
+// snapshot.
+func (s *Service) matchesLog(ctx context.Context, sv module.SessionView) bool {
+	head, err := s.events.Head(ctx, sv.UID)
+	return err == nil && head == sv.Seq
+}
+
+// matchesCatalog checks the hero and the boss of the session against the
+// current ones in the db.
+func (s *Service) matchesCatalog(ctx context.Context, sv module.SessionView) bool {
+	if sv.HeroName != "" {
+		hero, err := s.loadHeroFromDB(sv.HeroName, ctx)
+		if err != nil || hero != sv.Hero {
+			return false
+		}
+	}
+
+	boss, err := s.loadBossFromDB(sv.CurrentLevel, ctx)
+	return err == nil && boss == sv.Boss
+}
+
+func writeSnapshot(w io.Writer, sessionViews []*module.SessionView) error {
+	var header = make([]byte, len(snapshotMagic)+2)
+	copy(header, snapshotMagic)
+	binary.BigEndian.PutUint16(header[len(snapshotMagic):], snapshotVersion)
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	var size = make([]byte, binary.MaxVarintLen64)
+	for _, sv := range sessionViews {
+		data, err := proto.Marshal(convertSV2FightSV(*sv))
+		if err != nil {
+			return err
+		}
+
+		n := binary.PutUvarint(size, uint64(sv.Seq))
+		if _, err = w.Write(size[:n]); err != nil {
+			return err
+		}
+		n = binary.PutUvarint(size, uint64(len(data)))
+		if _, err = w.Write(size[:n]); err != nil {
+			return err
+		}
+		if _, err = w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readSnapshot(r *bufio.Reader) ([]module.SessionView, error) {
+	var header = make([]byte, len(snapshotMagic)+2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(header[:len(snapshotMagic)], []byte(snapshotMagic)) {
+		return nil, errors.New("not a session snapshot")
+	}
+	version := binary.BigEndian.Uint16(header[len(snapshotMagic):])
+	if version < 1 || version > snapshotVersion {
+		return nil, fmt.Errorf("unsupported session snapshot version: '%d'", version)
+	}
+
+	var sessionViews []module.SessionView
+	for {
+		// unknown, the session is loaded from the db
+		var seq int64 = -1
+		if version > 1 {
+			n, err := binary.ReadUvarint(r)
+			if err == io.EOF {
+				return sessionViews, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			seq = int64(n)
+		}
+
+		size, err := binary.ReadUvarint(r)
+		if err == io.EOF && version == 1 {
+			return sessionViews, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var data = make([]byte, size)
+		if _, err = io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+
+		var sv fight.SessionView
+		if err = proto.Unmarshal(data, &sv); err != nil {
+			return nil, err
+		}
+		sessionView := convertFightSV2SV(&sv)
+		sessionView.Seq = seq
+		sessionViews = append(sessionViews, sessionView)
+	}
+}
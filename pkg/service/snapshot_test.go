@@ -0,0 +1,114 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	want := module.SessionView{
+		Session: module.Session{
+			UID:           "1",
+			HeroName:      "PostgreSql",
+			LiveHeroBlood: 80,
+			LiveBossBlood: 40,
+			CurrentLevel:  2,
+			Score:         120,
+			ArchiveDate:   time.Date(2021, 3, 11, 18, 25, 6, 0, time.UTC),
+		},
+		Hero: module.Hero{Name: "PostgreSql", Detail: "Undisputed master", AttackPower: 50, DefensePower: 30, Blood: 100},
+		Boss: module.Boss{Name: "MySQL", Detail: "Was once the leader", AttackPower: 40, DefensePower: 40, Blood: 100, Level: 2},
+		Seq:  7,
+	}
+
+	var buf bytes.Buffer
+	if err := writeSnapshot(&buf, []*module.SessionView{&want, {}}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readSnapshot(bufio.NewReader(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("want 2 sessions, but get: %d", len(got))
+	}
+	if got[0] != want {
+		t.Errorf("want '%v', but get: '%v'", want, got[0])
+	}
+}
+
+func TestSnapshotVersion(t *testing.T) {
+	data := []byte(snapshotMagic + "\x00\x03")
+	if _, err := readSnapshot(bufio.NewReader(bytes.NewReader(data))); err == nil {
+		t.Errorf("want an error for the unsupported version")
+	}
+}
+
+func TestSnapshotVersion1(t *testing.T) {
+	data, err := proto.Marshal(convertSV2FightSV(module.SessionView{Session: module.Session{UID: "1"}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := bytes.NewBufferString(snapshotMagic + "\x00\x01")
+	buf.WriteByte(byte(len(data)))
+	buf.Write(data)
+
+	got, err := readSnapshot(bufio.NewReader(buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the sessions of the version 1 snapshots are loaded from the db
+	if len(got) != 1 || got[0].UID != "1" || got[0].Seq != -1 {
+		t.Errorf("want session '1' with no sequence number, but get: '%v'", got)
+	}
+}
+
+func TestRestoreStale(t *testing.T) {
+	db, f := newFakeDB(t)
+	file, err := ioutil.TempFile("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+
+	boss := module.Boss{Name: "SQLServer", Detail: "the server", AttackPower: 40, DefensePower: 20, Blood: 100, Level: 1}
+	stale := module.SessionView{Session: module.Session{UID: "1", LiveBossBlood: 10, CurrentLevel: 1, Score: 90}, Boss: boss, Seq: 5}
+	current := module.SessionView{Session: module.Session{UID: "2", LiveBossBlood: 60, CurrentLevel: 1, Score: 40}, Boss: boss, Seq: 3}
+	if err = writeSnapshot(file, []*module.SessionView{&stale, &current}); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	s := &Service{db: db, sessions: NewMemoryStore(), events: NewEventLog(db), snapshot: file.Name()}
+	head := []string{"max"}
+	// another replica served the session '1' after the snapshot
+	payload, err := json.Marshal(SessionEvent{Boss: &boss})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.expect("FROM session_event WHERE uid = $1;", "1").returns(head, []interface{}{7})
+	f.expect("FROM session_snapshot WHERE uid", "1").returns([]string{"seq", "state"})
+	f.expect("FROM session_event WHERE uid = $1 AND seq").returns([]string{"seq", "kind", "payload", "createdat"}, []interface{}{7, string(SessionStarted), payload, time.Now()})
+	f.expect("FROM session_event WHERE uid = $1;", "2").returns(head, []interface{}{3})
+	f.expect("FROM boss WHERE level = 1").returns([]string{"name", "details", "attackpower", "defensepower", "blood", "level"}, []interface{}{boss.Name, boss.Detail, boss.AttackPower, boss.DefensePower, boss.Blood, boss.Level})
+	if err = s.Restore(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := s.sessions.Get("1"); err != nil || got.Seq != 7 || got.Score != 0 {
+		t.Errorf("want session '1' loaded from the event log, but get: '%v', '%v'", got, err)
+	}
+	if got, err := s.sessions.Get("2"); err != nil || *got != current {
+		t.Errorf("want session '2' restored from the snapshot, but get: '%v', '%v'", got, err)
+	}
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+)
+
+// turnTx is the transaction of the db writes of a game turn. The caches and
+// the watchers of the rankings are updated once it commits, so that a
+// failed turn leaves no trace.
+type turnTx struct {
+	dbtx
+	committed []func()
+}
+
+// onCommit defers f until the turn commits.
+func (t *turnTx) onCommit(f func()) {
+	t.committed = append(t.committed, f)
+}
+
+// turn runs the writes of f in one transaction, then the functions deferred
+// by f once it commits. Without a db, the components do not persist
+// anything and f runs alone.
+func (s *Service) turn(ctx context.Context, f func(t *turnTx) error) error {
+	t := &turnTx{}
+	if s.db == nil {
+		if err := f(t); err != nil {
+			return err
+		}
+	} else {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		t.dbtx = tx
+		if err = f(t); err != nil {
+			return err
+		}
+		if err = tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range t.committed {
+		f()
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/new-adventure-aerolite/grpc-fight-server/pd/fight"
+	"github.com/new-adventure-aerolite/grpc-fight-server/pkg/module"
+)
+
+func TestGameTurnRollback(t *testing.T) {
+	db, f := newFakeDB(t)
+	s := &Service{
+		db:         db,
+		sessions:   NewMemoryStore(),
+		highScores: NewHighScores(db),
+		windows:    NewWindows(db, time.UTC),
+		records:    NewRecords(db),
+	}
+	want := module.SessionView{
+		Session: module.Session{UID: "1", HeroName: "PostgreSql", LiveHeroBlood: 100, LiveBossBlood: 100, CurrentLevel: 1},
+		Hero:    module.Hero{Name: "PostgreSql", AttackPower: 50, DefensePower: 30, Blood: 100},
+		Boss:    module.Boss{Name: "SQLServer", AttackPower: 40, DefensePower: 20, Blood: 100, Level: 1},
+	}
+	sv := want
+	s.sessions.Add("1", &sv)
+	score := []string{"score"}
+
+	// a failed write rolls back the turn
+	f.expect("BEGIN")
+	f.expect("INSERT INTO high_scores", "1", 10, 1).returns(score, []interface{}{10})
+	f.expect("INSERT INTO window_score", "1", 10, 1).fails(errors.New("window_score is gone"))
+	f.expect("ROLLBACK")
+	if _, err := s.Game(context.Background(), &fight.GameRequest{Id: "1", Type: fight.Type_FIGHT}); err == nil {
+		t.Error("want the turn failed, but get nil")
+	}
+	if got, _ := s.sessions.Get("1"); *got != want {
+		t.Errorf("want the session unchanged, but get '%v'", *got)
+	}
+	if _, ok := s.highScores.best["1"]; ok {
+		t.Errorf("want no best score cached, but get %d", s.highScores.best["1"])
+	}
+
+	// the retry plays the turn once
+	f.expect("BEGIN")
+	f.expect("INSERT INTO high_scores", "1", 10, 1).returns(score, []interface{}{10})
+	f.expect("INSERT INTO window_score", "1", 10, 1).affects(1)
+	f.expect("INSERT INTO hero_score", "PostgreSql", "1", 10, 1).returns(score, []interface{}{10})
+	f.expect("COMMIT")
+	resp, err := s.Game(context.Background(), &fight.GameRequest{Id: "1", Type: fight.Type_FIGHT})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.GetFight(); got.GetScore() != 10 || got.GetHeroBlood() != 90 || got.GetBossBlood() != 70 {
+		t.Errorf("want score 10, hero blood 90 and boss blood 70, but get '%v'", got)
+	}
+	if got, _ := s.sessions.Get("1"); got.Score != 10 || got.LiveBossBlood != 70 {
+		t.Errorf("want the turn applied to the session, but get '%v'", *got)
+	}
+	if s.highScores.best["1"] != 10 || s.records.best[heroPlayer{hero: "PostgreSql", uid: "1"}] != 10 {
+		t.Errorf("want the best scores cached, but get %d, %d", s.highScores.best["1"], s.records.best[heroPlayer{hero: "PostgreSql", uid: "1"}])
+	}
+}
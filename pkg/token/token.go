@@ -0,0 +1,124 @@
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidToken is returned for malformed tokens and tokens not signed by the server.
+	ErrInvalidToken = errors.New("invalid session token")
+	// ErrExpiredToken ...
+	ErrExpiredToken = errors.New("session token expired")
+	// ErrRevokedToken ...
+	ErrRevokedToken = errors.New("session token revoked")
+)
+
+var encoding = base64.RawURLEncoding
+
+// Claims are carried by the token.
+type Claims struct {
+	ID        string    `json:"jti"`
+	UID       string    `json:"uid"`
+	IssuedAt  time.Time `json:"iat"`
+	ExpiresAt time.Time `json:"exp"`
+	// the tokens of an older generation than the current one of the uid
+	// are revoked, the generations are kept by the caller
+	Generation int64 `json:"gen"`
+}
+
+// Issuer issues the session tokens bound to the uid of the session, and
+// verifies them. A token is the encoded claims followed by their HMAC-SHA256
+// signature with the server secret.
+type Issuer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewIssuer creates an issuer of the tokens valid for the ttl.
+func NewIssuer(secret []byte, ttl time.Duration) *Issuer {
+	return &Issuer{
+		secret: secret,
+		ttl:    ttl,
+	}
+}
+
+// NewSecret generates a random secret.
+func NewSecret() ([]byte, error) {
+	var secret = make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// Issue issues a new token of the generation for the uid.
+func (i *Issuer) Issue(uid string, generation int64) (string, Claims, error) {
+	var id = make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", Claims{}, err
+	}
+
+	now := time.Now().UTC()
+	claims := Claims{
+		ID:         hex.EncodeToString(id),
+		UID:        uid,
+		IssuedAt:   now,
+		ExpiresAt:  now.Add(i.ttl),
+		Generation: generation,
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", Claims{}, err
+	}
+
+	return encoding.EncodeToString(payload) + "." + encoding.EncodeToString(i.sign(payload)), claims, nil
+}
+
+// Verify checks the signature and the expiry of the token.
+func (i *Issuer) Verify(token string) (Claims, error) {
+	claims, err := i.Parse(token)
+	if err != nil {
+		return Claims{}, err
+	}
+	if time.Now().After(claims.ExpiresAt) {
+		return Claims{}, ErrExpiredToken
+	}
+	return claims, nil
+}
+
+// Parse checks the signature of the token only, the claims of an expired
+// token are returned as well.
+func (i *Issuer) Parse(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return Claims{}, ErrInvalidToken
+	}
+	payload, err := encoding.DecodeString(parts[0])
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	signature, err := encoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, i.sign(payload)) {
+		return Claims{}, ErrInvalidToken
+	}
+
+	var claims Claims
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func (i *Issuer) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, i.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,51 @@
+package token
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	issuer := NewIssuer([]byte("secret"), time.Hour)
+
+	token, claims, err := issuer.Issue("1", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := issuer.Verify(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.UID != "1" || got.ID != claims.ID || got.Generation != 3 {
+		t.Errorf("want claims '%v', but get: '%v'", claims, got)
+	}
+
+	// signed with another secret
+	if _, err = NewIssuer([]byte("other"), time.Hour).Verify(token); err != ErrInvalidToken {
+		t.Errorf("want ErrInvalidToken, but get: '%v'", err)
+	}
+	if _, err = issuer.Verify(token[1:]); err != ErrInvalidToken {
+		t.Errorf("want ErrInvalidToken, but get: '%v'", err)
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	issuer := NewIssuer([]byte("secret"), -time.Second)
+
+	token, _, err := issuer.Issue("1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = issuer.Verify(token); err != ErrExpiredToken {
+		t.Errorf("want ErrExpiredToken, but get: '%v'", err)
+	}
+
+	// the claims of an expired token are still signed
+	if claims, err := issuer.Parse(token); err != nil || claims.UID != "1" {
+		t.Errorf("want the claims of uid '1', but get: '%v', '%v'", claims, err)
+	}
+	if _, err = NewIssuer([]byte("other"), -time.Second).Parse(token); err != ErrInvalidToken {
+		t.Errorf("want ErrInvalidToken, but get: '%v'", err)
+	}
+}
@@ -11,7 +11,7 @@ INSERT INTO Hero VALUES ('PGAdvancedServer', 'PostgreSql Advanced Server, the gr
 
 
 CREATE TABLE Boss (
-    Name varchar(20) ,
+    Name varchar(20) UNIQUE,
     Detail text check (length(Detail) > 4),
     AttackPower int,
     DefensePower int,
@@ -31,12 +31,203 @@ CREATE TABLE Session(
     HeroName varchar(50) references hero(name),
     HeroBlood int,
     BossBlood int,
-    CurrentLevel int references boss(level),
+    -- follows its boss when the levels are reordered
+    CurrentLevel int references boss(level) ON UPDATE CASCADE,
     Score int,
     ArchiveDate timestamp default now()
 );
 
 
+CREATE TABLE session_lease(
+    UID varchar(100) primary key,
+    Owner varchar(100) NOT NULL,
+    Address varchar(200),
+    ExpiresAt timestamp NOT NULL,
+    HeartbeatAt timestamp NOT NULL default now()
+);
+
+
+-- the session tokens of an older generation are revoked
+CREATE TABLE session_token(
+    UID varchar(100) primary key,
+    Generation bigint NOT NULL
+);
+
+
+CREATE TABLE session_event(
+    Seq bigserial primary key,
+    UID varchar(100) NOT NULL,
+    Kind varchar(30) NOT NULL,
+    Payload jsonb NOT NULL,
+    CreatedAt timestamp NOT NULL default now()
+);
+
+CREATE INDEX session_event_uid_seq ON session_event(uid, seq);
+
+CREATE TABLE session_snapshot(
+    UID varchar(100) primary key,
+    Seq bigint NOT NULL,
+    State jsonb NOT NULL,
+    CreatedAt timestamp NOT NULL default now()
+);
+
+
+CREATE TABLE high_scores(
+    UID varchar(100) primary key,
+    Score int NOT NULL,
+    Level int NOT NULL,
+    UpdatedAt timestamp NOT NULL default now()
+);
+
+CREATE INDEX high_scores_rank ON high_scores(score DESC, level DESC, updatedat, uid);
+
+
+CREATE TABLE hero_score(
+    HeroName varchar(50) references hero(name),
+    UID varchar(100),
+    Score int NOT NULL,
+    Level int NOT NULL,
+    UpdatedAt timestamp NOT NULL default now(),
+    primary key (HeroName, UID)
+);
+
+CREATE INDEX hero_score_rank ON hero_score(heroname, score DESC, level DESC, updatedat, uid);
+
+-- the hero of the clears of a deleted hero is set to null, its hero_score
+-- rows are deleted
+CREATE TABLE level_clear(
+    Level int,
+    UID varchar(100),
+    HeroName varchar(50) references hero(name),
+    -- milliseconds
+    ClearTime bigint NOT NULL,
+    ClearedAt timestamp NOT NULL default now(),
+    primary key (Level, UID)
+);
+
+CREATE INDEX level_clear_rank ON level_clear(level, cleartime, clearedat, uid);
+
+
+CREATE TABLE player(
+    UID varchar(100) primary key,
+    DisplayName varchar(20) NOT NULL,
+    AvatarKey varchar(200),
+    CreatedAt timestamp NOT NULL default now()
+);
+
+-- the display names are unique regardless of the case
+CREATE UNIQUE INDEX player_displayname ON player(lower(displayname));
+
+
+CREATE TABLE friendship(
+    Requester varchar(100),
+    Addressee varchar(100),
+    -- pending or accepted, the declined requests are deleted
+    Status varchar(10) NOT NULL default 'pending',
+    CreatedAt timestamp NOT NULL default now(),
+    AcceptedAt timestamp,
+    primary key (Requester, Addressee),
+    check (Requester <> Addressee)
+);
+
+CREATE INDEX friendship_addressee ON friendship(addressee);
+
+-- the score of a guild is the sum of the high scores of its members
+CREATE TABLE guild(
+    ID bigserial primary key,
+    Name varchar(30) NOT NULL,
+    Score int NOT NULL default 0,
+    ScoreUpdatedAt timestamp NOT NULL default now(),
+    CreatedAt timestamp NOT NULL default now()
+);
+
+CREATE UNIQUE INDEX guild_name ON guild(lower(name));
+CREATE INDEX guild_rank ON guild(score DESC, scoreupdatedat, id);
+
+-- a player is a member of one guild at most
+CREATE TABLE guild_member(
+    UID varchar(100) primary key,
+    GuildID bigint NOT NULL references guild(id) ON DELETE CASCADE,
+    -- owner, officer or member
+    Role varchar(10) NOT NULL default 'member',
+    JoinedAt timestamp NOT NULL default now()
+);
+
+CREATE INDEX guild_member_guild ON guild_member(guildid);
+
+
+-- the applied balance patches, the diff holds the stats of the heros
+-- before and after the patch to revert it
+CREATE TABLE balance_patch(
+    ID bigserial primary key,
+    Patch json NOT NULL,
+    Diff json NOT NULL,
+    AppliedAt timestamp NOT NULL default now(),
+    RevertedAt timestamp
+);
+
+-- every admin request and ClearSession call, the updates and the deletes
+-- are refused by the audit_log_append_only trigger
+CREATE TABLE audit_log(
+    ID bigserial primary key,
+    CreatedAt timestamptz NOT NULL default now(),
+    -- the name of the api key, or the peer address of a request without one
+    Actor varchar(100) NOT NULL,
+    Role varchar(50) NOT NULL,
+    Operation varchar(50) NOT NULL,
+    Arguments json,
+    Before json,
+    After json,
+    Code int NOT NULL,
+    Error text NOT NULL default '',
+    TraceID varchar(32) NOT NULL default ''
+);
+
+CREATE INDEX audit_log_created ON audit_log(createdat DESC);
+CREATE INDEX audit_log_actor ON audit_log(actor, createdat DESC);
+
+CREATE or REPLACE FUNCTION audit_log_append_only() RETURNS TRIGGER AS $$
+    BEGIN
+        RAISE EXCEPTION 'audit_log is append-only';
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+BEFORE UPDATE OR DELETE OR TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE PROCEDURE audit_log_append_only();
+
+CREATE TABLE leaderboard_window(
+    ID bigserial primary key,
+    Kind varchar(10) NOT NULL,
+    Period varchar(20) NOT NULL,
+    StartsAt timestamptz NOT NULL,
+    EndsAt timestamptz NOT NULL,
+    ClosedAt timestamptz,
+    UNIQUE (Kind, Period)
+);
+
+CREATE TABLE window_score(
+    WindowID bigint references leaderboard_window(id),
+    UID varchar(100),
+    Score int NOT NULL,
+    Level int NOT NULL,
+    UpdatedAt timestamp NOT NULL default now(),
+    primary key (WindowID, UID)
+);
+
+CREATE INDEX window_score_rank ON window_score(windowid, score DESC, level DESC, updatedat, uid);
+
+CREATE TABLE window_result(
+    WindowID bigint references leaderboard_window(id),
+    Rank int,
+    UID varchar(100) NOT NULL,
+    Score int NOT NULL,
+    Level int NOT NULL,
+    primary key (WindowID, Rank),
+    UNIQUE (WindowID, UID)
+);
+
+
 UPDATE session
 SET heroblood = value1, bossblood = value2, currentlevel = value3, score = value4, archivedate = value5
 WHERE uid = %s;
@@ -69,19 +260,23 @@ SELECT
 FROM session, hero, boss
 WHERE session.heroname = hero.name and session.currentlevel = boss.level;
 
+-- notifies the changes as {table, action, old, new}, old is null for an
+-- INSERT and new for a DELETE
 CREATE or REPLACE FUNCTION notify_event() RETURNS TRIGGER AS $$
     DECLARE
-        data json;
+        old_row json;
+        new_row json;
         notification json;
     
     BEGIN
-        IF (TG_OP = 'DELETE') THEN
-            data = row_to_json(OLD);
-        ELSE
-            data = row_to_json(NEW);
+        IF (TG_OP <> 'INSERT') THEN
+            old_row = row_to_json(OLD);
+        END IF;
+        IF (TG_OP <> 'DELETE') THEN
+            new_row = row_to_json(NEW);
         END IF;
 
-        notification = json_build_object ('table', TG_TABLE_NAME, 'action', TG_OP, 'data', data);
+        notification = json_build_object ('table', TG_TABLE_NAME, 'action', TG_OP, 'old', old_row, 'new', new_row);
 
         PERFORM pg_notify('events', notification::text);
         RETURN NULL;
@@ -93,3 +288,11 @@ $$ LANGUAGE plpgsql;
 CREATE TRIGGER hero_notify_event
 AFTER INSERT OR UPDATE OR DELETE ON hero
     FOR EACH ROW EXECUTE PROCEDURE notify_event();
+
+CREATE TRIGGER boss_notify_event
+AFTER INSERT OR UPDATE OR DELETE ON boss
+    FOR EACH ROW EXECUTE PROCEDURE notify_event();
+
+CREATE TRIGGER session_notify_event
+AFTER INSERT OR UPDATE OR DELETE ON session
+    FOR EACH ROW EXECUTE PROCEDURE notify_event();